@@ -0,0 +1,245 @@
+// commentsbench seeds the comment threads inside a transaction, loads the pages of
+// different sizes and prints the number of the queries per page. The transaction is rolled
+// back, so it can be run against any database migrated to the latest version.
+//
+//	go run ./cmd/commentsbench --sizes 10,50,100,200
+//
+// It exits with the non-zero status if the number of the queries depends on the page size
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	gormpostgres "github.com/PC-Core/pc-core-backend/internal/database/gormPostgres"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"github.com/joho/godotenv"
+	"github.com/lib/pq"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const ENV_POSTGRES = "PCCORE_POSTGRES_CONN"
+
+// queryCounter is the gorm logger counting the executed queries
+type queryCounter struct {
+	count atomic.Int64
+}
+
+func (q *queryCounter) LogMode(logger.LogLevel) logger.Interface {
+	return q
+}
+
+func (q *queryCounter) Info(context.Context, string, ...interface{}) {}
+
+func (q *queryCounter) Warn(context.Context, string, ...interface{}) {}
+
+func (q *queryCounter) Error(context.Context, string, ...interface{}) {}
+
+func (q *queryCounter) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	q.count.Add(1)
+}
+
+type seeded struct {
+	productID int64
+	viewerID  int64
+	rootIDs   []int64
+}
+
+// seed creates the product with the rated root comments. Every root comment has the answers,
+// the reactions and the medias, the viewer has reacted to all of them
+func seed(tx *gorm.DB, roots int, answers int, reactions int, medias int) (*seeded, error) {
+	var (
+		users     []int64
+		productID int64
+		rootIDs   []int64
+		answerIDs []int64
+	)
+
+	err := tx.Raw(`
+		INSERT INTO Users (name, email, role, passwordhash)
+		SELECT 'bench ' || i, 'bench' || i || '@bench.invalid', 'Default', ''
+		FROM generate_series(1, ?) AS i
+		RETURNING id`, max(roots, reactions),
+	).Scan(&users).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Raw(`
+		INSERT INTO Products (name, price, chars_table_name, chars_id)
+		VALUES ('commentsbench', 100, 'LaptopChars', 0)
+		RETURNING id`,
+	).Scan(&productID).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Raw(`
+		INSERT INTO Comments (user_id, product_id, comment_text, rating, created_at)
+		SELECT u, ?, 'Root comment ' || n, 20 * (1 + n % 5), now() - n * interval '1 second'
+		FROM unnest(?::bigint[]) WITH ORDINALITY AS t(u, n)
+		RETURNING id`, productID, pq.Array(users[:roots]),
+	).Scan(&rootIDs).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Raw(`
+		INSERT INTO Comments (user_id, product_id, comment_text, answer_on, created_at)
+		SELECT ?, ?, 'Answer ' || n, r, now()
+		FROM unnest(?::bigint[]) AS r, generate_series(1, ?) AS n
+		RETURNING id`, users[0], productID, pq.Array(rootIDs), answers,
+	).Scan(&answerIDs).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Exec(`
+		INSERT INTO CommentReactions (user_id, comment_id, ty)
+		SELECT u, c, CASE WHEN u % 4 = 0 THEN 'dislike' ELSE 'like' END
+		FROM unnest(?::bigint[]) AS c, unnest(?::bigint[]) AS u`,
+		pq.Array(append(rootIDs, answerIDs...)), pq.Array(users[:reactions]),
+	).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Exec(`
+		INSERT INTO CommentMedias (owner_id, comment_id, url, thumbnail_url)
+		SELECT c.user_id, c.id, 'commentsbench.png', 'commentsbench_thumb.jpg'
+		FROM Comments c, generate_series(1, ?)
+		WHERE c.id = ANY(?)`, medias, pq.Array(rootIDs),
+	).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Exec(`
+		UPDATE Comments c SET media_ids = (SELECT array_agg(m.id ORDER BY m.id) FROM CommentMedias m WHERE m.comment_id = c.id)
+		WHERE c.id = ANY(?)`, pq.Array(rootIDs),
+	).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &seeded{productID, users[0], rootIDs}, nil
+}
+
+func parseSizes(s string) ([]int, error) {
+	var sizes []int
+
+	for _, part := range strings.Split(s, ",") {
+		size, err := strconv.Atoi(strings.TrimSpace(part))
+
+		if err != nil || size <= 0 {
+			return nil, fmt.Errorf("invalid page size %q", part)
+		}
+
+		sizes = append(sizes, size)
+	}
+
+	return sizes, nil
+}
+
+func main() {
+	sizesFlag := flag.String("sizes", "10,50,100,200", "Comma separated page sizes")
+	answers := flag.Int("answers", 5, "Answers on every root comment")
+	reactions := flag.Int("reactions", 20, "Reactions on every comment")
+	medias := flag.Int("medias", 2, "Medias of every root comment")
+	sort := flag.String("sort", string(models.CommentSortNewest), "Sort mode of the root comments")
+
+	flag.Parse()
+
+	sizes, err := parseSizes(*sizesFlag)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The environment may be set without the file
+	_ = godotenv.Load()
+
+	conn, err := gorm.Open(postgres.Open(os.Getenv(ENV_POSTGRES)), &gorm.Config{Logger: logger.Discard})
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	tx := conn.Begin()
+
+	if tx.Error != nil {
+		log.Fatal(tx.Error)
+	}
+
+	defer tx.Rollback()
+
+	roots := 0
+
+	for _, size := range sizes {
+		roots = max(roots, size)
+	}
+
+	data, err := seed(tx, roots, *answers, max(1, *reactions), *medias)
+
+	if err != nil {
+		log.Fatal("Failed to seed the comments: ", err)
+	}
+
+	counter := &queryCounter{}
+	db := gormpostgres.NewGormPostgresControllerFromDB(tx.Session(&gorm.Session{Logger: counter}))
+
+	fmt.Printf("%8s %14s %14s %16s %14s\n", "size", "root queries", "root time", "answer queries", "answer time")
+
+	var rootQueries, answerQueries []int64
+
+	for _, size := range sizes {
+		counter.count.Store(0)
+		start := time.Now()
+
+		if _, perr := db.GetRootCommentsForProduct(data.productID, &data.viewerID, &inputs.GetRootCommentsInput{Limit: size, Sort: models.CommentSort(*sort)}); perr != nil {
+			log.Fatal("Failed to load the root comments: ", perr)
+		}
+
+		rootTime := time.Since(start)
+		rootQueries = append(rootQueries, counter.count.Load())
+
+		counter.count.Store(0)
+		start = time.Now()
+
+		if _, perr := db.GetAnswersOnComment(data.productID, &data.viewerID, false, data.rootIDs[0], size, 0); perr != nil {
+			log.Fatal("Failed to load the answers: ", perr)
+		}
+
+		answerTime := time.Since(start)
+		answerQueries = append(answerQueries, counter.count.Load())
+
+		fmt.Printf("%8d %14d %14s %16d %14s\n", size, rootQueries[len(rootQueries)-1], rootTime, answerQueries[len(answerQueries)-1], answerTime)
+	}
+
+	for i := 1; i < len(sizes); i++ {
+		if rootQueries[i] != rootQueries[0] || answerQueries[i] != answerQueries[0] {
+			fmt.Println("FAIL: the number of the queries depends on the page size")
+			tx.Rollback()
+			os.Exit(1)
+		}
+	}
+
+	fmt.Println("OK: the number of the queries doesn't depend on the page size")
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -10,11 +11,16 @@ import (
 
 	"github.com/PC-Core/pc-core-backend/docs"
 	"github.com/PC-Core/pc-core-backend/internal/auth/jwt"
+	"github.com/PC-Core/pc-core-backend/internal/contentfilter"
 	"github.com/PC-Core/pc-core-backend/internal/controllers"
 	gormpostgres "github.com/PC-Core/pc-core-backend/internal/database/gormPostgres"
 	"github.com/PC-Core/pc-core-backend/internal/helpers"
+	"github.com/PC-Core/pc-core-backend/internal/mailer"
 	"github.com/PC-Core/pc-core-backend/internal/middlewares"
+	"github.com/PC-Core/pc-core-backend/internal/notifications"
+	"github.com/PC-Core/pc-core-backend/internal/pricechanges"
 	inredis "github.com/PC-Core/pc-core-backend/internal/redis"
+	"github.com/PC-Core/pc-core-backend/internal/reservations"
 	"github.com/PC-Core/pc-core-backend/internal/static"
 	"github.com/PC-Core/pc-core-backend/pkg/config"
 	"github.com/gin-contrib/cors"
@@ -31,6 +37,7 @@ const (
 	ENV_REDIS_PASSWORD = "PCCORE_REDIS_PASSWORD"
 	ENV_MINIO_ACCESS   = "MINIO_ACCESS"
 	ENV_MINIO_SECRET   = "MINIO_SECRET"
+	ENV_SMTP_PASSWORD  = "PCCORE_SMTP_PASSWORD"
 )
 
 const SWAGGER_KEY = "swagger"
@@ -55,21 +62,35 @@ func swaggerRegisterCheck() {
 func setupCors(r *gin.Engine, cfg *config.Config) {
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     cfg.AllowCors,
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 }
 
-func MustLoadJWTAuth(path string) *jwt.JWTAuth {
-	key, err := os.ReadFile(path)
+// MustLoadJWTAuth loads the keys from the config. The legacy HMAC key from
+// the path is optional and is kept to verify the tokens issued before the rotation
+func MustLoadJWTAuth(cfg *config.JWTConfig, legacyPath string) *jwt.JWTAuth {
+	var legacy []byte
+
+	if legacyPath != "" {
+		key, err := os.ReadFile(legacyPath)
+
+		if err != nil {
+			panic(err)
+		}
+
+		legacy = key
+	}
+
+	keys, err := jwt.LoadKeySet(cfg, legacy)
 
 	if err != nil {
 		panic(err)
 	}
 
-	return jwt.NewJWTAuth(key)
+	return jwt.NewJWTAuthWithKeys(keys)
 }
 
 func MustSetupRedis(cfg *config.Config) *redis.Client {
@@ -79,6 +100,15 @@ func MustSetupRedis(cfg *config.Config) *redis.Client {
 	})
 }
 
+// MustSetupMailer returns the SMTP mailer or, if the host is not configured, the one writing to the log
+func MustSetupMailer(cfg *config.MailConfig) mailer.Mailer {
+	if cfg.Host == "" {
+		return mailer.NewLogMailer()
+	}
+
+	return mailer.NewSmtpMailer(cfg.Host, cfg.Port, cfg.From, cfg.Username, os.Getenv(ENV_SMTP_PASSWORD))
+}
+
 func MustSetupMinio(config *config.MinIOConn) *static.MinIOClient {
 	client, err := static.NewMinIOClient(config.Ep, os.Getenv(ENV_MINIO_ACCESS), os.Getenv(ENV_MINIO_SECRET), config.Secure, config.Bucket)
 
@@ -99,6 +129,16 @@ func MustSetupMinio(config *config.MinIOConn) *static.MinIOClient {
 	return client
 }
 
+func MustSetupContentFilter(cfg *config.ContentFilterConfig, wd string) *contentfilter.Pipeline {
+	pipeline, err := contentfilter.NewPipelineFromConfig(cfg, wd)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return pipeline
+}
+
 func MustSetupWorkingDir() string {
 	dir := flag.String("working-dir", "./", "The directory containing config files.")
 
@@ -151,30 +191,61 @@ func main() {
 		panic(err)
 	}
 
+	if config.CommentsConfig.Filter.Enabled {
+		db.UseContentFilter(MustSetupContentFilter(&config.CommentsConfig.Filter, wd))
+	}
+
 	if gin.Mode() == gin.DebugMode {
 		configureSwagger(r, config.Addr)
 	}
 
-	auth := MustLoadJWTAuth(os.Getenv(ENV_JWT_KEY))
+	auth := MustLoadJWTAuth(&config.JWTConfig, os.Getenv(ENV_JWT_KEY))
 
 	staticDataController := MustSetupMinio(&config.MinIOConn)
 
 	redis := inredis.NewRedisController(MustSetupRedis(config))
 
-	uc := controllers.NewUserController(r, db, redis, auth)
-	lc := controllers.NewLaptopController(r, db, middlewares.JWTAuthorize(auth), helpers.JWTRoleCast)
-	pc := controllers.NewProductController(r, db)
+	auth.UseRevocationStore(redis)
+
+	middlewares.RequirePrivilegedMfa(config.MfaConfig.RequireForAdmin)
+
+	mail := MustSetupMailer(&config.MailConfig)
+
+	dispatcher := notifications.NewDispatcher(db, &config.NotificationsConfig,
+		notifications.NewEmailChannel(mail, config.NotificationsConfig.UnsubscribeURL),
+		notifications.NewInAppChannel(),
+		notifications.NewWebhookChannel(config.NotificationsConfig.WebhookTimeout, config.NotificationsConfig.UnsubscribeURL),
+	)
+
+	go dispatcher.Run(context.Background())
+	go reservations.NewSweeper(db, config.CheckoutConfig.SweepInterval).Run(context.Background())
+	go pricechanges.NewScheduler(db, config.PriceChangesConfig.ApplyInterval).Run(context.Background())
+
+	uc := controllers.NewUserController(r, db, redis, auth, helpers.JWTPublicUserCaster(auth))
+	lc := controllers.NewLaptopController(r, db, middlewares.JWTAuthorize(auth), helpers.JWTPublicUserCaster(auth))
+	pc := controllers.NewProductController(r, db, middlewares.JWTAuthorize(auth), helpers.JWTPublicUserCaster(auth))
 	ct := controllers.NewCategoryController(r, db)
 	jc := controllers.NewJWTController(r, db, auth)
 	cc := controllers.NewCartController(r, db, redis, helpers.JWTPublicUserCaster(auth), middlewares.JWTAuthorize(auth))
-	prc := controllers.NewProfileController(r, helpers.JWTPublicUserCaster(auth), middlewares.JWTAuthorize(auth))
+	prc := controllers.NewProfileController(r, helpers.JWTPublicUserCaster(auth), middlewares.JWTAuthorize(auth), db, auth, staticDataController, mail, config.MailConfig.VerifyEmailURL)
 	mc := controllers.NewStaticController(r, staticDataController)
-	cpc := controllers.NewCpuController(r, db, middlewares.JWTAuthorize(auth), helpers.JWTRoleCast)
-	comc := controllers.NewCommentController(r, db, middlewares.JWTAuthorize(auth), middlewares.JWTNotRequired(auth), helpers.JWTPublicUserCaster(auth))
+	cpc := controllers.NewCpuController(r, db, middlewares.JWTAuthorize(auth), helpers.JWTPublicUserCaster(auth))
+	comc := controllers.NewCommentController(r, db, middlewares.JWTAuthorize(auth), middlewares.JWTNotRequired(auth), helpers.JWTPublicUserCaster(auth), staticDataController, config.CommentsConfig.PremoderationPeriod, config.CommentsConfig.ReviewEditWindow)
 	rc := controllers.NewReactionsController(r, db, middlewares.JWTAuthorize(auth), helpers.JWTPublicUserCaster(auth))
-	gc := controllers.NewGpuController(r, db, middlewares.JWTAuthorize(auth), helpers.JWTRoleCast)
-	kbc := controllers.NewKeyBoardController(r, db, middlewares.JWTAuthorize(auth), helpers.JWTRoleCast)
-	msc := controllers.NewMouseController(r, db, middlewares.JWTAuthorize(auth), helpers.JWTRoleCast)
+	qc := controllers.NewQuestionsController(r, db, middlewares.JWTAuthorize(auth), middlewares.JWTNotRequired(auth), helpers.JWTPublicUserCaster(auth))
+	gc := controllers.NewGpuController(r, db, middlewares.JWTAuthorize(auth), helpers.JWTPublicUserCaster(auth))
+	kbc := controllers.NewKeyBoardController(r, db, middlewares.JWTAuthorize(auth), helpers.JWTPublicUserCaster(auth))
+	msc := controllers.NewMouseController(r, db, middlewares.JWTAuthorize(auth), helpers.JWTPublicUserCaster(auth))
+	mfc := controllers.NewMfaController(r, db, middlewares.JWTAuthorize(auth), helpers.JWTPublicUserCaster(auth), config.MfaConfig.Issuer)
+	auc := controllers.NewAdminUsersController(r, db, auth, middlewares.JWTAuthorize(auth), helpers.JWTPublicUserCaster(auth))
+	wc := controllers.NewWishlistController(r, db, redis, helpers.JWTPublicUserCaster(auth), middlewares.JWTAuthorize(auth))
+	apc := controllers.NewAdminPromotionsController(r, db, middlewares.JWTAuthorize(auth), helpers.JWTPublicUserCaster(auth))
+	sc := controllers.NewSubscriptionsController(r, db, helpers.JWTPublicUserCaster(auth), middlewares.JWTAuthorize(auth))
+	aic := controllers.NewAdminInventoryController(r, db, middlewares.JWTAuthorize(auth), helpers.JWTPublicUserCaster(auth))
+	aoc := controllers.NewAdminOrdersController(r, db, middlewares.JWTAuthorize(auth), helpers.JWTPublicUserCaster(auth))
+	aprc := controllers.NewAdminPricesController(r, db, middlewares.JWTAuthorize(auth), helpers.JWTPublicUserCaster(auth))
+	modc := controllers.NewModerationController(r, db, middlewares.JWTAuthorize(auth), helpers.JWTPublicUserCaster(auth))
+	chc := controllers.NewCheckoutController(r, db, helpers.JWTPublicUserCaster(auth), middlewares.JWTAuthorize(auth), config.CheckoutConfig.ReservationTTL)
 
 	uc.ApplyRoutes()
 	lc.ApplyRoutes()
@@ -187,9 +258,20 @@ func main() {
 	cpc.ApplyRoutes()
 	comc.ApplyRoutes()
 	rc.ApplyRoutes()
+	qc.ApplyRoutes()
 	gc.ApplyRoutes()
 	kbc.ApplyRoutes()
 	msc.ApplyRoutes()
+	mfc.ApplyRoutes()
+	auc.ApplyRoutes()
+	wc.ApplyRoutes()
+	apc.ApplyRoutes()
+	sc.ApplyRoutes()
+	chc.ApplyRoutes()
+	aic.ApplyRoutes()
+	aoc.ApplyRoutes()
+	aprc.ApplyRoutes()
+	modc.ApplyRoutes()
 
 	r.Run(config.Addr + ":" + strconv.Itoa(config.Port))
 }
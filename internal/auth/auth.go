@@ -11,9 +11,40 @@ type Auth interface {
 	Authentificate(data *models.PublicUser) (*models.AuthData, errors.PCCError)
 	AuthentificateWithDur(data *models.PublicUser, adur time.Duration, rdur time.Duration) (*models.AuthData, errors.PCCError)
 	Authorize(data string) (interface{}, errors.PCCError)
+	// CreateMfaChallenge issues the short-lived token proving that the password
+	// check has passed and the second factor is expected
+	CreateMfaChallenge(userID int) (string, errors.PCCError)
+	// ValidateMfaChallenge returns the ID of the user the challenge was issued for
+	// and the unique ID of the challenge
+	ValidateMfaChallenge(challenge string) (int, string, errors.PCCError)
+	// RevokeUserTokens makes all the tokens of the user issued before now invalid
+	RevokeUserTokens(userID int) errors.PCCError
+	// CreateImpersonationToken issues the short-lived access token for the target
+	// user without any permissions and with the impersonator ID in the claims
+	CreateImpersonationToken(target *models.PublicUser, impersonatorID int) (string, errors.PCCError)
+}
+
+// RevocationStore keeps the moments when the tokens of the users were revoked
+type RevocationStore interface {
+	SetUserTokensRevokedAt(userID int, at time.Time, ttl time.Duration) errors.PCCError
+	// GetUserTokensRevokedAt returns nil if the tokens of the user were never revoked
+	GetUserTokensRevokedAt(userID int) (*time.Time, errors.PCCError)
+}
+
+// Impersonated is implemented by the authorization data
+// which may belong to the impersonation session
+type Impersonated interface {
+	// GetImpersonator returns the ID of the admin viewing as the user or nil
+	GetImpersonator() *int
 }
 
 const (
 	AuthPublicLifetime        = 15 * time.Minute
 	AuthPrivateCookieLifetime = 24 * 30 * time.Hour
+	AuthMfaChallengeLifetime  = 5 * time.Minute
+	AuthImpersonationLifetime = 15 * time.Minute
 )
+
+// MfaChallengeMaxAttempts is the amount of the second factor checks allowed for
+// one challenge. The challenge is also spent after the successful check
+const MfaChallengeMaxAttempts = 5
@@ -13,6 +13,7 @@ const (
 	JE_NOT_VALID_YET_MESSAGE    = "JWT token is not valid yet"
 	JE_UNKNOWN_MESSAGE          = "Unknown error with the JWT token"
 	JE_WRONG_TOKEN_TYPE_MESSAGE = "The token with the wrong type provided. See the provided type in details"
+	JE_REVOKED_MESSAGE          = "JWT token is revoked"
 )
 
 type JwtError struct {
@@ -33,6 +34,17 @@ func NewJwtError(inner error) *JwtError {
 	}
 }
 
+// NewJwtRevokedError is returned for the valid tokens issued
+// before the tokens of the user were revoked
+func NewJwtRevokedError() *JwtError {
+	return &JwtError{
+		nil,
+		ierrors.EC_JWT_TOKEN_REVOKED,
+		ierrors.EK_JWT,
+		JE_REVOKED_MESSAGE,
+	}
+}
+
 func (j *JwtError) Error() string {
 	return j.Message
 }
@@ -0,0 +1,60 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"sort"
+)
+
+// JWK is the public key in the RFC 7517 format
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	// Crv and X are set for the OKP keys (Ed25519)
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	// N and E are set for the RSA keys
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public parts of all the asymmetric verification keys.
+// The HMAC keys are never published
+func (s *KeySet) JWKS() *JWKSet {
+	set := &JWKSet{Keys: make([]JWK, 0)}
+
+	for _, key := range s.verification {
+		jwk, ok := intoJWK(key)
+
+		if ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].Kid < set.Keys[j].Kid
+	})
+
+	return set
+}
+
+func intoJWK(key *Key) (JWK, bool) {
+	enc := base64.RawURLEncoding
+
+	switch pub := key.Public.(type) {
+	case ed25519.PublicKey:
+		return JWK{Kty: "OKP", Kid: key.Kid, Alg: key.Method.Alg(), Use: "sig", Crv: "Ed25519", X: enc.EncodeToString(pub)}, true
+	case *rsa.PublicKey:
+		return JWK{Kty: "RSA", Kid: key.Kid, Alg: key.Method.Alg(), Use: "sig", N: enc.EncodeToString(pub.N.Bytes()), E: enc.EncodeToString(big.NewInt(int64(pub.E)).Bytes())}, true
+	default:
+		return JWK{}, false
+	}
+}
@@ -1,7 +1,8 @@
 package jwt
 
 import (
-	"strconv"
+	"crypto/rand"
+	"encoding/hex"
 	"time"
 
 	"github.com/PC-Core/pc-core-backend/internal/auth"
@@ -19,18 +20,44 @@ func (s StrWrapper) String() string {
 }
 
 type JWTAuth struct {
-	key []byte
+	keys        *KeySet
+	revocations auth.RevocationStore
 }
 
-func NewJWTAuth(key []byte) *JWTAuth {
+// NewJWTAuth creates JWTAuth signing the tokens with the single HMAC key
+func NewJWTAuth(key []byte) (*JWTAuth, error) {
+	keys := NewKeySet()
+
+	if err := keys.Add(NewKey(LegacyKid, JWTTokenCryptoMethod, key, key)); err != nil {
+		return nil, err
+	}
+
+	if err := keys.SetSigning(LegacyKid); err != nil {
+		return nil, err
+	}
+
+	return NewJWTAuthWithKeys(keys), nil
+}
+
+func NewJWTAuthWithKeys(keys *KeySet) *JWTAuth {
 	return &JWTAuth{
-		key,
+		keys, nil,
 	}
 }
 
-func (a *JWTAuth) CreateRefreshToken(id int, rdur time.Duration) (string, errors.PCCError) {
-	token := jwt.NewWithClaims(JWTTokenCryptoMethod, NewJWTRefreshClaimsFromID(id, rdur))
-	jwt, err := token.SignedString(a.key)
+// UseRevocationStore enables the revocation checks. Without the store
+// the tokens stay valid until they expire
+func (a *JWTAuth) UseRevocationStore(store auth.RevocationStore) {
+	a.revocations = store
+}
+
+// JWKS returns the public keys for the services verifying the tokens
+func (a *JWTAuth) JWKS() *JWKSet {
+	return a.keys.JWKS()
+}
+
+func (a *JWTAuth) CreateRefreshToken(id int, mfa bool, rdur time.Duration) (string, errors.PCCError) {
+	jwt, err := a.keys.sign(NewJWTRefreshClaimsFromID(id, mfa, rdur))
 
 	if err != nil {
 		return "", jerrors.JwtErrorCaster(err)
@@ -40,8 +67,40 @@ func (a *JWTAuth) CreateRefreshToken(id int, rdur time.Duration) (string, errors
 }
 
 func (a *JWTAuth) CreateAccessToken(data *models.PublicUser, adur time.Duration) (string, errors.PCCError) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, NewJWTAccessClaimsFromUser(data, adur))
-	jwt, err := token.SignedString(a.key)
+	jwt, err := a.keys.sign(NewJWTAccessClaimsFromUser(data, adur))
+
+	if err != nil {
+		return "", jerrors.JwtErrorCaster(err)
+	}
+
+	return jwt, nil
+}
+
+func (a *JWTAuth) CreateMfaChallenge(userID int) (string, errors.PCCError) {
+	id := make([]byte, 16)
+
+	if _, rerr := rand.Read(id); rerr != nil {
+		return "", errors.NewInternalSecretError()
+	}
+
+	challengeID := hex.EncodeToString(id)
+
+	jwt, err := a.keys.sign(NewJWTMfaChallengeClaimsFromID(userID, challengeID, auth.AuthMfaChallengeLifetime))
+
+	if err != nil {
+		return "", jerrors.JwtErrorCaster(err)
+	}
+
+	return jwt, nil
+}
+
+func (a *JWTAuth) CreateImpersonationToken(target *models.PublicUser, impersonatorID int) (string, errors.PCCError) {
+	claims := NewJWTAccessClaimsFromUser(target, auth.AuthImpersonationLifetime)
+	claims.Permissions = models.Permissions{}
+	claims.Mfa = false
+	claims.Impersonator = &impersonatorID
+
+	jwt, err := a.keys.sign(claims)
 
 	if err != nil {
 		return "", jerrors.JwtErrorCaster(err)
@@ -50,6 +109,40 @@ func (a *JWTAuth) CreateAccessToken(data *models.PublicUser, adur time.Duration)
 	return jwt, nil
 }
 
+// RevokeUserTokens rejects all the access and refresh tokens of the user issued
+// up to now. The record is kept while the longest-living token may be valid
+func (a *JWTAuth) RevokeUserTokens(userID int) errors.PCCError {
+	if a.revocations == nil {
+		return errors.NewInternalSecretError()
+	}
+
+	return a.revocations.SetUserTokensRevokedAt(userID, time.Now(), auth.AuthPrivateCookieLifetime)
+}
+
+// CheckRevoked returns the error if the token issued at iat was revoked
+func (a *JWTAuth) CheckRevoked(userID int, iat *jwt.NumericDate) errors.PCCError {
+	if a.revocations == nil {
+		return nil
+	}
+
+	revokedAt, err := a.revocations.GetUserTokensRevokedAt(userID)
+
+	if err != nil {
+		return err
+	}
+
+	if revokedAt == nil {
+		return nil
+	}
+
+	// iat has the seconds precision, so the tokens issued in the same second are revoked too
+	if iat == nil || !iat.Time.After(revokedAt.Truncate(time.Second)) {
+		return jerrors.NewJwtRevokedError()
+	}
+
+	return nil
+}
+
 func (a *JWTAuth) Authentificate(data *models.PublicUser) (*models.AuthData, errors.PCCError) {
 	return a.AuthentificateWithDur(data, time.Duration(auth.AuthPublicLifetime), time.Duration(auth.AuthPrivateCookieLifetime))
 }
@@ -61,7 +154,7 @@ func (a *JWTAuth) AuthentificateWithDur(data *models.PublicUser, adur time.Durat
 		return nil, err
 	}
 
-	refresh, err := a.CreateRefreshToken(data.ID, rdur)
+	refresh, err := a.CreateRefreshToken(data.ID, data.Mfa, rdur)
 
 	if err != nil {
 		return nil, err
@@ -81,9 +174,7 @@ func (a *JWTAuth) parsePairType(data interface{}) (*outputs.JWTPair, errors.PCCE
 }
 
 func (a *JWTAuth) parseJWT(token string, claims jwt.Claims) (*jwt.Token, errors.PCCError) {
-	result, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
-		return a.key, nil
-	}, jwt.WithValidMethods([]string{JWTTokenCryptoMethod.Name}))
+	result, err := jwt.ParseWithClaims(token, claims, a.keys.keyFunc, jwt.WithValidMethods(a.keys.Methods()))
 
 	if err != nil {
 		return nil, jerrors.JwtErrorCaster(err)
@@ -120,6 +211,26 @@ func (a *JWTAuth) ValidateRefreshJWT(refresh string) (*jwt.Token, errors.PCCErro
 	return validateJWT[*JWTRefreshAuthClaims](refresh, RefreshToken, a, &JWTRefreshAuthClaims{})
 }
 
+func (a *JWTAuth) ValidateMfaChallengeJWT(challenge string) (*jwt.Token, errors.PCCError) {
+	return validateJWT[*JWTMfaChallengeClaims](challenge, MfaChallengeToken, a, &JWTMfaChallengeClaims{})
+}
+
+func (a *JWTAuth) ValidateMfaChallenge(challenge string) (int, string, errors.PCCError) {
+	tk, err := a.ValidateMfaChallengeJWT(challenge)
+
+	if err != nil {
+		return -1, "", err
+	}
+
+	claims, ok := tk.Claims.(*JWTMfaChallengeClaims)
+
+	if !ok || claims.ID == "" {
+		return -1, "", errors.NewInternalSecretError()
+	}
+
+	return claims.UserID, claims.ID, nil
+}
+
 func (a *JWTAuth) Authorize(data string) (interface{}, errors.PCCError) {
 	tk, err := a.ValidateAccessJWT(data)
 
@@ -133,6 +244,21 @@ func (a *JWTAuth) Authorize(data string) (interface{}, errors.PCCError) {
 		return nil, errors.NewInternalSecretError()
 	}
 
+	// The temporary users live in redis and their IDs may match the registered ones
+	if access_claims.Role != models.Temporary {
+		if err := a.CheckRevoked(access_claims.ID, access_claims.IssuedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	// The impersonation token dies with the tokens of the admin who issued it, so
+	// disabling or demoting the admin ends the impersonation sessions too
+	if access_claims.Impersonator != nil {
+		if err := a.CheckRevoked(*access_claims.Impersonator, access_claims.IssuedAt); err != nil {
+			return nil, err
+		}
+	}
+
 	return access_claims, nil
 }
 
@@ -153,17 +279,11 @@ func (a *JWTAuth) CheckAndReissue(token string) (string, errors.PCCError) {
 		return token, nil
 	}
 
-	sub, ierr := tk.Claims.GetSubject()
+	claims, ok := tk.Claims.(*JWTRefreshAuthClaims)
 
-	if ierr != nil {
-		return "", jerrors.JwtErrorCaster(ierr)
-	}
-
-	isub, ierr := strconv.Atoi(sub)
-
-	if ierr != nil {
-		return "", errors.NewAtoiError(ierr)
+	if !ok {
+		return "", errors.NewInternalSecretError()
 	}
 
-	return a.CreateRefreshToken(isub, auth.AuthPrivateCookieLifetime)
+	return a.CreateRefreshToken(claims.UserID, claims.Mfa, auth.AuthPrivateCookieLifetime)
 }
@@ -9,21 +9,27 @@ import (
 )
 
 type JWTAccessAuthClaims struct {
-	ID    int
-	Name  string
-	Email string
-	Role  models.UserRole
-	Type  TokenType
+	ID          int
+	Name        string
+	Email       string
+	Role        models.UserRole
+	Permissions models.Permissions
+	Mfa         bool
+	Type        TokenType
+	// Impersonator is the ID of the admin who issued the token to view as the user
+	Impersonator *int `json:",omitempty"`
 	jwt.RegisteredClaims
 }
 
 func NewJWTAccessClaimsFromUser(data *models.PublicUser, adur time.Duration) *JWTAccessAuthClaims {
 	return &JWTAccessAuthClaims{
-		ID:    data.ID,
-		Name:  data.Name,
-		Email: data.Email,
-		Role:  data.Role,
-		Type:  AccessToken,
+		ID:          data.ID,
+		Name:        data.Name,
+		Email:       data.Email,
+		Role:        data.Role,
+		Permissions: data.Permissions,
+		Mfa:         data.Mfa,
+		Type:        AccessToken,
 		RegisteredClaims: jwt.RegisteredClaims{
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(adur)),
@@ -33,7 +39,16 @@ func NewJWTAccessClaimsFromUser(data *models.PublicUser, adur time.Duration) *JW
 }
 
 func (c *JWTAccessAuthClaims) IntoPublicUser() *models.PublicUser {
-	return models.NewPublicUser(c.ID, c.Name, c.Email, c.Role)
+	pu := models.NewPublicUser(c.ID, c.Name, c.Email, c.Role)
+	pu.Permissions = c.Permissions
+	pu.Mfa = c.Mfa
+	pu.ImpersonatedBy = c.Impersonator
+
+	return pu
+}
+
+func (c *JWTAccessAuthClaims) GetImpersonator() *int {
+	return c.Impersonator
 }
 
 func (t *JWTAccessAuthClaims) GetType() TokenType {
@@ -42,13 +57,17 @@ func (t *JWTAccessAuthClaims) GetType() TokenType {
 
 type JWTRefreshAuthClaims struct {
 	UserID int
-	Type   TokenType
+	// Mfa is true when the session has passed the second factor check. It is
+	// carried over to the access tokens issued with this refresh token
+	Mfa  bool
+	Type TokenType
 	jwt.RegisteredClaims
 }
 
-func NewJWTRefreshClaimsFromID(id int, rdur time.Duration) *JWTRefreshAuthClaims {
+func NewJWTRefreshClaimsFromID(id int, mfa bool, rdur time.Duration) *JWTRefreshAuthClaims {
 	return &JWTRefreshAuthClaims{
 		UserID: id,
+		Mfa:    mfa,
 		Type:   RefreshToken,
 		RegisteredClaims: jwt.RegisteredClaims{
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -60,3 +79,29 @@ func NewJWTRefreshClaimsFromID(id int, rdur time.Duration) *JWTRefreshAuthClaims
 func (t *JWTRefreshAuthClaims) GetType() TokenType {
 	return t.Type
 }
+
+// JWTMfaChallengeClaims are issued after the password check for the users
+// with the MFA enabled. The token is exchanged for the auth data after the
+// TOTP or recovery code check. The unique ID of the token is used to count
+// the attempts to pass the check
+type JWTMfaChallengeClaims struct {
+	UserID int
+	Type   TokenType
+	jwt.RegisteredClaims
+}
+
+func NewJWTMfaChallengeClaimsFromID(id int, challengeID string, dur time.Duration) *JWTMfaChallengeClaims {
+	return &JWTMfaChallengeClaims{
+		UserID: id,
+		Type:   MfaChallengeToken,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        challengeID,
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(dur)),
+		},
+	}
+}
+
+func (t *JWTMfaChallengeClaims) GetType() TokenType {
+	return t.Type
+}
@@ -4,12 +4,13 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-
+// JWTTokenCryptoMethod is the method of the legacy HMAC key
 var JWTTokenCryptoMethod = jwt.SigningMethodHS256
 
 type TokenType string
 
 const (
-	RefreshToken TokenType = "Refresh"
-	AccessToken  TokenType = "Access"
+	RefreshToken      TokenType = "Refresh"
+	AccessToken       TokenType = "Access"
+	MfaChallengeToken TokenType = "MfaChallenge"
 )
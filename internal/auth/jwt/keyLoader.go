@@ -0,0 +1,166 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+
+	"github.com/PC-Core/pc-core-backend/pkg/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// LoadKeySet loads the keys listed in the config. If the legacy HMAC key is
+// provided, it is kept for the verification of the tokens without `kid`, and
+// is used for signing only if there is no signing key in the config
+func LoadKeySet(cfg *config.JWTConfig, legacy []byte) (*KeySet, error) {
+	set := NewKeySet()
+
+	for _, kc := range cfg.Keys {
+		key, err := loadKey(&kc)
+
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %q: %w", kc.Kid, err)
+		}
+
+		if err := set.Add(key); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(legacy) != 0 {
+		if err := set.Add(NewKey(LegacyKid, JWTTokenCryptoMethod, legacy, legacy)); err != nil {
+			return nil, err
+		}
+	}
+
+	signing := cfg.SigningKid
+
+	if signing == "" && len(legacy) != 0 {
+		signing = LegacyKid
+	}
+
+	if err := set.SetSigning(signing); err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+func loadKey(kc *config.JWTKeyConfig) (*Key, error) {
+	method := jwt.GetSigningMethod(kc.Alg)
+
+	if method == nil {
+		return nil, fmt.Errorf("unknown algorithm %q", kc.Alg)
+	}
+
+	var private crypto.Signer
+
+	if kc.PrivateKey != "" {
+		key, err := readPrivateKey(kc.PrivateKey)
+
+		if err != nil {
+			return nil, err
+		}
+
+		private = key
+	}
+
+	var public crypto.PublicKey
+
+	if kc.PublicKey != "" {
+		key, err := readPublicKey(kc.PublicKey)
+
+		if err != nil {
+			return nil, err
+		}
+
+		public = key
+	} else if private != nil {
+		public = private.Public()
+	} else {
+		return nil, fmt.Errorf("neither private nor public key is provided")
+	}
+
+	if err := checkKeyMatchesMethod(method, public); err != nil {
+		return nil, err
+	}
+
+	return NewKey(kc.Kid, method, private, public), nil
+}
+
+func checkKeyMatchesMethod(method jwt.SigningMethod, public crypto.PublicKey) error {
+	switch method.(type) {
+	case *jwt.SigningMethodEd25519:
+		if _, ok := public.(ed25519.PublicKey); ok {
+			return nil
+		}
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		if _, ok := public.(*rsa.PublicKey); ok {
+			return nil
+		}
+	default:
+		return fmt.Errorf("the algorithm %s is not supported for the key files", method.Alg())
+	}
+
+	return fmt.Errorf("the key type does not match the algorithm %s", method.Alg())
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM file", path)
+	}
+
+	return block, nil
+}
+
+func readPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+
+	if !ok {
+		return nil, fmt.Errorf("%s contains an unsupported private key", path)
+	}
+
+	return signer, nil
+}
+
+func readPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
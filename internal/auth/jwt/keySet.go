@@ -0,0 +1,132 @@
+package jwt
+
+import (
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// LegacyKid is the key ID of the HMAC key used before the key rotation was
+// introduced. The tokens signed with it have no `kid` header
+const LegacyKid = ""
+
+// Key is the single key of the KeySet. For the asymmetric methods
+// Private may be nil, so the key can only be used for verification
+type Key struct {
+	Kid     string
+	Method  jwt.SigningMethod
+	Private any
+	Public  any
+}
+
+func NewKey(kid string, method jwt.SigningMethod, private any, public any) *Key {
+	return &Key{
+		kid, method, private, public,
+	}
+}
+
+// KeySet contains the key used to sign the new tokens and
+// all the keys which tokens are still accepted
+type KeySet struct {
+	signing      *Key
+	verification map[string]*Key
+}
+
+func NewKeySet() *KeySet {
+	return &KeySet{
+		nil,
+		make(map[string]*Key),
+	}
+}
+
+// Add registers the key for the verification
+func (s *KeySet) Add(key *Key) error {
+	if _, exists := s.verification[key.Kid]; exists {
+		return fmt.Errorf("the key with kid %q is already registered", key.Kid)
+	}
+
+	s.verification[key.Kid] = key
+
+	return nil
+}
+
+// SetSigning makes the registered key with the kid the signing one
+func (s *KeySet) SetSigning(kid string) error {
+	key, ok := s.verification[kid]
+
+	if !ok {
+		return fmt.Errorf("the key with kid %q is not registered", kid)
+	}
+
+	if key.Private == nil {
+		return fmt.Errorf("the key with kid %q has no private part", kid)
+	}
+
+	s.signing = key
+
+	return nil
+}
+
+func (s *KeySet) Signing() *Key {
+	return s.signing
+}
+
+// Keys returns all the verification keys
+func (s *KeySet) Keys() []*Key {
+	keys := make([]*Key, 0, len(s.verification))
+
+	for _, key := range s.verification {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// Methods returns the names of the algorithms accepted by the set
+func (s *KeySet) Methods() []string {
+	seen := make(map[string]bool)
+	methods := make([]string, 0)
+
+	for _, key := range s.verification {
+		if !seen[key.Method.Alg()] {
+			seen[key.Method.Alg()] = true
+			methods = append(methods, key.Method.Alg())
+		}
+	}
+
+	return methods
+}
+
+// sign signs the token with the current signing key and puts its kid into the header
+func (s *KeySet) sign(claims jwt.Claims) (string, error) {
+	if s.signing == nil {
+		return "", fmt.Errorf("no signing key")
+	}
+
+	token := jwt.NewWithClaims(s.signing.Method, claims)
+
+	if s.signing.Kid != LegacyKid {
+		token.Header["kid"] = s.signing.Kid
+	}
+
+	return token.SignedString(s.signing.Private)
+}
+
+// keyFunc finds the verification key by the `kid` header. The algorithm of
+// the token must match the algorithm of the key, so the public key can never
+// be used as the HMAC secret
+func (s *KeySet) keyFunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	key, ok := s.verification[kid]
+
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %q: %w", kid, jwt.ErrTokenUnverifiable)
+	}
+
+	if t.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("the algorithm %s does not match the key: %w", t.Method.Alg(), jwt.ErrTokenSignatureInvalid)
+	}
+
+	return key.Public, nil
+}
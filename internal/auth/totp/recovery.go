@@ -0,0 +1,42 @@
+package totp
+
+import (
+	"crypto/rand"
+	"strings"
+)
+
+const (
+	// RecoveryCodesAmount is the amount of the recovery codes generated at once
+	RecoveryCodesAmount = 10
+	// recoveryCodeHalf is the length of the each half of the code
+	recoveryCodeHalf = 5
+)
+
+const recoveryAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// GenerateRecoveryCodes creates the one-time codes in format `xxxxx-xxxxx`
+// which can be used instead of the TOTP code
+func GenerateRecoveryCodes(amount int) ([]string, error) {
+	codes := make([]string, 0, amount)
+
+	for i := 0; i < amount; i++ {
+		buf := make([]byte, recoveryCodeHalf*2)
+
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+
+		for j := range buf {
+			buf[j] = recoveryAlphabet[int(buf[j])%len(recoveryAlphabet)]
+		}
+
+		codes = append(codes, string(buf[:recoveryCodeHalf])+"-"+string(buf[recoveryCodeHalf:]))
+	}
+
+	return codes, nil
+}
+
+// NormalizeRecoveryCode makes the user input comparable with the generated code
+func NormalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
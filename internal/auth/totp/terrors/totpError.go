@@ -0,0 +1,64 @@
+package terrors
+
+import (
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+)
+
+const (
+	TE_INVALID_CODE_MESSAGE    = "The provided code is invalid"
+	TE_NOT_ENROLLED_MESSAGE    = "The MFA enrollment was not started"
+	TE_ALREADY_ENABLED_MESSAGE = "The MFA is already enabled"
+	TE_CHALLENGE_SPENT_MESSAGE = "The MFA challenge is spent, please login again"
+)
+
+// TotpError represents the error occured while working with the TOTP codes
+type TotpError struct {
+	Code    errors.ErrorCode
+	Kind    errors.ErrorKind
+	Message string
+}
+
+func newTotpError(code errors.ErrorCode, message string) *TotpError {
+	return &TotpError{
+		code,
+		errors.EK_MFA,
+		message,
+	}
+}
+
+// NewInvalidCodeError is returned when neither TOTP nor recovery code matches
+func NewInvalidCodeError() *TotpError {
+	return newTotpError(errors.EC_MFA_INVALID_CODE, TE_INVALID_CODE_MESSAGE)
+}
+
+// NewNotEnrolledError is returned when the user tries to confirm the MFA without the secret
+func NewNotEnrolledError() *TotpError {
+	return newTotpError(errors.EC_MFA_NOT_ENROLLED, TE_NOT_ENROLLED_MESSAGE)
+}
+
+// NewAlreadyEnabledError is returned when the user tries to enroll the MFA twice
+func NewAlreadyEnabledError() *TotpError {
+	return newTotpError(errors.EC_MFA_ALREADY_ENABLED, TE_ALREADY_ENABLED_MESSAGE)
+}
+
+// NewChallengeSpentError is returned when the challenge was already used or
+// had too many failed attempts
+func NewChallengeSpentError() *TotpError {
+	return newTotpError(errors.EC_MFA_CHALLENGE_SPENT, TE_CHALLENGE_SPENT_MESSAGE)
+}
+
+func (e *TotpError) Error() string {
+	return e.Message
+}
+
+func (e *TotpError) GetErrorKind() errors.ErrorKind {
+	return e.Kind
+}
+
+func (e *TotpError) GetErrorCode() errors.ErrorCode {
+	return e.Code
+}
+
+func (e *TotpError) IntoPublic() *errors.PublicPCCError {
+	return errors.NewPublicPCCError(e.Code, e.Kind, nil, e.Message)
+}
@@ -0,0 +1,115 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// SecretSize is the size of the generated secret in bytes (RFC 4226 recommends 160 bits)
+	SecretSize = 20
+	// Digits is the length of the generated code
+	Digits = 6
+	// Period is the time step of the code
+	Period = 30 * time.Second
+	// Skew is the amount of the neighbour time steps which are also accepted
+	Skew = 1
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret creates a new random base32-encoded secret
+func GenerateSecret() (string, error) {
+	secret := make([]byte, SecretSize)
+
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return encoding.EncodeToString(secret), nil
+}
+
+// ProvisioningURI returns the otpauth:// URI which is rendered as a QR code
+// and scanned by the authenticator application
+func ProvisioningURI(issuer string, account string, secret string) string {
+	label := url.PathEscape(fmt.Sprintf("%s:%s", issuer, account))
+
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(Digits))
+	params.Set("period", fmt.Sprint(int(Period.Seconds())))
+
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, params.Encode())
+}
+
+// GenerateCode returns the code for the provided moment of time
+func GenerateCode(secret string, t time.Time) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+
+	if err != nil {
+		return "", err
+	}
+
+	return hotp(key, uint64(t.Unix()/int64(Period.Seconds()))), nil
+}
+
+// Validate checks the code against the secret allowing the Skew time steps
+// in both directions
+func Validate(secret string, code string, t time.Time) bool {
+	_, ok := ValidateStep(secret, code, t)
+
+	return ok
+}
+
+// ValidateStep works like Validate and also returns the time step the code was
+// generated for. The step is stored to reject the code if it is used again
+func ValidateStep(secret string, code string, t time.Time) (int64, bool) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+
+	if err != nil || len(code) != Digits {
+		return 0, false
+	}
+
+	counter := t.Unix() / int64(Period.Seconds())
+
+	for i := -Skew; i <= Skew; i++ {
+		step := counter + int64(i)
+		expected := hotp(key, uint64(step))
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// hotp implements the RFC 4226 algorithm
+func hotp(key []byte, counter uint64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < Digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", Digits, value%mod)
+}
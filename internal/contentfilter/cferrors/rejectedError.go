@@ -0,0 +1,43 @@
+package cferrors
+
+import (
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+)
+
+const CF_REJECTED_MESSAGE = "The text was rejected by the content filter"
+
+// Violation is the public description of the filter check the text failed
+type Violation struct {
+	Filter  string `json:"filter"`
+	Verdict string `json:"verdict"`
+	Reason  string `json:"reason"`
+}
+
+type RejectedError struct {
+	Violations []Violation
+	Code       errors.ErrorCode
+	Kind       errors.ErrorKind
+}
+
+func NewRejectedError(violations []Violation) *RejectedError {
+	return &RejectedError{
+		violations, errors.EC_CONTENT_REJECTED, errors.EK_CONTENT_FILTER,
+	}
+}
+
+func (e *RejectedError) Error() string {
+	return CF_REJECTED_MESSAGE
+}
+
+func (e *RejectedError) GetErrorCode() errors.ErrorCode {
+	return e.Code
+}
+
+func (e *RejectedError) GetErrorKind() errors.ErrorKind {
+	return e.Kind
+}
+
+// IntoPublic returns all the violations, so the user can fix the text
+func (e *RejectedError) IntoPublic() *errors.PublicPCCError {
+	return errors.NewPublicPCCError(e.Code, e.Kind, e.Violations, CF_REJECTED_MESSAGE)
+}
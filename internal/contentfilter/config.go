@@ -0,0 +1,66 @@
+package contentfilter
+
+import (
+	"path/filepath"
+
+	"github.com/PC-Core/pc-core-backend/pkg/config"
+)
+
+// NewPipelineFromConfig creates the pipeline of the configured filters. The relative
+// wordlist paths are resolved against the dir
+func NewPipelineFromConfig(cfg *config.ContentFilterConfig, dir string) (*Pipeline, error) {
+	var filters []Filter
+
+	verdict, err := ParseVerdict(cfg.Flood.Verdict)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The flood is checked first, so the flooder gets the reason right away
+	if verdict != Allow && cfg.Flood.MaxComments > 0 {
+		filters = append(filters, NewFloodFilter(cfg.Flood.Window, cfg.Flood.MaxComments, verdict))
+	}
+
+	if verdict, err = ParseVerdict(cfg.Profanity.Verdict); err != nil {
+		return nil, err
+	}
+
+	if verdict != Allow && len(cfg.Profanity.Wordlists) > 0 {
+		paths := make([]string, 0, len(cfg.Profanity.Wordlists))
+
+		for _, path := range cfg.Profanity.Wordlists {
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+
+			paths = append(paths, path)
+		}
+
+		words, err := LoadWordlists(paths...)
+
+		if err != nil {
+			return nil, err
+		}
+
+		filters = append(filters, NewProfanityFilter(words, verdict))
+	}
+
+	if verdict, err = ParseVerdict(cfg.Links.Verdict); err != nil {
+		return nil, err
+	}
+
+	if verdict != Allow {
+		filters = append(filters, NewLinksFilter(cfg.Links.MaxLinks, cfg.Links.MaxPhones, cfg.Links.AllowedDomains, verdict))
+	}
+
+	if verdict, err = ParseVerdict(cfg.Duplicates.Verdict); err != nil {
+		return nil, err
+	}
+
+	if verdict != Allow && cfg.Duplicates.Window > 0 {
+		filters = append(filters, NewDuplicatesFilter(cfg.Duplicates.Window, cfg.Duplicates.MinLength, verdict))
+	}
+
+	return NewPipeline(filters...), nil
+}
@@ -0,0 +1,175 @@
+package contentfilter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/PC-Core/pc-core-backend/internal/contentfilter/cferrors"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+)
+
+// Verdict is the decision of the filter. The verdicts are ordered by severity,
+// the pipeline returns the most severe one
+type Verdict string
+
+const (
+	Allow Verdict = "allow"
+	// Mask means the filter replaced the unwanted parts of the text
+	Mask Verdict = "mask"
+	// Hold means the comment is saved but waits for the approval of the moderators
+	Hold   Verdict = "hold"
+	Reject Verdict = "reject"
+)
+
+func (v Verdict) severity() int {
+	switch v {
+	case Mask:
+		return 1
+	case Hold:
+		return 2
+	case Reject:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// ParseVerdict parses the verdict from the config. The empty string is Allow
+func ParseVerdict(s string) (Verdict, error) {
+	switch v := Verdict(s); v {
+	case "":
+		return Allow, nil
+	case Allow, Mask, Hold, Reject:
+		return v, nil
+	default:
+		return "", fmt.Errorf("unknown content filter verdict %q", s)
+	}
+}
+
+// PastComment is the recent comment of the same author
+type PastComment struct {
+	ProductID int64
+	Text      string
+	CreatedAt time.Time
+}
+
+// Comment is the text being checked
+type Comment struct {
+	UserID    int64
+	ProductID int64
+	Text      string
+	// Edited is true when the existing comment is changed
+	Edited bool
+	// Recent are the comments of the author written during the history window of the pipeline
+	Recent []PastComment
+}
+
+// Match is the reason of the filter to apply its verdict
+type Match struct {
+	Filter  string
+	Verdict Verdict
+	Reason  string
+}
+
+func (m *Match) String() string {
+	return m.Filter + ": " + m.Reason
+}
+
+type Filter interface {
+	Name() string
+	// Check returns nil if the comment is fine. The filters with the Mask verdict
+	// replace the text of the comment
+	Check(comment *Comment) *Match
+}
+
+// historyFilter is implemented by the filters checking the recent comments of the author
+type historyFilter interface {
+	HistoryWindow() time.Duration
+}
+
+// Result is the outcome of the pipeline
+type Result struct {
+	Verdict Verdict
+	// Text is the text after masking
+	Text    string
+	Matches []Match
+}
+
+// Flags describe the matches for the moderators
+func (r *Result) Flags() []string {
+	flags := make([]string, 0, len(r.Matches))
+
+	for _, match := range r.Matches {
+		flags = append(flags, match.String())
+	}
+
+	return flags
+}
+
+// Rejection describes all the matches as the error, so the user can fix the text
+func (r *Result) Rejection() *cferrors.RejectedError {
+	violations := make([]cferrors.Violation, 0, len(r.Matches))
+
+	for _, match := range r.Matches {
+		violations = append(violations, cferrors.Violation{
+			Filter:  match.Filter,
+			Verdict: string(match.Verdict),
+			Reason:  match.Reason,
+		})
+	}
+
+	return cferrors.NewRejectedError(violations)
+}
+
+// Pipeline runs the filters one by one. Every filter sees the text masked by the previous ones
+type Pipeline struct {
+	filters []Filter
+	history time.Duration
+}
+
+func NewPipeline(filters ...Filter) *Pipeline {
+	var history time.Duration
+
+	for _, filter := range filters {
+		if hf, ok := filter.(historyFilter); ok && hf.HistoryWindow() > history {
+			history = hf.HistoryWindow()
+		}
+	}
+
+	return &Pipeline{
+		filters, history,
+	}
+}
+
+// HistoryWindow is the period the recent comments of the author should be loaded for
+func (p *Pipeline) HistoryWindow() time.Duration {
+	return p.history
+}
+
+// Run checks the comment by all the filters. The Reject verdict is returned as the error
+// describing all the matches
+func (p *Pipeline) Run(comment *Comment) (*Result, errors.PCCError) {
+	result := &Result{Verdict: Allow}
+
+	for _, filter := range p.filters {
+		match := filter.Check(comment)
+
+		if match == nil || match.Verdict == Allow {
+			continue
+		}
+
+		result.Matches = append(result.Matches, *match)
+
+		if match.Verdict.severity() > result.Verdict.severity() {
+			result.Verdict = match.Verdict
+		}
+	}
+
+	result.Text = comment.Text
+
+	if result.Verdict == Reject {
+		return nil, result.Rejection()
+	}
+
+	return result, nil
+}
@@ -0,0 +1,96 @@
+package contentfilter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestWordlist(t *testing.T) *Wordlist {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	content := "# test words\ndarn\n!darnel\nплох\n"
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	words, err := LoadWordlists(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return words
+}
+
+func TestWordlistMatches(t *testing.T) {
+	words := newTestWordlist(t)
+
+	cases := map[string]bool{
+		"darn":         true,
+		"DAAARN":       true,
+		"d4rn":         true,
+		"darned":       true,
+		"motherdarner": true,
+		"darnel":       false,
+		"undarn":       false,
+		"плохой":       true,
+		"плoхой":       true,
+		"наплохо":      true,
+		"хороший":      false,
+	}
+
+	for word, expected := range cases {
+		if words.Matches(word) != expected {
+			t.Errorf("Matches(%q) = %v, expected %v", word, !expected, expected)
+		}
+	}
+}
+
+func TestLinksFilter(t *testing.T) {
+	filter := NewLinksFilter(0, 0, []string{"pc-core.ru"}, Hold)
+
+	cases := map[string]bool{
+		"see https://shop.pc-core.ru/laptops": false,
+		"see pc-core.ru.":                     false,
+		"buy at www.example.com/cheap":        true,
+		"buy at cheap-laptops.shop now":       true,
+		"call +7 (999) 123-45-67":             true,
+		"the price is 129 990 rubles":         false,
+	}
+
+	for text, expected := range cases {
+		if match := filter.Check(&Comment{Text: text}); (match != nil) != expected {
+			t.Errorf("Check(%q) = %v, expected the match %v", text, match, expected)
+		}
+	}
+}
+
+// TestPipeline checks that the most severe verdict wins and the masked text is
+// returned with it
+func TestPipeline(t *testing.T) {
+	pipeline := NewPipeline(
+		NewProfanityFilter(newTestWordlist(t), Mask),
+		NewLinksFilter(0, 0, nil, Hold),
+	)
+
+	result, err := pipeline.Run(&Comment{Text: "Darn good, see example.com"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Verdict != Hold || result.Text != "D*** good, see example.com" || len(result.Matches) != 2 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	rejecting := NewPipeline(NewProfanityFilter(newTestWordlist(t), Reject))
+
+	if _, err := rejecting.Run(&Comment{Text: "darn"}); err == nil {
+		t.Fatal("the unwanted word is not rejected")
+	}
+
+	if result, err := rejecting.Run(&Comment{Text: "fine"}); err != nil || result.Verdict != Allow {
+		t.Fatalf("the decent text is not allowed: %+v, %v", result, err)
+	}
+}
@@ -0,0 +1,103 @@
+package contentfilter
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// DuplicatesFilter finds the text the author has already posted under the other products.
+// The short texts like "thanks" are not checked
+type DuplicatesFilter struct {
+	window    time.Duration
+	minLength int
+	verdict   Verdict
+}
+
+func NewDuplicatesFilter(window time.Duration, minLength int, verdict Verdict) *DuplicatesFilter {
+	return &DuplicatesFilter{
+		window, minLength, verdict,
+	}
+}
+
+func (f *DuplicatesFilter) Name() string {
+	return "duplicates"
+}
+
+func (f *DuplicatesFilter) HistoryWindow() time.Duration {
+	return f.window
+}
+
+func (f *DuplicatesFilter) Check(comment *Comment) *Match {
+	text := normalizeText(comment.Text)
+
+	if utf8.RuneCountInString(text) < f.minLength {
+		return nil
+	}
+
+	since := time.Now().Add(-f.window)
+	products := make(map[int64]bool)
+
+	for _, past := range comment.Recent {
+		if past.ProductID == comment.ProductID || past.CreatedAt.Before(since) {
+			continue
+		}
+
+		if normalizeText(past.Text) == text {
+			products[past.ProductID] = true
+		}
+	}
+
+	if len(products) == 0 {
+		return nil
+	}
+
+	return &Match{
+		f.Name(), f.verdict, fmt.Sprintf("the same text was posted under %d other products", len(products)),
+	}
+}
+
+// FloodFilter limits the amount of the comments the author can post during the window.
+// The edits are not counted
+type FloodFilter struct {
+	window      time.Duration
+	maxComments int
+	verdict     Verdict
+}
+
+func NewFloodFilter(window time.Duration, maxComments int, verdict Verdict) *FloodFilter {
+	return &FloodFilter{
+		window, maxComments, verdict,
+	}
+}
+
+func (f *FloodFilter) Name() string {
+	return "flood"
+}
+
+func (f *FloodFilter) HistoryWindow() time.Duration {
+	return f.window
+}
+
+func (f *FloodFilter) Check(comment *Comment) *Match {
+	if comment.Edited {
+		return nil
+	}
+
+	since := time.Now().Add(-f.window)
+	count := 0
+
+	for _, past := range comment.Recent {
+		if !past.CreatedAt.Before(since) {
+			count++
+		}
+	}
+
+	if count < f.maxComments {
+		return nil
+	}
+
+	return &Match{
+		f.Name(), f.verdict, fmt.Sprintf("at most %d comments are allowed per %s", f.maxComments, f.window),
+	}
+}
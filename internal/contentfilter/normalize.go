@@ -0,0 +1,70 @@
+package contentfilter
+
+import (
+	"strings"
+	"unicode"
+)
+
+// The latin letters looking like the cyrillic ones. They are used to hide the russian words
+var cyrillicLookalikes = map[rune]rune{
+	'a': 'а', 'b': 'в', 'c': 'с', 'e': 'е', 'h': 'н', 'k': 'к', 'm': 'м',
+	'o': 'о', 'p': 'р', 't': 'т', 'x': 'х', 'y': 'у', 'u': 'и',
+	'0': 'о', '3': 'з', '4': 'ч', '6': 'б', '@': 'а',
+}
+
+var latinLookalikes = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '@': 'a', '$': 's',
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '@' || r == '$'
+}
+
+func isCyrillic(r rune) bool {
+	return unicode.Is(unicode.Cyrillic, r)
+}
+
+// normalizeWord lowercases the word, replaces the lookalike symbols by the letters
+// of the word's script and collapses the repeated letters, so "ХУУУЙ", "xуй" and "хуй"
+// are the same word
+func normalizeWord(word string) string {
+	word = strings.ToLower(word)
+
+	lookalikes := latinLookalikes
+
+	if strings.IndexFunc(word, isCyrillic) >= 0 {
+		lookalikes = cyrillicLookalikes
+	}
+
+	var (
+		b    strings.Builder
+		last rune
+	)
+
+	for _, r := range word {
+		if r == 'ё' {
+			r = 'е'
+		}
+
+		if l, ok := lookalikes[r]; ok {
+			r = l
+		}
+
+		if r == last {
+			continue
+		}
+
+		b.WriteRune(r)
+		last = r
+	}
+
+	return b.String()
+}
+
+// normalizeText keeps only the lowercased words of the text separated by single spaces.
+// The texts differing only in the case and punctuation are the same
+func normalizeText(text string) string {
+	return strings.Join(strings.FieldsFunc(strings.ReplaceAll(strings.ToLower(text), "ё", "е"), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
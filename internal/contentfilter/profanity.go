@@ -0,0 +1,166 @@
+package contentfilter
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// The prefixes forming the derived words from the same root, e.g. "выеб", "наху", "spizd"
+var (
+	russianPrefixes = []string{
+		"", "в", "вз", "вы", "до", "за", "из", "на", "недо", "об", "от", "па", "пере",
+		"по", "под", "при", "про", "раз", "рас", "с", "съ", "у",
+	}
+	englishPrefixes = []string{"", "mother", "bull", "horse", "dip", "dumb"}
+)
+
+// Wordlist contains the normalized stems of the unwanted words. A word matches the stem
+// if it starts with the stem, optionally after a prefix, so all the word forms match
+// the single stem
+type Wordlist struct {
+	stems []string
+	// exceptions are the stems of the decent words starting like the unwanted ones
+	exceptions []string
+}
+
+// LoadWordlists reads the wordlist files. Each line is a stem, lines starting with '!'
+// are the exceptions and lines starting with '#' are the comments
+func LoadWordlists(paths ...string) (*Wordlist, error) {
+	list := &Wordlist{}
+
+	for _, path := range paths {
+		if err := list.load(path); err != nil {
+			return nil, err
+		}
+	}
+
+	return list, nil
+}
+
+func (l *Wordlist) load(path string) error {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "!"):
+			l.exceptions = append(l.exceptions, normalizeWord(line[1:]))
+		default:
+			l.stems = append(l.stems, normalizeWord(line))
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read the wordlist %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Matches reports whether the word is a form of the unwanted word
+func (l *Wordlist) Matches(word string) bool {
+	word = normalizeWord(word)
+
+	for _, exception := range l.exceptions {
+		if strings.HasPrefix(word, exception) {
+			return false
+		}
+	}
+
+	for _, stem := range l.stems {
+		prefixes := englishPrefixes
+
+		if strings.IndexFunc(stem, isCyrillic) >= 0 {
+			prefixes = russianPrefixes
+		}
+
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(word, prefix) && strings.HasPrefix(word[len(prefix):], stem) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ProfanityFilter finds the unwanted words. With the Mask verdict it replaces all
+// the letters of the words except the first one by asterisks
+type ProfanityFilter struct {
+	words   *Wordlist
+	verdict Verdict
+}
+
+func NewProfanityFilter(words *Wordlist, verdict Verdict) *ProfanityFilter {
+	return &ProfanityFilter{
+		words, verdict,
+	}
+}
+
+func (f *ProfanityFilter) Name() string {
+	return "profanity"
+}
+
+func (f *ProfanityFilter) Check(comment *Comment) *Match {
+	var (
+		b     strings.Builder
+		found int
+	)
+
+	text := comment.Text
+
+	for len(text) > 0 {
+		r, size := utf8.DecodeRuneInString(text)
+
+		if !isWordRune(r) {
+			b.WriteString(text[:size])
+			text = text[size:]
+			continue
+		}
+
+		end := strings.IndexFunc(text, func(r rune) bool { return !isWordRune(r) })
+
+		if end < 0 {
+			end = len(text)
+		}
+
+		word := text[:end]
+		text = text[end:]
+
+		if !f.words.Matches(word) {
+			b.WriteString(word)
+			continue
+		}
+
+		found++
+
+		b.WriteRune(r)
+		b.WriteString(strings.Repeat("*", utf8.RuneCountInString(word)-1))
+	}
+
+	if found == 0 {
+		return nil
+	}
+
+	if f.verdict == Mask {
+		comment.Text = b.String()
+	}
+
+	return &Match{
+		f.Name(), f.verdict, fmt.Sprintf("%d unwanted words", found),
+	}
+}
@@ -0,0 +1,99 @@
+package contentfilter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	linkRegexp = regexp.MustCompile(
+		`(?i)(?:https?://|www\.)[^\s]+|[\p{L}\p{N}-]+(?:\.[\p{L}\p{N}-]+)*\.(?:ru|su|рф|com|net|org|info|biz|io|me|xyz|top|shop|site|online|club|store)(?:/[^\s]*|[^\p{L}\p{N}]|$)`,
+	)
+	phoneRegexp = regexp.MustCompile(`\+?\d[\d\s().-]{8,18}\d`)
+)
+
+// LinksFilter finds the links and the phone numbers. The links to the allowed domains
+// and their subdomains are not counted
+type LinksFilter struct {
+	maxLinks       int
+	maxPhones      int
+	allowedDomains []string
+	verdict        Verdict
+}
+
+func NewLinksFilter(maxLinks int, maxPhones int, allowedDomains []string, verdict Verdict) *LinksFilter {
+	domains := make([]string, 0, len(allowedDomains))
+
+	for _, domain := range allowedDomains {
+		domains = append(domains, strings.ToLower(domain))
+	}
+
+	return &LinksFilter{
+		maxLinks, maxPhones, domains, verdict,
+	}
+}
+
+func (f *LinksFilter) Name() string {
+	return "links"
+}
+
+func (f *LinksFilter) Check(comment *Comment) *Match {
+	links := 0
+
+	for _, link := range linkRegexp.FindAllString(comment.Text, -1) {
+		if !f.isAllowed(link) {
+			links++
+		}
+	}
+
+	phones := 0
+
+	for _, phone := range phoneRegexp.FindAllString(comment.Text, -1) {
+		if digits := countDigits(phone); digits >= 10 && digits <= 15 {
+			phones++
+		}
+	}
+
+	switch {
+	case links > f.maxLinks:
+		return &Match{f.Name(), f.verdict, fmt.Sprintf("%d links, %d allowed", links, f.maxLinks)}
+	case phones > f.maxPhones:
+		return &Match{f.Name(), f.verdict, fmt.Sprintf("%d phone numbers, %d allowed", phones, f.maxPhones)}
+	default:
+		return nil
+	}
+}
+
+func (f *LinksFilter) isAllowed(link string) bool {
+	host := strings.ToLower(link)
+	host = strings.TrimPrefix(host, "http://")
+	host = strings.TrimPrefix(host, "https://")
+	host = strings.TrimPrefix(host, "www.")
+	host = strings.TrimRightFunc(host, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+
+	if i := strings.IndexAny(host, "/?#:"); i >= 0 {
+		host = host[:i]
+	}
+
+	for _, domain := range f.allowedDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func countDigits(s string) int {
+	count := 0
+
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			count++
+		}
+	}
+
+	return count
+}
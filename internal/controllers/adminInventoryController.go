@@ -0,0 +1,267 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/PC-Core/pc-core-backend/internal/controllers/conerrors"
+	"github.com/PC-Core/pc-core-backend/internal/database"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/helpers"
+	"github.com/PC-Core/pc-core-backend/internal/middlewares"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"github.com/PC-Core/pc-core-backend/pkg/models/outputs"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminInventoryController struct {
+	engine          *gin.Engine
+	db              database.DbController
+	auth_middleware gin.HandlerFunc
+	pucaster        helpers.PublicUserCaster
+}
+
+func NewAdminInventoryController(engine *gin.Engine, db database.DbController, auth_middleware gin.HandlerFunc, pucaster helpers.PublicUserCaster) *AdminInventoryController {
+	return &AdminInventoryController{
+		engine, db, auth_middleware, pucaster,
+	}
+}
+
+func (c *AdminInventoryController) ApplyRoutes() {
+	warehouses := c.engine.Group("/admin/warehouses", c.auth_middleware, middlewares.RequirePermission(models.PermissionInventoryManage, c.pucaster))
+	{
+		warehouses.GET("/", c.getWarehouses)
+		warehouses.POST("/", c.addWarehouse)
+		warehouses.PUT("/:id", c.updateWarehouse)
+	}
+
+	inventory := c.engine.Group("/admin/inventory", c.auth_middleware, middlewares.RequirePermission(models.PermissionInventoryManage, c.pucaster))
+	{
+		inventory.GET("/movements", c.getMovements)
+		inventory.POST("/receipts", c.recordReceipt)
+		inventory.POST("/transfers", c.transfer)
+		inventory.POST("/write-offs", c.writeOff)
+	}
+}
+
+// Get the warehouses and the pickup points
+// @Summary      Get the warehouses and the pickup points
+// @Tags         admin
+// @Produce      json
+// @Param		 Authorization  header	string	true	"access token"
+// @Success      200  {array}   models.Warehouse
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/warehouses/ [get]
+func (c *AdminInventoryController) getWarehouses(ctx *gin.Context) {
+	warehouses, err := c.db.GetWarehouses()
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, warehouses)
+}
+
+// Add the warehouse or the pickup point
+// @Summary      Add the warehouse or the pickup point
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param		 Authorization  header	string					true	"access token"
+// @Param		 input			body	inputs.WarehouseInput	true	"Warehouse"
+// @Success      201  {object}  models.Warehouse
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/warehouses/ [post]
+func (c *AdminInventoryController) addWarehouse(ctx *gin.Context) {
+	var input inputs.WarehouseInput
+
+	if berr := ctx.ShouldBindBodyWithJSON(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	warehouse, err := c.db.AddWarehouse(&input)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, warehouse)
+}
+
+// Update the warehouse or the pickup point
+// @Summary      Update the warehouse or the pickup point
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param		 Authorization  header	string					true	"access token"
+// @Param		 id				path	int						true	"Warehouse ID"
+// @Param		 input			body	inputs.WarehouseInput	true	"Warehouse"
+// @Success      200  {object}  models.Warehouse
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/warehouses/{id} [put]
+func (c *AdminInventoryController) updateWarehouse(ctx *gin.Context) {
+	id, perr := strconv.Atoi(ctx.Param("id"))
+
+	if perr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(perr))
+		return
+	}
+
+	var input inputs.WarehouseInput
+
+	if berr := ctx.ShouldBindBodyWithJSON(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	warehouse, err := c.db.UpdateWarehouse(id, &input)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, warehouse)
+}
+
+// Get the stock movements
+// @Summary      Get the stock movements, the newest go first
+// @Tags         admin
+// @Produce      json
+// @Param		 Authorization  header	string							true	"access token"
+// @Param		 filter			query	inputs.GetStockMovementsInput	true	"Filter, page and count"
+// @Success      200  {object}  outputs.GetStockMovementsResult
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/inventory/movements [get]
+func (c *AdminInventoryController) getMovements(ctx *gin.Context) {
+	var input inputs.GetStockMovementsInput
+
+	if berr := ctx.ShouldBindQuery(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	start := (input.Page * input.Count) - input.Count
+
+	movements, amount, err := c.db.GetStockMovements(input.ProductID, input.WarehouseID, start, input.Count)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, outputs.NewGetStockMovementsResult(movements, amount, input.Page))
+}
+
+// Record the goods received from the supplier
+// @Summary      Record the goods received from the supplier
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param		 Authorization  header	string						true	"access token"
+// @Param		 input			body	inputs.GoodsReceiptInput	true	"Goods receipt"
+// @Success      201  {array}   models.StockMovement
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/inventory/receipts [post]
+func (c *AdminInventoryController) recordReceipt(ctx *gin.Context) {
+	pu, err := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	var input inputs.GoodsReceiptInput
+
+	if berr := ctx.ShouldBindBodyWithJSON(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	movements, err := c.db.RecordGoodsReceipt(pu.ID, &input)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, movements)
+}
+
+// Transfer the stock between the locations
+// @Summary      Transfer the stock between the locations
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param		 Authorization  header	string						true	"access token"
+// @Param		 input			body	inputs.StockTransferInput	true	"Transfer"
+// @Success      201  {object}  models.StockMovement
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/inventory/transfers [post]
+func (c *AdminInventoryController) transfer(ctx *gin.Context) {
+	pu, err := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	var input inputs.StockTransferInput
+
+	if berr := ctx.ShouldBindBodyWithJSON(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	movement, err := c.db.TransferStock(pu.ID, &input)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, movement)
+}
+
+// Write off the damaged or lost stock. The stock reserved by the checkouts can't be written off
+// @Summary      Write off the damaged or lost stock
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param		 Authorization  header	string					true	"access token"
+// @Param		 input			body	inputs.WriteOffInput	true	"Write-off"
+// @Success      201  {object}  models.StockMovement
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/inventory/write-offs [post]
+func (c *AdminInventoryController) writeOff(ctx *gin.Context) {
+	pu, err := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	var input inputs.WriteOffInput
+
+	if berr := ctx.ShouldBindBodyWithJSON(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	movement, err := c.db.WriteOffStock(pu.ID, &input)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, movement)
+}
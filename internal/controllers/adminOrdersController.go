@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/PC-Core/pc-core-backend/internal/controllers/conerrors"
+	"github.com/PC-Core/pc-core-backend/internal/database"
+	"github.com/PC-Core/pc-core-backend/internal/helpers"
+	"github.com/PC-Core/pc-core-backend/internal/middlewares"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"github.com/PC-Core/pc-core-backend/pkg/models/outputs"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminOrdersController struct {
+	engine          *gin.Engine
+	db              database.DbController
+	auth_middleware gin.HandlerFunc
+	pucaster        helpers.PublicUserCaster
+}
+
+func NewAdminOrdersController(engine *gin.Engine, db database.DbController, auth_middleware gin.HandlerFunc, pucaster helpers.PublicUserCaster) *AdminOrdersController {
+	return &AdminOrdersController{
+		engine, db, auth_middleware, pucaster,
+	}
+}
+
+func (c *AdminOrdersController) ApplyRoutes() {
+	orders := c.engine.Group("/admin/orders", c.auth_middleware, middlewares.RequirePermission(models.PermissionOrdersManage, c.pucaster))
+	{
+		orders.GET("/", c.getOrders)
+		orders.GET("/:id", c.getOrder)
+		orders.POST("/:id/cancel", c.cancelOrder)
+	}
+}
+
+// Get the orders of all the users
+// @Summary      Get the orders of all the users, the newest go first
+// @Tags         admin
+// @Produce      json
+// @Param		 Authorization  header	string						true	"access token"
+// @Param		 filter			query	inputs.GetAdminOrdersInput	true	"Filter, page and count"
+// @Success      200  {object}  outputs.GetOrdersResult
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/orders/ [get]
+func (c *AdminOrdersController) getOrders(ctx *gin.Context) {
+	var input inputs.GetAdminOrdersInput
+
+	if berr := ctx.ShouldBindQuery(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	orders, amount, err := c.db.GetOrders(&input)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, outputs.NewGetOrdersResult(orders, amount, input.Page))
+}
+
+// Get the order of any user
+// @Summary      Get the order of any user
+// @Tags         admin
+// @Produce      json
+// @Param		 Authorization  header	string	true	"access token"
+// @Param		 id				path	int		true	"ID of the order"
+// @Success      200  {object}  models.Order
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/orders/{id} [get]
+func (c *AdminOrdersController) getOrder(ctx *gin.Context) {
+	id, ok := getOrderID(ctx)
+
+	if !ok {
+		return
+	}
+
+	order, err := c.db.GetOrder(id)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, order)
+}
+
+// Cancel the pending order of any user
+// @Summary      Cancel the pending order of any user and release its reservations
+// @Tags         admin
+// @Produce      json
+// @Param		 Authorization  header	string	true	"access token"
+// @Param		 id				path	int		true	"ID of the order"
+// @Success      200  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/orders/{id}/cancel [post]
+func (c *AdminOrdersController) cancelOrder(ctx *gin.Context) {
+	pu, err := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	id, ok := getOrderID(ctx)
+
+	if !ok {
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.CancelOrder(pu.ID, id)) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "ok")
+}
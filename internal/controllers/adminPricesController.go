@@ -0,0 +1,189 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/PC-Core/pc-core-backend/internal/controllers/conerrors"
+	"github.com/PC-Core/pc-core-backend/internal/database"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/helpers"
+	"github.com/PC-Core/pc-core-backend/internal/middlewares"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"github.com/PC-Core/pc-core-backend/pkg/models/outputs"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminPricesController struct {
+	engine          *gin.Engine
+	db              database.DbController
+	auth_middleware gin.HandlerFunc
+	pucaster        helpers.PublicUserCaster
+}
+
+func NewAdminPricesController(engine *gin.Engine, db database.DbController, auth_middleware gin.HandlerFunc, pucaster helpers.PublicUserCaster) *AdminPricesController {
+	return &AdminPricesController{
+		engine, db, auth_middleware, pucaster,
+	}
+}
+
+func (c *AdminPricesController) ApplyRoutes() {
+	group := c.engine.Group("/admin", c.auth_middleware, middlewares.RequirePermission(models.PermissionCatalogWrite, c.pucaster))
+	{
+		group.GET("/price-changes/", c.getScheduledChanges)
+		group.POST("/price-changes/", c.scheduleChange)
+		group.DELETE("/price-changes/:id", c.cancelChange)
+		group.GET("/price-history/:id", c.getPriceHistory)
+	}
+}
+
+// Get the scheduled price changes
+// @Summary      Get the scheduled price changes, the latest go first
+// @Tags         admin
+// @Produce      json
+// @Param		 Authorization  header	string								true	"access token"
+// @Param		 filter			query	inputs.GetScheduledPriceChangesInput	true	"Filter, page and count"
+// @Success      200  {object}  outputs.GetScheduledPriceChangesResult
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/price-changes/ [get]
+func (c *AdminPricesController) getScheduledChanges(ctx *gin.Context) {
+	var input inputs.GetScheduledPriceChangesInput
+
+	if berr := ctx.ShouldBindQuery(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	start := (input.Page * input.Count) - input.Count
+
+	changes, amount, err := c.db.GetScheduledPriceChanges(input.ProductID, input.Status, start, input.Count)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, outputs.NewGetScheduledPriceChangesResult(changes, amount, input.Page))
+}
+
+// Schedule the price change. A sale is scheduled as two changes: the discounted price and the regular one
+// @Summary      Schedule the price change
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param		 Authorization  header	string								true	"access token"
+// @Param		 input			body	inputs.ScheduledPriceChangeInput	true	"Price change"
+// @Success      201  {object}  models.ScheduledPriceChange
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/price-changes/ [post]
+func (c *AdminPricesController) scheduleChange(ctx *gin.Context) {
+	pu, err := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	var input inputs.ScheduledPriceChangeInput
+
+	if berr := ctx.ShouldBindBodyWithJSON(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, checkScheduledPriceChangeInput(&input)) {
+		return
+	}
+
+	change, err := c.db.AddScheduledPriceChange(pu.ID, &input)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, change)
+}
+
+func checkScheduledPriceChangeInput(input *inputs.ScheduledPriceChangeInput) errors.PCCError {
+	details := make([]conerrors.ValError, 0)
+
+	if input.Price.Minor < 0 {
+		details = append(details, conerrors.ValError{Field: "Price", Tag: "min", Reason: conerrors.VFR_UNKNOWN})
+	}
+
+	if !input.ApplyAt.After(time.Now()) {
+		details = append(details, conerrors.ValError{Field: "ApplyAt", Tag: "gt", Reason: conerrors.VFR_UNKNOWN})
+	}
+
+	if len(details) != 0 {
+		return conerrors.NewBindValidationError(details)
+	}
+
+	return nil
+}
+
+// Cancel the price change which was not applied yet
+// @Summary      Cancel the scheduled price change
+// @Tags         admin
+// @Produce      json
+// @Param		 Authorization  header	string	true	"access token"
+// @Param		 id				path	int		true	"Price change ID"
+// @Success      200  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/price-changes/{id} [delete]
+func (c *AdminPricesController) cancelChange(ctx *gin.Context) {
+	id, perr := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if perr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(perr))
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.CancelScheduledPriceChange(id)) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "ok")
+}
+
+// Get the full price history of the product with the authors of the changes
+// @Summary      Get the full price history of the product
+// @Tags         admin
+// @Produce      json
+// @Param		 Authorization  header	string						true	"access token"
+// @Param		 id				path	uint64						true	"Product ID"
+// @Param		 period			query	inputs.GetPriceHistoryInput	false	"The period in RFC 3339"
+// @Success      200  {array}   models.PriceHistoryEntry
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/price-history/{id} [get]
+func (c *AdminPricesController) getPriceHistory(ctx *gin.Context) {
+	id, perr := strconv.ParseUint(ctx.Param("id"), 10, 64)
+
+	if perr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(perr))
+		return
+	}
+
+	var input inputs.GetPriceHistoryInput
+
+	if berr := ctx.ShouldBindQuery(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	history, err := c.db.GetPriceHistory(id, input.From, input.To)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, history)
+}
@@ -0,0 +1,246 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/PC-Core/pc-core-backend/internal/controllers/conerrors"
+	"github.com/PC-Core/pc-core-backend/internal/database"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/helpers"
+	"github.com/PC-Core/pc-core-backend/internal/middlewares"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"github.com/PC-Core/pc-core-backend/pkg/models/outputs"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminPromotionsController struct {
+	engine          *gin.Engine
+	db              database.DbController
+	auth_middleware gin.HandlerFunc
+	pucaster        helpers.PublicUserCaster
+}
+
+func NewAdminPromotionsController(engine *gin.Engine, db database.DbController, auth_middleware gin.HandlerFunc, pucaster helpers.PublicUserCaster) *AdminPromotionsController {
+	return &AdminPromotionsController{
+		engine, db, auth_middleware, pucaster,
+	}
+}
+
+func (c *AdminPromotionsController) ApplyRoutes() {
+	gr := c.engine.Group("/admin/promotions", c.auth_middleware, middlewares.RequirePermission(models.PermissionPromotionsManage, c.pucaster))
+	{
+		gr.GET("/", c.getPromotions)
+		gr.POST("/", c.addPromotion)
+		gr.GET("/:id", c.getPromotion)
+		gr.PUT("/:id", c.updatePromotion)
+		gr.DELETE("/:id", c.deletePromotion)
+	}
+}
+
+// checkPromotionInput validates the fields which depend on the kind and the scope
+func checkPromotionInput(input *inputs.PromotionInput) errors.PCCError {
+	details := make([]conerrors.ValError, 0)
+
+	switch input.Kind {
+	case models.PromotionPercent:
+		if input.Percent == 0 {
+			details = append(details, conerrors.ValError{Field: "Percent", Tag: "required", Reason: conerrors.VFR_REQUIRED})
+		}
+	case models.PromotionFixed:
+		if input.Amount == nil || input.Amount.Minor <= 0 {
+			details = append(details, conerrors.ValError{Field: "Amount", Tag: "required", Reason: conerrors.VFR_REQUIRED})
+		}
+	}
+
+	if input.Amount != nil && input.MinCart != nil && input.Amount.Currency != input.MinCart.Currency {
+		details = append(details, conerrors.ValError{Field: "MinCart", Tag: "currency", Reason: conerrors.VFR_UNKNOWN})
+	}
+
+	if input.MinCart != nil && input.MinCart.Minor < 0 {
+		details = append(details, conerrors.ValError{Field: "MinCart", Tag: "min", Reason: conerrors.VFR_UNKNOWN})
+	}
+
+	targets := map[models.PromotionScope]int{
+		models.PromotionScopeProducts:    len(input.ProductIDs),
+		models.PromotionScopeCategories:  len(input.CategoryIDs),
+		models.PromotionScopeCharsTables: len(input.CharsTables),
+	}
+
+	if count, ok := targets[input.Scope]; ok && count == 0 {
+		details = append(details, conerrors.ValError{Field: "Scope", Tag: "required", Reason: conerrors.VFR_REQUIRED})
+	}
+
+	if input.StartsAt != nil && input.EndsAt != nil && !input.EndsAt.After(*input.StartsAt) {
+		details = append(details, conerrors.ValError{Field: "EndsAt", Tag: "gtfield", Reason: conerrors.VFR_UNKNOWN})
+	}
+
+	if len(details) != 0 {
+		return conerrors.NewBindValidationError(details)
+	}
+
+	return nil
+}
+
+func (c *AdminPromotionsController) bindPromotionInput(ctx *gin.Context) (*inputs.PromotionInput, errors.PCCError) {
+	var input inputs.PromotionInput
+
+	if berr := ctx.ShouldBindBodyWithJSON(&input); berr != nil {
+		return nil, conerrors.BindErrorCast(berr)
+	}
+
+	if err := checkPromotionInput(&input); err != nil {
+		return nil, err
+	}
+
+	return &input, nil
+}
+
+// Get the promotions
+// @Summary      Get the promotions
+// @Tags         admin
+// @Produce      json
+// @Param		 Authorization  header	string						true	"access token"
+// @Param		 page			query	inputs.GetPromotionsInput	true	"Page and count"
+// @Success      200  {object}  outputs.GetPromotionsResult
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/promotions/ [get]
+func (c *AdminPromotionsController) getPromotions(ctx *gin.Context) {
+	var input inputs.GetPromotionsInput
+
+	if berr := ctx.ShouldBindQuery(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	start := (input.Page * input.Count) - input.Count
+
+	promotions, amount, err := c.db.GetPromotions(start, input.Count)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, outputs.NewGetPromotionsResult(promotions, amount, input.Page))
+}
+
+// Add the promotion
+// @Summary      Add the promotion
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param		 Authorization  header	string					true	"access token"
+// @Param		 input			body	inputs.PromotionInput	true	"Promotion"
+// @Success      201  {object}  models.Promotion
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/promotions/ [post]
+func (c *AdminPromotionsController) addPromotion(ctx *gin.Context) {
+	input, err := c.bindPromotionInput(ctx)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	promotion, err := c.db.AddPromotion(input)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, promotion)
+}
+
+// Get the promotion by ID
+// @Summary      Get the promotion by ID
+// @Tags         admin
+// @Produce      json
+// @Param		 Authorization  header	string	true	"access token"
+// @Param		 id				path	int		true	"Promotion ID"
+// @Success      200  {object}  models.Promotion
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/promotions/{id} [get]
+func (c *AdminPromotionsController) getPromotion(ctx *gin.Context) {
+	id, perr := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if perr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(perr))
+		return
+	}
+
+	promotion, err := c.db.GetPromotionByID(id)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, promotion)
+}
+
+// Update the promotion. The carts the promotion was applied to get the new rules
+// @Summary      Update the promotion
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param		 Authorization  header	string					true	"access token"
+// @Param		 id				path	int						true	"Promotion ID"
+// @Param		 input			body	inputs.PromotionInput	true	"Promotion"
+// @Success      200  {object}  models.Promotion
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/promotions/{id} [put]
+func (c *AdminPromotionsController) updatePromotion(ctx *gin.Context) {
+	id, perr := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if perr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(perr))
+		return
+	}
+
+	input, err := c.bindPromotionInput(ctx)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	promotion, err := c.db.UpdatePromotion(id, input)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, promotion)
+}
+
+// Delete the promotion which was never used in the orders. The used promotions should be deactivated
+// @Summary      Delete the promotion
+// @Tags         admin
+// @Produce      json
+// @Param		 Authorization  header	string	true	"access token"
+// @Param		 id				path	int		true	"Promotion ID"
+// @Success      200  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/promotions/{id} [delete]
+func (c *AdminPromotionsController) deletePromotion(ctx *gin.Context) {
+	id, perr := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if perr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(perr))
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.DeletePromotion(id)) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "ok")
+}
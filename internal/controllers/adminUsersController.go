@@ -0,0 +1,304 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/PC-Core/pc-core-backend/internal/auth"
+	"github.com/PC-Core/pc-core-backend/internal/controllers/conerrors"
+	"github.com/PC-Core/pc-core-backend/internal/database"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/helpers"
+	"github.com/PC-Core/pc-core-backend/internal/middlewares"
+	"github.com/PC-Core/pc-core-backend/internal/middlewares/merrors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"github.com/PC-Core/pc-core-backend/pkg/models/outputs"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminUsersController struct {
+	engine          *gin.Engine
+	db              database.DbController
+	auth            auth.Auth
+	auth_middleware gin.HandlerFunc
+	pucaster        helpers.PublicUserCaster
+}
+
+func NewAdminUsersController(engine *gin.Engine, db database.DbController, auth auth.Auth, auth_middleware gin.HandlerFunc, pucaster helpers.PublicUserCaster) *AdminUsersController {
+	return &AdminUsersController{
+		engine, db, auth, auth_middleware, pucaster,
+	}
+}
+
+func (c *AdminUsersController) ApplyRoutes() {
+	manage := middlewares.RequirePermission(models.PermissionUsersManage, c.pucaster)
+
+	gr := c.engine.Group("/admin", c.auth_middleware)
+	{
+		gr.GET("/roles", manage, c.getRoles)
+		gr.GET("/users", manage, c.getUsers)
+		gr.GET("/users/:id", manage, c.getUser)
+		gr.PUT("/users/:id/role", middlewares.RequirePermission(models.PermissionRolesAssign, c.pucaster), c.setUserRole)
+		gr.POST("/users/:id/disable", manage, c.disableUser)
+		gr.POST("/users/:id/enable", manage, c.enableUser)
+		gr.POST("/users/:id/impersonate", manage, c.impersonateUser)
+	}
+}
+
+// getTarget loads the user the action is applied to. The staff members can only be
+// managed by those who can assign the roles, so Support can't disable an Admin
+func (c *AdminUsersController) getTarget(ctx *gin.Context, actor *models.PublicUser) (*models.User, errors.PCCError) {
+	id, perr := strconv.Atoi(ctx.Param("id"))
+
+	if perr != nil {
+		return nil, errors.NewAtoiError(perr)
+	}
+
+	if id == actor.ID {
+		return nil, conerrors.NewAdminSelfActionError()
+	}
+
+	target, err := c.db.GetUserByID(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(target.Permissions) != 0 && !actor.Permissions.Has(models.PermissionRolesAssign) {
+		return nil, merrors.NewMissingPermissionError(models.PermissionRolesAssign, actor.Role)
+	}
+
+	return target, nil
+}
+
+// Get the roles with their permissions
+// @Summary      Get the roles with their permissions
+// @Tags         admin
+// @Produce      json
+// @Param		 Authorization  header	string	true	"access token"
+// @Success      200  {array}   models.Role
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/roles [get]
+func (c *AdminUsersController) getRoles(ctx *gin.Context) {
+	roles, err := c.db.GetRoles()
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, roles)
+}
+
+// Get the users filtered by the role and the email
+// @Summary      Get the users filtered by the role and the email
+// @Tags         admin
+// @Produce      json
+// @Param		 Authorization  header	string					true	"access token"
+// @Param		 filter			query	inputs.GetUsersInput	true	"Filters, page and count"
+// @Success      200  {object}  outputs.GetUsersResult
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/users [get]
+func (c *AdminUsersController) getUsers(ctx *gin.Context) {
+	var input inputs.GetUsersInput
+
+	if berr := ctx.ShouldBindQuery(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	start := (input.Page * input.Count) - input.Count
+
+	users, amount, err := c.db.GetUsers(input.Role, input.Email, start, input.Count)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, outputs.NewGetUsersResult(users, amount, input.Page))
+}
+
+// Get the user by ID
+// @Summary      Get the user by ID
+// @Tags         admin
+// @Produce      json
+// @Param		 Authorization  header	string	true	"access token"
+// @Param		 id				path	int		true	"User ID"
+// @Success      200  {object}  models.User
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/users/{id} [get]
+func (c *AdminUsersController) getUser(ctx *gin.Context) {
+	id, perr := strconv.Atoi(ctx.Param("id"))
+
+	if perr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(perr))
+		return
+	}
+
+	user, err := c.db.GetUserByID(id)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
+
+// Change the role of the user. The active tokens of the user are revoked,
+// so the new permissions are applied on the next login. The impersonation
+// sessions started by the user end as well
+// @Summary      Change the role of the user
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param		 Authorization  header	string					true	"access token"
+// @Param		 id				path	int						true	"User ID"
+// @Param		 input			body	inputs.SetUserRoleInput	true	"New role"
+// @Success      200  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/users/{id}/role [put]
+func (c *AdminUsersController) setUserRole(ctx *gin.Context) {
+	actor, err := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	var input inputs.SetUserRoleInput
+
+	if berr := ctx.ShouldBindBodyWithJSON(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	target, err := c.getTarget(ctx, actor)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.SetUserRole(actor.ID, target.ID, input.Role)) {
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.auth.RevokeUserTokens(target.ID)) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "ok")
+}
+
+// Disable the user. The active tokens of the user and the impersonation
+// sessions started by the user are revoked
+// @Summary      Disable the user
+// @Tags         admin
+// @Produce      json
+// @Param		 Authorization  header	string	true	"access token"
+// @Param		 id				path	int		true	"User ID"
+// @Success      200  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/users/{id}/disable [post]
+func (c *AdminUsersController) disableUser(ctx *gin.Context) {
+	c.setUserDisabled(ctx, true)
+}
+
+// Enable the disabled user
+// @Summary      Enable the disabled user
+// @Tags         admin
+// @Produce      json
+// @Param		 Authorization  header	string	true	"access token"
+// @Param		 id				path	int		true	"User ID"
+// @Success      200  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/users/{id}/enable [post]
+func (c *AdminUsersController) enableUser(ctx *gin.Context) {
+	c.setUserDisabled(ctx, false)
+}
+
+func (c *AdminUsersController) setUserDisabled(ctx *gin.Context, disabled bool) {
+	actor, err := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	target, err := c.getTarget(ctx, actor)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.SetUserDisabled(actor.ID, target.ID, disabled)) {
+		return
+	}
+
+	if disabled {
+		if CheckErrorAndWriteBadRequest(ctx, c.auth.RevokeUserTokens(target.ID)) {
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, "ok")
+}
+
+// View as the user. The issued token is read-only, has no permissions
+// and can't be refreshed
+// @Summary      View as the user
+// @Tags         admin
+// @Produce      json
+// @Param		 Authorization  header	string	true	"access token"
+// @Param		 id				path	int		true	"User ID"
+// @Success      201  {object}  outputs.ImpersonationResult
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/users/{id}/impersonate [post]
+func (c *AdminUsersController) impersonateUser(ctx *gin.Context) {
+	actor, err := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	target, err := c.getTarget(ctx, actor)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	if target.Disabled {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.NewUserDisabledError())
+		return
+	}
+
+	// The record is saved before the token is issued, so no session is left unaudited
+	if CheckErrorAndWriteBadRequest(ctx, c.db.AddAuditRecord(actor.ID, models.AuditUserImpersonate, &target.ID, nil)) {
+		return
+	}
+
+	pu := models.NewPublicUserFromUser(target)
+
+	token, err := c.auth.CreateImpersonationToken(pu, actor.ID)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	pu.Permissions = models.Permissions{}
+	pu.Mfa = false
+	pu.ImpersonatedBy = &actor.ID
+
+	ctx.JSON(http.StatusCreated, outputs.NewImpersonationResult(pu, token, int(auth.AuthImpersonationLifetime.Seconds())))
+}
@@ -4,11 +4,13 @@ import (
 	"math"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/PC-Core/pc-core-backend/internal/controllers/conerrors"
 	"github.com/PC-Core/pc-core-backend/internal/database"
 	"github.com/PC-Core/pc-core-backend/internal/errors"
 	"github.com/PC-Core/pc-core-backend/internal/helpers"
+	"github.com/PC-Core/pc-core-backend/internal/pricing"
 	"github.com/PC-Core/pc-core-backend/internal/redis"
 	"github.com/PC-Core/pc-core-backend/pkg/models"
 	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
@@ -41,6 +43,8 @@ func (c *CartController) ApplyRoutes() {
 		gr.DELETE("/:id", c.removeFromCart)
 		gr.DELETE("/item/:id", c.removeQuantity)
 		gr.PUT("/item/:id", c.addQuantity)
+		gr.POST("/promo", c.applyPromo)
+		gr.DELETE("/promo/:code", c.removePromo)
 	}
 }
 
@@ -74,6 +78,10 @@ func (c *CartController) addToTempCart(pu *models.PublicUser, productID int, inp
 	return id, nil
 }
 
+func (c *CartController) setToTempCart(pu *models.PublicUser, productID int, input inputs.AddToCartInput) (uint64, errors.PCCError) {
+	return c.rctrl.SetToCart(uint64(pu.ID), uint64(productID), uint(input.Quantity))
+}
+
 func (c *CartController) addToDefaultCart(pu *models.PublicUser, productID int, input inputs.AddToCartInput) (uint64, errors.PCCError) {
 	return c.db.AddToCart(uint64(productID), uint64(pu.ID), uint64(input.Quantity))
 }
@@ -82,7 +90,7 @@ func (c *CartController) setToDefaultCart(pu *models.PublicUser, productID int,
 	return c.db.SetToCart(uint64(productID), uint64(pu.ID), uint64(input.Quantity))
 }
 
-// Get user's cart
+// Get user's cart with the totals and the warnings about the stock and the prices
 // @Summary      Get user's cart
 // @Tags         cart
 // @Accept       json
@@ -100,7 +108,21 @@ func (c *CartController) getCart(ctx *gin.Context) {
 		return
 	}
 
-	var cart *models.Cart
+	cart, err := c.priceCart(pu)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+// summarizeCart loads the cart and computes its totals without the promotions
+func (c *CartController) summarizeCart(pu *models.PublicUser) (*models.Cart, errors.PCCError) {
+	var (
+		cart *models.Cart
+		err  errors.PCCError
+	)
 
 	switch pu.Role {
 	case models.Temporary:
@@ -109,6 +131,162 @@ func (c *CartController) getCart(ctx *gin.Context) {
 		cart, err = c.getDefaultCart(pu)
 	}
 
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pricing.SummarizeCart(cart); err != nil {
+		return nil, err
+	}
+
+	return cart, nil
+}
+
+// priceCart loads the cart and computes its totals with the applied promotions.
+// The temporary users can't apply the promotions
+func (c *CartController) priceCart(pu *models.PublicUser) (*models.Cart, errors.PCCError) {
+	if pu.Role == models.Temporary {
+		return c.summarizeCart(pu)
+	}
+
+	return priceUserCart(c.db, pu.ID)
+}
+
+// priceUserCart loads the cart of the registered user and computes its totals with the applied promotions
+func priceUserCart(db database.DbController, userID int) (*models.Cart, errors.PCCError) {
+	cart, err := db.GetCartByUserID(uint64(userID))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pricing.SummarizeCart(cart); err != nil {
+		return nil, err
+	}
+
+	promotions, err := db.GetCartPromotions(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pricing.ApplyPromotions(cart, promotions, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return cart, nil
+}
+
+// Apply the promo code to the cart
+// @Summary      Apply the promo code to the cart
+// @Description  Returns the cart with the discounts. The adjustments of the items show which promotion changed which line
+// @Tags         cart
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization	header	string					true	"access token for authorization"
+// @Param		 input			body	inputs.ApplyPromoInput	true	"Promo code"
+// @Success      200  {object}  models.Cart
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /cart/promo [post]
+func (c *CartController) applyPromo(ctx *gin.Context) {
+	pu, err := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	if pu.Role == models.Temporary {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.NewTemporaryUserError())
+		return
+	}
+
+	var input inputs.ApplyPromoInput
+
+	if berr := ctx.ShouldBindBodyWithJSON(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	promotion, err := c.db.GetPromotionByCode(input.Code, pu.ID)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	applied, err := c.db.GetCartPromotions(pu.ID)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	alreadyApplied := false
+
+	for _, p := range applied {
+		alreadyApplied = alreadyApplied || p.ID == promotion.ID
+	}
+
+	if !alreadyApplied {
+		if !pricing.CanStack(promotion, applied) {
+			CheckErrorAndWriteBadRequest(ctx, conerrors.NewPromotionNotStackableError())
+			return
+		}
+
+		cart, err := c.summarizeCart(pu)
+
+		if CheckErrorAndWriteBadRequest(ctx, err) {
+			return
+		}
+
+		if reason := pricing.CheckPromotion(promotion, cart, time.Now()); reason != "" {
+			CheckErrorAndWriteBadRequest(ctx, conerrors.NewPromotionUnavailableError(reason))
+			return
+		}
+
+		if CheckErrorAndWriteBadRequest(ctx, c.db.AddCartPromotion(pu.ID, promotion.ID)) {
+			return
+		}
+	}
+
+	cart, err := c.priceCart(pu)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+// Remove the promo code from the cart
+// @Summary      Remove the promo code from the cart
+// @Tags         cart
+// @Produce      json
+// @Param 		 Authorization	header	string	true	"access token for authorization"
+// @Param		 code			path	string	true	"Promo code"
+// @Success      200  {object}  models.Cart
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /cart/promo/{code} [delete]
+func (c *CartController) removePromo(ctx *gin.Context) {
+	pu, err := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	if pu.Role == models.Temporary {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.NewTemporaryUserError())
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.RemoveCartPromotion(pu.ID, ctx.Param("code"))) {
+		return
+	}
+
+	cart, err := c.priceCart(pu)
+
 	if CheckErrorAndWriteBadRequest(ctx, err) {
 		return
 	}
@@ -154,7 +332,7 @@ func (c *CartController) setToCart(ctx *gin.Context) {
 
 	switch pu.Role {
 	case models.Temporary:
-		product_id, err = c.addToTempCart(pu, productID, input)
+		product_id, err = c.setToTempCart(pu, productID, input)
 	default:
 		product_id, err = c.setToDefaultCart(pu, productID, input)
 	}
@@ -187,12 +365,19 @@ func (c *CartController) removeFromCart(ctx *gin.Context) {
 
 	idStr := ctx.Param("id")
 	id, eerr := strconv.Atoi(idStr)
-	if err != nil {
+	if eerr != nil {
 		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(eerr))
 		return
 	}
 
-	product_id, err := c.db.RemoveFromCart(uint64(id), uint64(pu.ID))
+	var product_id uint64
+
+	switch pu.Role {
+	case models.Temporary:
+		product_id, err = c.rctrl.RemoveFromCart(uint64(pu.ID), uint64(id))
+	default:
+		product_id, err = c.db.RemoveFromCart(uint64(id), uint64(pu.ID))
+	}
 
 	if CheckErrorAndWriteBadRequest(ctx, err) {
 		return
@@ -202,6 +387,10 @@ func (c *CartController) removeFromCart(ctx *gin.Context) {
 }
 
 func (c *CartController) changeQuantity(productID int, pu *models.PublicUser, quantity int) (uint64, errors.PCCError) {
+	if pu.Role == models.Temporary {
+		return c.rctrl.ChangeQuantity(uint64(pu.ID), uint64(productID), int64(quantity))
+	}
+
 	return c.db.ChangeQuantity(uint64(productID), uint64(pu.ID), int64(quantity))
 }
 
@@ -218,7 +407,7 @@ func (c *CartController) reqChangeQuantity(ctx *gin.Context, sign int) {
 
 	idStr := ctx.Param("id")
 	id, eerr := strconv.Atoi(idStr)
-	if err != nil {
+	if eerr != nil {
 		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(eerr))
 		return
 	}
@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/PC-Core/pc-core-backend/internal/controllers/conerrors"
+	"github.com/PC-Core/pc-core-backend/internal/database"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/helpers"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"github.com/PC-Core/pc-core-backend/pkg/models/outputs"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultReservationTTL is used if the checkout config doesn't set the time
+const DefaultReservationTTL = 15 * time.Minute
+
+type CheckoutController struct {
+	engine          *gin.Engine
+	db              database.DbController
+	pucaster        helpers.PublicUserCaster
+	auth_middleware gin.HandlerFunc
+	reservationTTL  time.Duration
+}
+
+func NewCheckoutController(engine *gin.Engine, db database.DbController, pucaster helpers.PublicUserCaster, auth_middleware gin.HandlerFunc, reservationTTL time.Duration) *CheckoutController {
+	if reservationTTL <= 0 {
+		reservationTTL = DefaultReservationTTL
+	}
+
+	return &CheckoutController{
+		engine, db, pucaster, auth_middleware, reservationTTL,
+	}
+}
+
+func (c *CheckoutController) ApplyRoutes() {
+	checkout := c.engine.Group("/checkout", c.auth_middleware)
+	{
+		checkout.POST("/", c.startCheckout)
+		checkout.POST("/:id/complete", c.completeCheckout)
+		checkout.DELETE("/:id", c.cancelCheckout)
+	}
+
+	orders := c.engine.Group("/orders", c.auth_middleware)
+	{
+		orders.GET("/", c.getOrders)
+		orders.GET("/:id", c.getOrder)
+	}
+}
+
+func getOrderID(ctx *gin.Context) (int64, bool) {
+	id, perr := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if perr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(perr))
+		return 0, false
+	}
+
+	return id, true
+}
+
+// Start the checkout
+// @Summary      Start the checkout
+// @Description  Creates the pending order from the cart and reserves its items until expires_at. The previous checkout is cancelled
+// @Tags         checkout
+// @Produce      json
+// @Param 		 Authorization	header	string	true	"access token for authorization"
+// @Success      201  {object}  models.Order
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /checkout/ [post]
+func (c *CheckoutController) startCheckout(ctx *gin.Context) {
+	pu, ok := GetRegisteredUser(ctx, c.pucaster)
+
+	if !ok {
+		return
+	}
+
+	cart, err := priceUserCart(c.db, pu.ID)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	if len(cart.Items) == 0 {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.NewEmptyCartError())
+		return
+	}
+
+	mismatched := make([]uint64, 0)
+
+	for _, item := range cart.Items {
+		if item.CurrencyMismatch {
+			mismatched = append(mismatched, item.Product.ID)
+		}
+	}
+
+	if len(mismatched) != 0 {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.NewCartCurrencyMismatchError(mismatched))
+		return
+	}
+
+	order, err := c.db.StartCheckout(pu.ID, cart, c.reservationTTL)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, order)
+}
+
+// Complete the checkout
+// @Summary      Complete the checkout
+// @Description  Completes the pending order, the reserved items are taken from the stock and removed from the cart
+// @Tags         checkout
+// @Produce      json
+// @Param 		 Authorization	header	string	true	"access token for authorization"
+// @Param		 id				path	int		true	"Order ID"
+// @Success      200  {object}  models.Order
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /checkout/{id}/complete [post]
+func (c *CheckoutController) completeCheckout(ctx *gin.Context) {
+	pu, ok := GetRegisteredUser(ctx, c.pucaster)
+
+	if !ok {
+		return
+	}
+
+	id, ok := getOrderID(ctx)
+
+	if !ok {
+		return
+	}
+
+	order, err := c.db.CompleteCheckout(pu.ID, id)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, order)
+}
+
+// Cancel the checkout
+// @Summary      Cancel the checkout and release the reserved items
+// @Tags         checkout
+// @Produce      json
+// @Param 		 Authorization	header	string	true	"access token for authorization"
+// @Param		 id				path	int		true	"Order ID"
+// @Success      200  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /checkout/{id} [delete]
+func (c *CheckoutController) cancelCheckout(ctx *gin.Context) {
+	pu, ok := GetRegisteredUser(ctx, c.pucaster)
+
+	if !ok {
+		return
+	}
+
+	id, ok := getOrderID(ctx)
+
+	if !ok {
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.CancelCheckout(pu.ID, id)) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "ok")
+}
+
+// Get user's orders
+// @Summary      Get user's orders
+// @Tags         checkout
+// @Produce      json
+// @Param 		 Authorization	header	string					true	"access token for authorization"
+// @Param		 page			query	inputs.GetOrdersInput	true	"Page and count"
+// @Success      200  {object}  outputs.GetOrdersResult
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /orders/ [get]
+func (c *CheckoutController) getOrders(ctx *gin.Context) {
+	pu, ok := GetRegisteredUser(ctx, c.pucaster)
+
+	if !ok {
+		return
+	}
+
+	var input inputs.GetOrdersInput
+
+	if berr := ctx.ShouldBindQuery(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	start := (input.Page * input.Count) - input.Count
+
+	orders, amount, err := c.db.GetUserOrders(pu.ID, start, input.Count)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, outputs.NewGetOrdersResult(orders, amount, input.Page))
+}
+
+// Get user's order by ID
+// @Summary      Get user's order by ID
+// @Tags         checkout
+// @Produce      json
+// @Param 		 Authorization	header	string	true	"access token for authorization"
+// @Param		 id				path	int		true	"Order ID"
+// @Success      200  {object}  models.Order
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /orders/{id} [get]
+func (c *CheckoutController) getOrder(ctx *gin.Context) {
+	pu, ok := GetRegisteredUser(ctx, c.pucaster)
+
+	if !ok {
+		return
+	}
+
+	id, ok := getOrderID(ctx)
+
+	if !ok {
+		return
+	}
+
+	order, err := c.db.GetUserOrder(pu.ID, id)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, order)
+}
@@ -1,32 +1,57 @@
 package controllers
 
 import (
+	"bytes"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/PC-Core/pc-core-backend/internal/controllers/conerrors"
 	"github.com/PC-Core/pc-core-backend/internal/database"
 	"github.com/PC-Core/pc-core-backend/internal/errors"
 	"github.com/PC-Core/pc-core-backend/internal/helpers"
+	"github.com/PC-Core/pc-core-backend/internal/middlewares"
+	"github.com/PC-Core/pc-core-backend/internal/static"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
 	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
 	"github.com/gin-gonic/gin"
 )
 
+// CommentMediaMaxSize is the maximum size of the image attached to the comment in bytes
+const CommentMediaMaxSize = 10 << 20
+
+// commentMediaExtensions maps the detected content types of the allowed comment images to the file extensions
+var commentMediaExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+}
+
 type CommentController struct {
 	engine                       *gin.Engine
 	db                           database.DbController
 	auth_middleware              gin.HandlerFunc
 	auth_not_required_middleware gin.HandlerFunc
 	pucaster                     helpers.PublicUserCaster
+	static                       static.StaticDataController
+	// premoderation is the age of the account its comments are pre-moderated before
+	premoderation time.Duration
+	// reviewEditWindow is the period the reviews can be edited during
+	reviewEditWindow time.Duration
 }
 
-func NewCommentController(engine *gin.Engine, db database.DbController, auth_middleware gin.HandlerFunc, auth_not_req_middleware gin.HandlerFunc, pucaster helpers.PublicUserCaster) *CommentController {
+func NewCommentController(engine *gin.Engine, db database.DbController, auth_middleware gin.HandlerFunc, auth_not_req_middleware gin.HandlerFunc, pucaster helpers.PublicUserCaster, static static.StaticDataController, premoderation time.Duration, reviewEditWindow time.Duration) *CommentController {
 	return &CommentController{
 		engine,
 		db,
 		auth_middleware,
 		auth_not_req_middleware,
 		pucaster,
+		static,
+		premoderation,
+		reviewEditWindow,
 	}
 }
 
@@ -36,6 +61,11 @@ func (c *CommentController) ApplyRoutes() {
 		g.GET("/product/:id", c.auth_not_required_middleware, c.getRootComments)
 		g.GET("/parent/:id", c.auth_not_required_middleware, c.getAnswers)
 		g.POST("/product/:id", c.auth_middleware, c.addComment)
+		g.PUT("/:id", c.auth_middleware, c.editComment)
+		g.DELETE("/:id", c.auth_middleware, c.deleteComment)
+		g.POST("/:id/report", c.auth_middleware, c.reportComment)
+		g.POST("/media", c.auth_middleware, c.uploadMedia)
+		g.GET("/:id/history", c.auth_not_required_middleware, c.getHistory)
 	}
 }
 
@@ -67,7 +97,7 @@ func (c *CommentController) getRootComments(ctx *gin.Context) {
 
 	userID := GetNotRequiredUserID(ctx, c.pucaster)
 
-	comments, perr := c.db.GetRootCommentsForProduct(int64(id), userID, input.Limit, input.Offset)
+	comments, perr := c.db.GetRootCommentsForProduct(int64(id), userID, &input)
 
 	if CheckErrorAndWriteBadRequest(ctx, perr) {
 		return
@@ -83,7 +113,7 @@ func (c *CommentController) getRootComments(ctx *gin.Context) {
 // @Produce      json
 // @Param 		 comment_id 	query	int						true	"ID of the comment"
 // @param		 input			query	inputs.GetAnswersInput	true	"Input"
-// @Param		 Authorization  header	string					false	"access token for user is used to check your reaction, is not required"
+// @Param		 Authorization  header	string					false	"access token for user is used to check your reaction and to show the answers on your hidden comment, is not required"
 // @Success      200  {object}  outputs.CommentsOutput
 // @Failure      400  {object}  errors.PublicPCCError
 // @Router       /comment/parent/:id [get]
@@ -104,8 +134,10 @@ func (c *CommentController) getAnswers(ctx *gin.Context) {
 	}
 
 	userID := GetNotRequiredUserID(ctx, c.pucaster)
+	pu, perr := GetPubUser(ctx, c.pucaster)
+	moderator := perr == nil && middlewares.HasPermission(pu, models.PermissionCommentsModerate)
 
-	ans, perr := c.db.GetAnswersOnComment(input.ProductID, userID, int64(id), input.Limit, input.Offset)
+	ans, perr := c.db.GetAnswersOnComment(input.ProductID, userID, moderator, int64(id), input.Limit, input.Offset)
 
 	if CheckErrorAndWriteBadRequest(ctx, perr) {
 		return
@@ -114,7 +146,8 @@ func (c *CommentController) getAnswers(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, ans)
 }
 
-// Add comment
+// Add comment. The root comment with the rating is the review, the user has at most one review of the product.
+// The comments of the new accounts are shown after the approval of the moderators
 // @Summary      Add comment
 // @Tags         comments
 // @Accept       json
@@ -147,7 +180,7 @@ func (c *CommentController) addComment(ctx *gin.Context) {
 		return
 	}
 
-	newID, perr := c.db.AddComment(&input, int64(data.ID), int64(id))
+	newID, perr := c.db.AddComment(&input, int64(data.ID), int64(id), c.premoderation)
 
 	if CheckErrorAndWriteBadRequest(ctx, perr) {
 		return
@@ -155,3 +188,228 @@ func (c *CommentController) addComment(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusCreated, newID)
 }
+
+// Edit comment. The reviews can be edited only during the configured period after they are written
+// @Summary      Edit your comment. The rating can be changed only on the reviews
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param 		 id 			path	int						true	"ID of the comment"
+// @Param		 Authorization  header	string					true	"access token"
+// @Param		 input			body	inputs.EditCommentInput	true	"input"
+// @Success      200  {object} 	int
+// @Failure      400  {object}  errors.PublicPCCError
+// @Router       /comment/{id} [put]
+func (c *CommentController) editComment(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(err))
+		return
+	}
+
+	data, perr := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, perr) {
+		return
+	}
+
+	var input inputs.EditCommentInput
+
+	if err := ctx.ShouldBindBodyWithJSON(&input); err != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(err))
+		return
+	}
+
+	editedID, perr := c.db.EditComment(&input, id, int64(data.ID), c.reviewEditWindow)
+
+	if CheckErrorAndWriteBadRequest(ctx, perr) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, editedID)
+}
+
+// Delete comment
+// @Summary      Delete your comment. The deleted review doesn't count in the rating of the product
+// @Tags         comments
+// @Produce      json
+// @Param 		 id 			path	int		true	"ID of the comment"
+// @Param		 Authorization  header	string	true	"access token"
+// @Success      200  {object} 	int
+// @Failure      400  {object}  errors.PublicPCCError
+// @Router       /comment/{id} [delete]
+func (c *CommentController) deleteComment(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(err))
+		return
+	}
+
+	data, perr := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, perr) {
+		return
+	}
+
+	deletedID, perr := c.db.DeleteComment(id, int64(data.ID))
+
+	if CheckErrorAndWriteBadRequest(ctx, perr) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deletedID)
+}
+
+// Report comment
+// @Summary      Report the abusive comment to the moderators
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param 		 id 			path	int							true	"ID of the comment"
+// @Param		 Authorization  header	string						true	"access token"
+// @Param		 input			body	inputs.ReportCommentInput	true	"input"
+// @Success      201  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Router       /comment/{id}/report [post]
+func (c *CommentController) reportComment(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(err))
+		return
+	}
+
+	data, ok := GetRegisteredUser(ctx, c.pucaster)
+
+	if !ok {
+		return
+	}
+
+	var input inputs.ReportCommentInput
+
+	if err := ctx.ShouldBindBodyWithJSON(&input); err != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(err))
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.ReportComment(data.ID, id, &input)) {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, "ok")
+}
+
+// Upload comment media
+// @Summary      Upload the image to attach it to the comment. Pass the ID of the media in the medias of the comment
+// @Tags         comments
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param		 Authorization  header		string	true	"access token"
+// @Param 		 image			formData	file	true	"PNG, JPEG or GIF image"
+// @Success      201  {object}  models.Media
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /comment/media [post]
+func (c *CommentController) uploadMedia(ctx *gin.Context) {
+	data, ok := GetRegisteredUser(ctx, c.pucaster)
+
+	if !ok {
+		return
+	}
+
+	header, ferr := ctx.FormFile("image")
+
+	if ferr != nil || header.Size > CommentMediaMaxSize {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.NewInvalidCommentMediaError(CommentMediaMaxSize))
+		return
+	}
+
+	file, ferr := header.Open()
+
+	if ferr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewInternalSecretError())
+		return
+	}
+
+	defer file.Close()
+
+	content, ferr := io.ReadAll(io.LimitReader(file, CommentMediaMaxSize))
+
+	if ferr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewInternalSecretError())
+		return
+	}
+
+	// The content type is detected by the content, the header from the client is not trusted
+	ctype := http.DetectContentType(content)
+	ext, ok := commentMediaExtensions[ctype]
+
+	if !ok {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.NewInvalidCommentMediaError(CommentMediaMaxSize))
+		return
+	}
+
+	thumbnail, ferr := static.MakeThumbnail(content, static.ThumbnailSize)
+
+	if ferr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.NewInvalidCommentMediaError(CommentMediaMaxSize))
+		return
+	}
+
+	suffix, terr := helpers.RandomToken(8)
+
+	if terr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewInternalSecretError())
+		return
+	}
+
+	name := fmt.Sprintf("comments/%d/%s", data.ID, suffix)
+
+	locs, err := c.static.UploadFiles([]static.StaticFile{
+		*static.NewStaticFile(bytes.NewReader(content), name+ext, ctype),
+		*static.NewStaticFile(bytes.NewReader(thumbnail), name+"_thumb.jpg", "image/jpeg"),
+	})
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	media, err := c.db.AddCommentMedia(data.ID, locs[0], locs[1])
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, media)
+}
+
+// Get comment history
+// @Summary      Get the edit history of the comment. The moderators see all the revisions with the diffs
+// @Tags         comments
+// @Produce      json
+// @Param 		 id 			path	int		true	"ID of the comment"
+// @Param		 Authorization  header	string	false	"access token, the revisions are returned for the moderators"
+// @Success      200  {object} 	models.CommentHistory
+// @Failure      400  {object}  errors.PublicPCCError
+// @Router       /comment/{id}/history [get]
+func (c *CommentController) getHistory(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(err))
+		return
+	}
+
+	pu, perr := GetPubUser(ctx, c.pucaster)
+	full := perr == nil && middlewares.HasPermission(pu, models.PermissionCommentsModerate)
+
+	history, perr := c.db.GetCommentHistory(id, full)
+
+	if CheckErrorAndWriteBadRequest(ctx, perr) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, history)
+}
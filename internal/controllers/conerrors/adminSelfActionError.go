@@ -0,0 +1,31 @@
+package conerrors
+
+import (
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+)
+
+const ADMIN_SELF_ACTION_MESSAGE = "The action can't be applied to your own account"
+
+// AdminSelfActionError is returned when the admin tries to disable,
+// impersonate or change the role of his own account
+type AdminSelfActionError struct{}
+
+func NewAdminSelfActionError() *AdminSelfActionError {
+	return &AdminSelfActionError{}
+}
+
+func (e *AdminSelfActionError) Error() string {
+	return ADMIN_SELF_ACTION_MESSAGE
+}
+
+func (e *AdminSelfActionError) GetErrorKind() errors.ErrorKind {
+	return errors.EK_CTRLS
+}
+
+func (e *AdminSelfActionError) GetErrorCode() errors.ErrorCode {
+	return errors.EC_ADMIN_SELF_ACTION
+}
+
+func (e *AdminSelfActionError) IntoPublic() *errors.PublicPCCError {
+	return errors.NewPublicPCCError(e.GetErrorCode(), e.GetErrorKind(), nil, ADMIN_SELF_ACTION_MESSAGE)
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 
 	inerrors "github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/pricing/prerrors"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -39,6 +40,10 @@ func BindErrorCast(err error) inerrors.PCCError {
 		return inerrors.NewJsonSyntaxError(syntax_err.Offset)
 	}
 
+	if merr := prerrors.MoneyErrorCast(err); merr != nil {
+		return merr
+	}
+
 	if errors.Is(err, io.EOF) {
 		return NewEmptyBodyError()
 	}
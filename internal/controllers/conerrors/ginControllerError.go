@@ -1,13 +1,25 @@
 package conerrors
 
-import "github.com/PC-Core/pc-core-backend/internal/errors"
+import (
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+)
 
 const (
 	// GCE_BIND_ERROR_MESSAGE contains the error message means that input data is invalid
-	GCE_BIND_ERROR_MESSAGE   = "Error while binding: invalid data provided"
-	GCE_NO_USER_DATA_MESSAGE = "Error while getting user data: no data provided"
-	GCE_EMPTY_BODY           = "The body expected to be non-empty, was empty"
-	GCE_UNKNOWN_BIND_ERROR   = "Unknown bind error"
+	GCE_BIND_ERROR_MESSAGE    = "Error while binding: invalid data provided"
+	GCE_NO_USER_DATA_MESSAGE  = "Error while getting user data: no data provided"
+	GCE_EMPTY_BODY            = "The body expected to be non-empty, was empty"
+	GCE_UNKNOWN_BIND_ERROR    = "Unknown bind error"
+	GCE_TEMPORARY_USER        = "The action is not available for the temporary users"
+	GCE_WRONG_PASSWORD        = "The current password is wrong"
+	GCE_VERIFICATION_INVALID  = "The verification token is invalid or expired"
+	GCE_INVALID_AVATAR        = "The avatar must be a PNG, JPEG or WebP image not larger than the limit"
+	GCE_INVALID_COMMENT_MEDIA = "The comment media must be a PNG, JPEG or GIF image not larger than the limit"
+	GCE_PROMO_UNAVAILABLE     = "The promo code can't be applied to the cart"
+	GCE_PROMO_NOT_STACKABLE   = "The promo code can't be combined with the applied ones"
+	GCE_EMPTY_CART            = "The cart has no available items"
+	GCE_CURRENCY_MISMATCH     = "The cart has the items priced in different currencies"
 )
 
 // GinControllerError represents an error occured in controllers
@@ -72,3 +84,57 @@ func GetUserDataFromContextError() *GinControllerError {
 func NewUnknownInputError() *GinControllerError {
 	return NewGinControllersError(errors.EC_CTRLS_INPUT_ERROR, GCE_UNKNOWN_BIND_ERROR, nil)
 }
+
+// NewTemporaryUserError creates an instance of GinControllerError.
+// Error represents the action which requires the registered user
+func NewTemporaryUserError() *GinControllerError {
+	return NewGinControllersError(errors.EC_TEMPORARY_USER_FORBIDDEN, GCE_TEMPORARY_USER, nil)
+}
+
+// NewWrongPasswordError creates an instance of GinControllerError.
+// Error represents the wrong current password on the sensitive data change
+func NewWrongPasswordError() *GinControllerError {
+	return NewGinControllersError(errors.EC_WRONG_PASSWORD, GCE_WRONG_PASSWORD, nil)
+}
+
+// NewVerificationInvalidError creates an instance of GinControllerError.
+// Error represents the unknown or expired email verification token
+func NewVerificationInvalidError() *GinControllerError {
+	return NewGinControllersError(errors.EC_EMAIL_VERIFICATION_INVALID, GCE_VERIFICATION_INVALID, nil)
+}
+
+// NewInvalidAvatarError creates an instance of GinControllerError.
+// Details contain the size limit in bytes
+func NewInvalidAvatarError(limit int64) *GinControllerError {
+	return NewGinControllersError(errors.EC_INVALID_AVATAR, GCE_INVALID_AVATAR, map[string]int64{"limit": limit})
+}
+
+// NewInvalidCommentMediaError creates an instance of GinControllerError.
+// Details contain the size limit in bytes
+func NewInvalidCommentMediaError(limit int64) *GinControllerError {
+	return NewGinControllersError(errors.EC_INVALID_COMMENT_MEDIA, GCE_INVALID_COMMENT_MEDIA, map[string]int64{"limit": limit})
+}
+
+// NewPromotionUnavailableError creates an instance of GinControllerError.
+// Details contain the reason the promotion was rejected
+func NewPromotionUnavailableError(reason models.PromotionRejection) *GinControllerError {
+	return NewGinControllersError(errors.EC_PROMO_UNAVAILABLE, GCE_PROMO_UNAVAILABLE, map[string]models.PromotionRejection{"reason": reason})
+}
+
+// NewPromotionNotStackableError creates an instance of GinControllerError.
+// Error represents the promotion which can't be combined with the applied ones
+func NewPromotionNotStackableError() *GinControllerError {
+	return NewGinControllersError(errors.EC_PROMO_NOT_STACKABLE, GCE_PROMO_NOT_STACKABLE, nil)
+}
+
+// NewEmptyCartError creates an instance of GinControllerError.
+// Error represents the checkout of the cart without the available items
+func NewEmptyCartError() *GinControllerError {
+	return NewGinControllersError(errors.EC_CHECKOUT_EMPTY_CART, GCE_EMPTY_CART, nil)
+}
+
+// NewCartCurrencyMismatchError creates an instance of GinControllerError.
+// Details contain the products priced in a currency other than the currency of the cart
+func NewCartCurrencyMismatchError(productIDs []uint64) *GinControllerError {
+	return NewGinControllersError(errors.EC_CHECKOUT_CURRENCY_MISMATCH, GCE_CURRENCY_MISMATCH, map[string][]uint64{"products": productIDs})
+}
@@ -0,0 +1,29 @@
+package conerrors
+
+import (
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+)
+
+const USER_DISABLED_MESSAGE = "The account is disabled"
+
+type UserDisabledError struct{}
+
+func NewUserDisabledError() *UserDisabledError {
+	return &UserDisabledError{}
+}
+
+func (e *UserDisabledError) Error() string {
+	return USER_DISABLED_MESSAGE
+}
+
+func (e *UserDisabledError) GetErrorKind() errors.ErrorKind {
+	return errors.EK_CTRLS
+}
+
+func (e *UserDisabledError) GetErrorCode() errors.ErrorCode {
+	return errors.EC_USER_DISABLED
+}
+
+func (e *UserDisabledError) IntoPublic() *errors.PublicPCCError {
+	return errors.NewPublicPCCError(e.GetErrorCode(), e.GetErrorKind(), nil, USER_DISABLED_MESSAGE)
+}
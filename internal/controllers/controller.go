@@ -33,6 +33,23 @@ func GetPubUser(ctx *gin.Context, pucaster helpers.PublicUserCaster) (*models.Pu
 	return pu, err
 }
 
+// GetRegisteredUser returns the current user. The error is written if the user is
+// not authorized or is temporary
+func GetRegisteredUser(ctx *gin.Context, pucaster helpers.PublicUserCaster) (*models.PublicUser, bool) {
+	pu, err := GetPubUser(ctx, pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return nil, false
+	}
+
+	if pu.Role == models.Temporary {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.NewTemporaryUserError())
+		return nil, false
+	}
+
+	return pu, true
+}
+
 func GetNotRequiredUserID(ctx *gin.Context, pucaster helpers.PublicUserCaster) *int64 {
 	var userID *int64 = nil
 
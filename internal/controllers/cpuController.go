@@ -15,17 +15,17 @@ type CpuController struct {
 	engine          *gin.Engine
 	db              database.DbController
 	auth_middleware gin.HandlerFunc
-	caster          helpers.RoleCastFunc
+	pucaster        helpers.PublicUserCaster
 }
 
-func NewCpuController(engine *gin.Engine, db database.DbController, auth_middleware gin.HandlerFunc, caster helpers.RoleCastFunc) *CpuController {
+func NewCpuController(engine *gin.Engine, db database.DbController, auth_middleware gin.HandlerFunc, pucaster helpers.PublicUserCaster) *CpuController {
 	return &CpuController{
-		engine, db, auth_middleware, caster,
+		engine, db, auth_middleware, pucaster,
 	}
 }
 
 func (c *CpuController) ApplyRoutes() {
-	c.engine.POST("/cpus/add", c.auth_middleware, middlewares.RoleCheck(models.Admin, c.db, c.caster), c.addCpu)
+	c.engine.POST("/cpus/add", c.auth_middleware, middlewares.RequirePermission(models.PermissionCatalogWrite, c.pucaster), c.addCpu)
 }
 
 // Add cpu
@@ -15,17 +15,17 @@ type GpuController struct {
 	engine          *gin.Engine
 	db              database.DbController
 	auth_middleware gin.HandlerFunc
-	caster          helpers.RoleCastFunc
+	pucaster        helpers.PublicUserCaster
 }
 
-func NewGpuController(engine *gin.Engine, db database.DbController, auth_middleware gin.HandlerFunc, caster helpers.RoleCastFunc) *GpuController {
+func NewGpuController(engine *gin.Engine, db database.DbController, auth_middleware gin.HandlerFunc, pucaster helpers.PublicUserCaster) *GpuController {
 	return &GpuController{
-		engine, db, auth_middleware, caster,
+		engine, db, auth_middleware, pucaster,
 	}
 }
 
 func (c *GpuController) ApplyRoutes() {
-	c.engine.POST("/gpus/add", c.auth_middleware, middlewares.RoleCheck(models.Admin, c.db, c.caster), c.addGpu)
+	c.engine.POST("/gpus/add", c.auth_middleware, middlewares.RequirePermission(models.PermissionCatalogWrite, c.pucaster), c.addGpu)
 }
 
 func (c *GpuController) addGpu(ctx *gin.Context) {
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"fmt"
 	"net/http"
 	"time"
 
@@ -20,6 +21,10 @@ type JWTController struct {
 	jwt_auth *jwt.JWTAuth
 }
 
+// JWKSCacheAge is how long the other services may cache the key set.
+// The new key should be published at least this long before it starts signing
+const JWKSCacheAge = 5 * time.Minute
+
 type SingleAccessToken struct {
 	AccessToken string `json:"access_token"`
 }
@@ -32,6 +37,18 @@ func NewJWTController(engine *gin.Engine, db database.DbController, jwt_auth *jw
 
 func (c *JWTController) ApplyRoutes() {
 	c.engine.POST("/auth/jwt/update", c.updateAccessToken)
+	c.engine.GET("/.well-known/jwks.json", c.getJWKS)
+}
+
+// Get the public keys used to verify the tokens
+// @Summary      Get the public keys used to verify the tokens
+// @Tags         jwt
+// @Produce      json
+// @Success      200  		{object}  	jwt.JWKSet
+// @Router       /.well-known/jwks.json [get]
+func (c *JWTController) getJWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(JWKSCacheAge.Seconds())))
+	ctx.JSON(http.StatusOK, c.jwt_auth.JWKS())
 }
 
 // Update Access JWT token
@@ -52,19 +69,26 @@ func (c *JWTController) updateAccessToken(ctx *gin.Context) {
 		return
 	}
 
-	id, err := c.getUserIdFromRefreshToken(token)
+	claims, err := c.getRefreshClaims(token)
 
 	if CheckErrorAndWriteUnauthorized(ctx, err) {
 		return
 	}
 
-	user, err := c.db.GetUserByID(id)
+	user, err := c.db.GetUserByID(claims.UserID)
 
 	if CheckErrorAndWriteUnauthorized(ctx, err) {
 		return
 	}
 
+	if user.Disabled {
+		CheckErrorAndWriteUnauthorized(ctx, conerrors.NewUserDisabledError())
+		return
+	}
+
 	pubuser := models.NewPublicUserFromUser(user)
+	// The second factor check is passed once per session, when the refresh token is issued
+	pubuser.Mfa = claims.Mfa
 
 	new_token, err := c.jwt_auth.CreateAccessToken(pubuser, time.Duration(auth.AuthPublicLifetime))
 
@@ -78,18 +102,22 @@ func (c *JWTController) updateAccessToken(ctx *gin.Context) {
 
 }
 
-func (c *JWTController) getUserIdFromRefreshToken(str_token string) (int, errors.PCCError) {
+func (c *JWTController) getRefreshClaims(str_token string) (*jwt.JWTRefreshAuthClaims, errors.PCCError) {
 	token, err := c.jwt_auth.ValidateRefreshJWT(str_token)
 
 	if err != nil {
-		return -1, err
+		return nil, err
 	}
 
 	claims, ok := token.Claims.(*jwt.JWTRefreshAuthClaims)
 
 	if !ok {
-		return -1, errors.NewInternalSecretError()
+		return nil, errors.NewInternalSecretError()
+	}
+
+	if err := c.jwt_auth.CheckRevoked(claims.UserID, claims.IssuedAt); err != nil {
+		return nil, err
 	}
 
-	return claims.UserID, nil
+	return claims, nil
 }
@@ -15,17 +15,17 @@ type KeyBoardController struct {
 	engine          *gin.Engine
 	db              database.DbController
 	auth_middleware gin.HandlerFunc
-	caster          helpers.RoleCastFunc
+	pucaster        helpers.PublicUserCaster
 }
 
-func NewKeyBoardController(engine *gin.Engine, db database.DbController, auth_middleware gin.HandlerFunc, caster helpers.RoleCastFunc) *KeyBoardController{
+func NewKeyBoardController(engine *gin.Engine, db database.DbController, auth_middleware gin.HandlerFunc, pucaster helpers.PublicUserCaster) *KeyBoardController{
 	return &KeyBoardController{
-		engine, db, auth_middleware, caster,
+		engine, db, auth_middleware, pucaster,
 	}
 }
 
 func (c *KeyBoardController) ApplyRoutes(){ 
-	c.engine.POST("/keyboards/add", c.auth_middleware, middlewares.RoleCheck(models.Admin, c.db, c.caster), c.addKeyBoard)
+	c.engine.POST("/keyboards/add", c.auth_middleware, middlewares.RequirePermission(models.PermissionCatalogWrite, c.pucaster), c.addKeyBoard)
 }
 
 func (c *KeyBoardController) addKeyBoard(ctx *gin.Context){
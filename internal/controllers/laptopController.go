@@ -16,17 +16,17 @@ type LaptopController struct {
 	engine          *gin.Engine
 	db              database.DbController
 	auth_middleware gin.HandlerFunc
-	caster          helpers.RoleCastFunc
+	pucaster        helpers.PublicUserCaster
 }
 
-func NewLaptopController(engine *gin.Engine, db database.DbController, auth_middleware gin.HandlerFunc, caster helpers.RoleCastFunc) *LaptopController {
+func NewLaptopController(engine *gin.Engine, db database.DbController, auth_middleware gin.HandlerFunc, pucaster helpers.PublicUserCaster) *LaptopController {
 	return &LaptopController{
-		engine, db, auth_middleware, caster,
+		engine, db, auth_middleware, pucaster,
 	}
 }
 
 func (c *LaptopController) ApplyRoutes() {
-	c.engine.POST("/laptops/add", c.auth_middleware, middlewares.RoleCheck(models.Admin, c.db, c.caster), c.addLaptop)
+	c.engine.POST("/laptops/add", c.auth_middleware, middlewares.RequirePermission(models.PermissionCatalogWrite, c.pucaster), c.addLaptop)
 }
 
 // Add laptop
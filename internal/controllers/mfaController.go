@@ -0,0 +1,299 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/PC-Core/pc-core-backend/internal/auth/totp"
+	"github.com/PC-Core/pc-core-backend/internal/auth/totp/terrors"
+	"github.com/PC-Core/pc-core-backend/internal/controllers/conerrors"
+	"github.com/PC-Core/pc-core-backend/internal/database"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/helpers"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"github.com/PC-Core/pc-core-backend/pkg/models/outputs"
+	"github.com/gin-gonic/gin"
+)
+
+type MfaController struct {
+	engine          *gin.Engine
+	db              database.DbController
+	auth_middleware gin.HandlerFunc
+	pucaster        helpers.PublicUserCaster
+	issuer          string
+}
+
+func NewMfaController(engine *gin.Engine, db database.DbController, auth_middleware gin.HandlerFunc, pucaster helpers.PublicUserCaster, issuer string) *MfaController {
+	return &MfaController{
+		engine, db, auth_middleware, pucaster, issuer,
+	}
+}
+
+func (c *MfaController) ApplyRoutes() {
+	gr := c.engine.Group("/users/mfa", c.auth_middleware)
+	{
+		gr.POST("/enroll", c.enroll)
+		gr.POST("/confirm", c.confirm)
+		gr.POST("/disable", c.disable)
+		gr.POST("/recovery-codes", c.regenerateRecoveryCodes)
+	}
+}
+
+// verifySecondFactor checks the TOTP code or, if it is not provided, spends the recovery code
+func verifySecondFactor(db database.DbController, user *models.User, input *inputs.MfaCodeInput) errors.PCCError {
+	if input.Code != "" && user.TotpSecret != nil {
+		ok, err := useTotpCode(db, user, input.Code)
+
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			return nil
+		}
+	}
+
+	if input.RecoveryCode != "" {
+		ok, err := db.UseRecoveryCode(user.ID, helpers.Sha256(totp.NormalizeRecoveryCode(input.RecoveryCode)))
+
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			return nil
+		}
+	}
+
+	return terrors.NewInvalidCodeError()
+}
+
+// useTotpCode checks the TOTP code and saves its time step. The code which was already
+// accepted once is rejected
+func useTotpCode(db database.DbController, user *models.User, code string) (bool, errors.PCCError) {
+	step, ok := totp.ValidateStep(*user.TotpSecret, code, time.Now())
+
+	if !ok {
+		return false, nil
+	}
+
+	return db.UseTotpStep(user.ID, step)
+}
+
+// generateRecoveryCodes returns the plain codes for the user and the hashes for the database
+func generateRecoveryCodes() ([]string, []string, errors.PCCError) {
+	codes, err := totp.GenerateRecoveryCodes(totp.RecoveryCodesAmount)
+
+	if err != nil {
+		return nil, nil, errors.NewInternalSecretError()
+	}
+
+	hashes := make([]string, 0, len(codes))
+
+	for _, code := range codes {
+		hashes = append(hashes, helpers.Sha256(code))
+	}
+
+	return codes, hashes, nil
+}
+
+func (c *MfaController) getUser(ctx *gin.Context) (*models.User, errors.PCCError) {
+	pu, err := GetPubUser(ctx, c.pucaster)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return c.db.GetUserByID(pu.ID)
+}
+
+// Start the MFA enrollment
+// @Summary      Start the MFA enrollment
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Param		 Authorization  header	string	true	"access token"
+// @Success      200  {object}  outputs.MfaEnrollment
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /users/mfa/enroll [post]
+func (c *MfaController) enroll(ctx *gin.Context) {
+	user, err := c.getUser(ctx)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	if user.MfaEnabled {
+		CheckErrorAndWriteBadRequest(ctx, terrors.NewAlreadyEnabledError())
+		return
+	}
+
+	secret, serr := totp.GenerateSecret()
+
+	if serr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewInternalSecretError())
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.SetUserTotpSecret(user.ID, secret)) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, outputs.NewMfaEnrollment(secret, totp.ProvisioningURI(c.issuer, user.Email, secret)))
+}
+
+// Confirm the MFA enrollment with the first code
+// @Summary      Confirm the MFA enrollment with the first code
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Param		 Authorization  header	string					true	"access token"
+// @Param		 input			body	inputs.MfaCodeInput		true	"TOTP code from the authenticator application"
+// @Success      200  {object}  outputs.RecoveryCodes
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /users/mfa/confirm [post]
+func (c *MfaController) confirm(ctx *gin.Context) {
+	user, err := c.getUser(ctx)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	var input inputs.MfaCodeInput
+
+	if berr := ctx.ShouldBindBodyWithJSON(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	if user.MfaEnabled {
+		CheckErrorAndWriteBadRequest(ctx, terrors.NewAlreadyEnabledError())
+		return
+	}
+
+	if user.TotpSecret == nil {
+		CheckErrorAndWriteBadRequest(ctx, terrors.NewNotEnrolledError())
+		return
+	}
+
+	ok, err := useTotpCode(c.db, user, input.Code)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	if !ok {
+		CheckErrorAndWriteBadRequest(ctx, terrors.NewInvalidCodeError())
+		return
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.EnableUserMfa(user.ID, hashes)) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, outputs.NewRecoveryCodes(codes))
+}
+
+// Disable the MFA
+// @Summary      Disable the MFA
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Param		 Authorization  header	string					true	"access token"
+// @Param		 input			body	inputs.MfaCodeInput		true	"TOTP or recovery code"
+// @Success      200  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /users/mfa/disable [post]
+func (c *MfaController) disable(ctx *gin.Context) {
+	user, err := c.getUser(ctx)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	var input inputs.MfaCodeInput
+
+	if berr := ctx.ShouldBindBodyWithJSON(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	if !user.MfaEnabled {
+		CheckErrorAndWriteBadRequest(ctx, terrors.NewNotEnrolledError())
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, verifySecondFactor(c.db, user, &input)) {
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.DisableUserMfa(user.ID)) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "ok")
+}
+
+// Regenerate the recovery codes
+// @Summary      Regenerate the recovery codes. The old codes become invalid
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Param		 Authorization  header	string					true	"access token"
+// @Param		 input			body	inputs.MfaCodeInput		true	"TOTP code"
+// @Success      200  {object}  outputs.RecoveryCodes
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /users/mfa/recovery-codes [post]
+func (c *MfaController) regenerateRecoveryCodes(ctx *gin.Context) {
+	user, err := c.getUser(ctx)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	var input inputs.MfaCodeInput
+
+	if berr := ctx.ShouldBindBodyWithJSON(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	if !user.MfaEnabled || user.TotpSecret == nil {
+		CheckErrorAndWriteBadRequest(ctx, terrors.NewNotEnrolledError())
+		return
+	}
+
+	ok, err := useTotpCode(c.db, user, input.Code)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	if !ok {
+		CheckErrorAndWriteBadRequest(ctx, terrors.NewInvalidCodeError())
+		return
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.ReplaceRecoveryCodes(user.ID, hashes)) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, outputs.NewRecoveryCodes(codes))
+}
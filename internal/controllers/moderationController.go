@@ -0,0 +1,255 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/PC-Core/pc-core-backend/internal/controllers/conerrors"
+	"github.com/PC-Core/pc-core-backend/internal/database"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/helpers"
+	"github.com/PC-Core/pc-core-backend/internal/middlewares"
+	"github.com/PC-Core/pc-core-backend/internal/middlewares/merrors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"github.com/PC-Core/pc-core-backend/pkg/models/outputs"
+	"github.com/gin-gonic/gin"
+)
+
+type ModerationController struct {
+	engine          *gin.Engine
+	db              database.DbController
+	auth_middleware gin.HandlerFunc
+	pucaster        helpers.PublicUserCaster
+}
+
+func NewModerationController(engine *gin.Engine, db database.DbController, auth_middleware gin.HandlerFunc, pucaster helpers.PublicUserCaster) *ModerationController {
+	return &ModerationController{
+		engine, db, auth_middleware, pucaster,
+	}
+}
+
+func (c *ModerationController) ApplyRoutes() {
+	gr := c.engine.Group("/admin", c.auth_middleware, middlewares.RequirePermission(models.PermissionCommentsModerate, c.pucaster))
+	{
+		gr.GET("/comments/moderation", c.getQueue)
+		gr.POST("/comments/:id/hide", c.hideComment)
+		gr.POST("/comments/:id/restore", c.restoreComment)
+		gr.POST("/comments/:id/ban-author", c.banAuthor)
+		gr.DELETE("/users/:id/comments-ban", c.unbanAuthor)
+	}
+}
+
+// getModerationAction parses the ID from the path and the optional reason from the body
+func getModerationAction(ctx *gin.Context) (int64, *inputs.ModerationActionInput, errors.PCCError) {
+	id, perr := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if perr != nil {
+		return 0, nil, errors.NewAtoiError(perr)
+	}
+
+	var input inputs.ModerationActionInput
+
+	if ctx.Request.ContentLength != 0 {
+		if berr := ctx.ShouldBindBodyWithJSON(&input); berr != nil {
+			return 0, nil, conerrors.BindErrorCast(berr)
+		}
+	}
+
+	return id, &input, nil
+}
+
+// checkModerationTarget forbids the actions against the staff to those who can't assign the roles
+func (c *ModerationController) checkModerationTarget(actor *models.PublicUser, targetID int) errors.PCCError {
+	if targetID == actor.ID {
+		return conerrors.NewAdminSelfActionError()
+	}
+
+	target, err := c.db.GetUserByID(targetID)
+
+	if err != nil {
+		return err
+	}
+
+	if len(target.Permissions) != 0 && !actor.Permissions.Has(models.PermissionRolesAssign) {
+		return merrors.NewMissingPermissionError(models.PermissionRolesAssign, actor.Role)
+	}
+
+	return nil
+}
+
+// Get the moderation queue
+// @Summary      Get the pending comments and the comments with the open reports, the most reported go first
+// @Tags         admin
+// @Produce      json
+// @Param		 Authorization  header	string							true	"access token"
+// @Param		 input			query	inputs.GetModerationQueueInput	true	"Page and count"
+// @Success      200  {object}  outputs.GetModerationQueueResult
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/comments/moderation [get]
+func (c *ModerationController) getQueue(ctx *gin.Context) {
+	var input inputs.GetModerationQueueInput
+
+	if berr := ctx.ShouldBindQuery(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	start := (input.Page * input.Count) - input.Count
+
+	items, amount, err := c.db.GetModerationQueue(start, input.Count)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, outputs.NewGetModerationQueueResult(items, amount, input.Page))
+}
+
+// Hide the comment
+// @Summary      Hide the comment and resolve its reports
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param		 Authorization  header	string							true	"access token"
+// @Param		 id				path	int								true	"Comment ID"
+// @Param		 input			body	inputs.ModerationActionInput	false	"Reason"
+// @Success      200  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/comments/{id}/hide [post]
+func (c *ModerationController) hideComment(ctx *gin.Context) {
+	actor, err := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	id, input, err := getModerationAction(ctx)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.HideComment(actor.ID, id, input.Reason)) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "ok")
+}
+
+// Restore the comment
+// @Summary      Show the hidden comment, approve the pending one or dismiss the reports
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param		 Authorization  header	string							true	"access token"
+// @Param		 id				path	int								true	"Comment ID"
+// @Param		 input			body	inputs.ModerationActionInput	false	"Reason"
+// @Success      200  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/comments/{id}/restore [post]
+func (c *ModerationController) restoreComment(ctx *gin.Context) {
+	actor, err := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	id, input, err := getModerationAction(ctx)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.RestoreComment(actor.ID, id, input.Reason)) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "ok")
+}
+
+// Ban the author of the comment
+// @Summary      Hide the comment and ban its author from commenting
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param		 Authorization  header	string							true	"access token"
+// @Param		 id				path	int								true	"Comment ID"
+// @Param		 input			body	inputs.ModerationActionInput	false	"Reason"
+// @Success      200  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/comments/{id}/ban-author [post]
+func (c *ModerationController) banAuthor(ctx *gin.Context) {
+	actor, err := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	id, input, err := getModerationAction(ctx)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	authorID, err := c.db.GetCommentAuthorID(id)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.checkModerationTarget(actor, authorID)) {
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.BanCommentAuthor(actor.ID, id, input.Reason)) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "ok")
+}
+
+// Lift the comments ban of the user
+// @Summary      Allow the banned user to comment again
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param		 Authorization  header	string							true	"access token"
+// @Param		 id				path	int								true	"User ID"
+// @Param		 input			body	inputs.ModerationActionInput	false	"Reason"
+// @Success      200  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/users/{id}/comments-ban [delete]
+func (c *ModerationController) unbanAuthor(ctx *gin.Context) {
+	actor, err := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	id, input, err := getModerationAction(ctx)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.checkModerationTarget(actor, int(id))) {
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.UnbanCommentAuthor(actor.ID, int(id), input.Reason)) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "ok")
+}
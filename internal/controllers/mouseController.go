@@ -15,17 +15,17 @@ type MouseController struct {
 	engine          *gin.Engine
 	db              database.DbController
 	auth_middleware gin.HandlerFunc
-	caster          helpers.RoleCastFunc
+	pucaster        helpers.PublicUserCaster
 }
 
-func NewMouseController(engine *gin.Engine, db database.DbController, auth_middleware gin.HandlerFunc, caster helpers.RoleCastFunc) *MouseController{
+func NewMouseController(engine *gin.Engine, db database.DbController, auth_middleware gin.HandlerFunc, pucaster helpers.PublicUserCaster) *MouseController{
 	return &MouseController{
-		engine, db, auth_middleware, caster,
+		engine, db, auth_middleware, pucaster,
 	}
 }
 
 func (c *MouseController) ApplyRoutes(){ 
-	c.engine.POST("/mouses/add", c.auth_middleware, middlewares.RoleCheck(models.Admin, c.db, c.caster), c.addMouse)
+	c.engine.POST("/mouses/add", c.auth_middleware, middlewares.RequirePermission(models.PermissionCatalogWrite, c.pucaster), c.addMouse)
 }
 
 func (c *MouseController) addMouse(ctx *gin.Context){
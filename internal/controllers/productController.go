@@ -3,23 +3,33 @@ package controllers
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/PC-Core/pc-core-backend/internal/controllers/conerrors"
 	"github.com/PC-Core/pc-core-backend/internal/database"
 	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/helpers"
+	"github.com/PC-Core/pc-core-backend/internal/middlewares"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
 	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
 	"github.com/PC-Core/pc-core-backend/pkg/models/outputs"
 	"github.com/gin-gonic/gin"
 )
 
+// LowestPriceWindow is the period before the latest price change the lowest price is shown for
+// with the discounts
+const LowestPriceWindow = 30 * 24 * time.Hour
+
 type ProductController struct {
-	engine *gin.Engine
-	db     database.DbController
+	engine          *gin.Engine
+	db              database.DbController
+	auth_middleware gin.HandlerFunc
+	pucaster        helpers.PublicUserCaster
 }
 
-func NewProductController(engine *gin.Engine, db database.DbController) *ProductController {
+func NewProductController(engine *gin.Engine, db database.DbController, auth_middleware gin.HandlerFunc, pucaster helpers.PublicUserCaster) *ProductController {
 	return &ProductController{
-		engine, db,
+		engine, db, auth_middleware, pucaster,
 	}
 }
 
@@ -27,6 +37,8 @@ func (c *ProductController) ApplyRoutes() {
 	c.engine.GET("/products/", c.getProducts)
 	c.engine.GET("/products/:id", c.getProductById)
 	c.engine.GET("/products/chars/:id", c.getProductChars)
+	c.engine.GET("/products/:id/price-history", c.getPriceHistory)
+	c.engine.PATCH("/products/:id", c.auth_middleware, middlewares.RequirePermission(models.PermissionCatalogWrite, c.pucaster), c.updateProduct)
 }
 
 // Get products from page N in quantity M
@@ -34,7 +46,7 @@ func (c *ProductController) ApplyRoutes() {
 // @Tags         products
 // @Accept       json
 // @Produce      json
-// @Param 		 product query	inputs.GetProductsInput	true	"Page and count"
+// @Param 		 product query	inputs.GetProductsInput	true	"Page, count, sort and minimal rating"
 // @Success      200  {object}  outputs.GetProductsResult
 // @Failure      400  {object}  errors.PublicPCCError
 // @Router       /products/ [get]
@@ -50,7 +62,7 @@ func (c *ProductController) getProducts(ctx *gin.Context) {
 
 	start := (input.Page * input.Count) - input.Count
 
-	products, amount, err := c.db.GetProducts(start, input.Count)
+	products, amount, err := c.db.GetProducts(start, &input)
 
 	if CheckErrorAndWriteBadRequest(ctx, err) {
 		return
@@ -95,7 +107,19 @@ func (c *ProductController) getProductById(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, outputs.NewProductWithChars(product, charsDesc))
+	availability, perr := c.db.GetProductAvailability(product.ID)
+
+	if CheckErrorAndWriteBadRequest(ctx, perr) {
+		return
+	}
+
+	lowestPrice, perr := c.db.GetLowestPrice(product.ID, LowestPriceWindow)
+
+	if CheckErrorAndWriteBadRequest(ctx, perr) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, outputs.NewProductWithChars(product, charsDesc, availability, lowestPrice))
 }
 
 // Get product 	characteristics
@@ -129,3 +153,100 @@ func (c *ProductController) getProductChars(ctx *gin.Context) {
 		"chars": chars,
 	})
 }
+
+// Update the price of the product. The change is written to the price history and
+// the subscribers are notified when the price drops.
+// The stock is changed by the inventory routes
+// @Summary      Update the price of the product
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Param		 Authorization  header	string						true	"access token"
+// @Param		 id				path	uint64						true	"Product ID"
+// @Param		 input			body	inputs.UpdateProductInput	true	"The changed fields"
+// @Success      200  {object}  models.Product
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /products/{id} [patch]
+func (c *ProductController) updateProduct(ctx *gin.Context) {
+	pu, err := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	id, perr := strconv.ParseUint(ctx.Param("id"), 10, 64)
+
+	if perr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(perr))
+		return
+	}
+
+	var input inputs.UpdateProductInput
+
+	if berr := ctx.ShouldBindBodyWithJSON(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	if input.Price != nil && input.Price.Minor < 0 {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.NewBindValidationError([]conerrors.ValError{
+			{Field: "Price", Tag: "min", Reason: conerrors.VFR_UNKNOWN},
+		}))
+		return
+	}
+
+	product, err := c.db.UpdateProduct(pu.ID, id, &input)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, product)
+}
+
+// Get the price history of the product for the charts
+// @Summary      Get the price history of the product, the oldest prices go first
+// @Tags         products
+// @Produce      json
+// @Param 		 id		path	uint64						true	"Product ID"
+// @Param 		 period	query	inputs.GetPriceHistoryInput	false	"The period in RFC 3339"
+// @Success      200  {object}  outputs.PriceHistoryResult
+// @Failure      400  {object}  errors.PublicPCCError
+// @Router       /products/{id}/price-history [get]
+func (c *ProductController) getPriceHistory(ctx *gin.Context) {
+	id, perr := strconv.ParseUint(ctx.Param("id"), 10, 64)
+
+	if perr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(perr))
+		return
+	}
+
+	var input inputs.GetPriceHistoryInput
+
+	if berr := ctx.ShouldBindQuery(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	product, err := c.db.GetProductById(id)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	history, err := c.db.GetPriceHistory(id, input.From, input.To)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	lowestPrice, err := c.db.GetLowestPrice(id, LowestPriceWindow)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, outputs.NewPriceHistoryResult(id, product.Price, lowestPrice, history))
+}
@@ -1,24 +1,53 @@
 package controllers
 
 import (
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
+	"github.com/PC-Core/pc-core-backend/internal/auth"
 	"github.com/PC-Core/pc-core-backend/internal/controllers/conerrors"
+	"github.com/PC-Core/pc-core-backend/internal/database"
 	"github.com/PC-Core/pc-core-backend/internal/errors"
 	"github.com/PC-Core/pc-core-backend/internal/helpers"
+	"github.com/PC-Core/pc-core-backend/internal/mailer"
+	"github.com/PC-Core/pc-core-backend/internal/static"
 	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	// AvatarMaxSize is the maximum size of the avatar in bytes
+	AvatarMaxSize = 5 << 20
+	// EmailVerificationLifetime is how long the link from the verification email is valid
+	EmailVerificationLifetime = 24 * time.Hour
+)
+
+// avatarExtensions maps the detected content types of the allowed avatars to the file extensions
+var avatarExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/webp": ".webp",
+}
+
 type ProfileController struct {
 	engine          *gin.Engine
 	pucaster        helpers.PublicUserCaster
 	auth_middleware gin.HandlerFunc
+	db              database.DbController
+	auth            auth.Auth
+	static          static.StaticDataController
+	mailer          mailer.Mailer
+	verifyEmailURL  string
 }
 
-func NewProfileController(engine *gin.Engine, pucaster helpers.PublicUserCaster, auth_middleware gin.HandlerFunc) *ProfileController {
+func NewProfileController(engine *gin.Engine, pucaster helpers.PublicUserCaster, auth_middleware gin.HandlerFunc, db database.DbController, auth auth.Auth, static static.StaticDataController, mailer mailer.Mailer, verifyEmailURL string) *ProfileController {
 	return &ProfileController{
-		engine, pucaster, auth_middleware,
+		engine, pucaster, auth_middleware, db, auth, static, mailer, verifyEmailURL,
 	}
 }
 
@@ -38,11 +67,57 @@ func (c *ProfileController) GetPubUser(ctx *gin.Context) (*models.PublicUser, er
 	return pu, err
 }
 
+// loadUser loads the current user from the database. The error is written if the user
+// is not authorized or is temporary, the temporary users have no profile
+func (c *ProfileController) loadUser(ctx *gin.Context) (*models.User, bool) {
+	pu, ok := GetRegisteredUser(ctx, c.pucaster)
+
+	if !ok {
+		return nil, false
+	}
+
+	user, err := c.db.GetUserByID(pu.ID)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return nil, false
+	}
+
+	return user, true
+}
+
 func (c *ProfileController) ApplyRoutes() {
 	group := c.engine.Group("/profile")
 	{
 		group.GET("/", c.auth_middleware, c.getProfile)
+		group.PATCH("/", c.auth_middleware, c.updateProfile)
+		group.POST("/avatar", c.auth_middleware, c.uploadAvatar)
+		group.POST("/email/verify", c.verifyEmail)
+
+		group.GET("/addresses", c.auth_middleware, c.getAddresses)
+		group.POST("/addresses", c.auth_middleware, c.addAddress)
+		group.PUT("/addresses/:id", c.auth_middleware, c.updateAddress)
+		group.DELETE("/addresses/:id", c.auth_middleware, c.deleteAddress)
+		group.POST("/addresses/:id/default", c.auth_middleware, c.setDefaultAddress)
+	}
+}
+
+func (c *ProfileController) loadProfile(user *models.User) (*models.Profile, errors.PCCError) {
+	pending, err := c.db.GetPendingEmail(user.ID)
+
+	if err != nil {
+		return nil, err
 	}
+
+	addresses, err := c.db.GetUserAddresses(user.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	profile := models.NewProfile(models.NewPublicUserFromUser(user), user.AvatarURL, pending, addresses)
+	profile.MfaEnabled = user.MfaEnabled
+
+	return profile, nil
 }
 
 // Get user profile
@@ -52,15 +127,413 @@ func (c *ProfileController) ApplyRoutes() {
 // @Produce      json
 // @Param 		 Authorization	header	string	true	"access token for authorization"
 // @Success      200  {object}  models.Profile
+// @Failure      400  {object}  errors.PublicPCCError
 // @Failure      401  {object}  errors.PublicPCCError
 // @Failure      403  {object}  errors.PublicPCCError
 // @Router       /profile/ [get]
 func (c *ProfileController) getProfile(ctx *gin.Context) {
-	user, err := c.GetPubUser(ctx)
+	pu, err := c.GetPubUser(ctx)
 
 	if CheckErrorAndWriteUnauthorized(ctx, err) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, models.NewProfile(user))
+	if pu.Role == models.Temporary {
+		ctx.JSON(http.StatusOK, models.NewProfile(pu, nil, nil, []models.Address{}))
+		return
+	}
+
+	user, err := c.db.GetUserByID(pu.ID)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	profile, err := c.loadProfile(user)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, profile)
+}
+
+// Update user profile. The email and the password can only be changed with
+// the current password provided. The new email is applied after the verification.
+// The password change ends all the sessions of the user, the current one as well
+// @Summary      Update user profile
+// @Tags         profile
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization	header	string						true	"access token for authorization"
+// @Param		 input			body	inputs.UpdateProfileInput	true	"Fields to change"
+// @Success      200  {object}  models.Profile
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /profile/ [patch]
+func (c *ProfileController) updateProfile(ctx *gin.Context) {
+	user, ok := c.loadUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	var input inputs.UpdateProfileInput
+
+	if berr := ctx.ShouldBindBodyWithJSON(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	emailChanged := input.Email != nil && *input.Email != user.Email
+
+	if emailChanged || input.Password != nil {
+		if input.OldPassword == nil || helpers.Sha256(*input.OldPassword) != user.PasswdHash {
+			CheckErrorAndWriteBadRequest(ctx, conerrors.NewWrongPasswordError())
+			return
+		}
+	}
+
+	var (
+		name         *string
+		passwordHash *string
+		email        *string
+		token        string
+	)
+
+	if input.Name != nil && *input.Name != user.Name {
+		name = input.Name
+	}
+
+	if input.Password != nil {
+		hash := helpers.Sha256(*input.Password)
+		passwordHash = &hash
+	}
+
+	if emailChanged {
+		var terr error
+
+		if token, terr = helpers.RandomToken(32); terr != nil {
+			CheckErrorAndWriteBadRequest(ctx, errors.NewInternalSecretError())
+			return
+		}
+
+		email = input.Email
+	}
+
+	err := c.db.UpdateUserProfile(user.ID, name, passwordHash, email, helpers.Sha256(token), time.Now().Add(EmailVerificationLifetime))
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	if name != nil {
+		user.Name = *name
+	}
+
+	// The tokens issued with the old password are rejected from now on
+	if passwordHash != nil {
+		if CheckErrorAndWriteBadRequest(ctx, c.auth.RevokeUserTokens(user.ID)) {
+			return
+		}
+	}
+
+	if emailChanged {
+		if CheckErrorAndWriteBadRequest(ctx, c.sendEmailVerification(user, *input.Email, token)) {
+			return
+		}
+	}
+
+	profile, err := c.loadProfile(user)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, profile)
+}
+
+// sendEmailVerification sends the link with the token of the saved pending email to the new address
+func (c *ProfileController) sendEmailVerification(user *models.User, email string, token string) errors.PCCError {
+	link := fmt.Sprintf("%s?token=%s", c.verifyEmailURL, url.QueryEscape(token))
+	body := fmt.Sprintf("Hello, %s!\n\nFollow the link to confirm your new email address:\n%s\n\nThe link is valid for %s.", user.Name, link, EmailVerificationLifetime)
+
+	return c.mailer.Send(email, "Confirm your email address", body)
+}
+
+// Confirm the new email with the token from the verification email
+// @Summary      Confirm the new email
+// @Tags         profile
+// @Accept       json
+// @Produce      json
+// @Param		 input	body	inputs.VerifyEmailInput	true	"Token from the verification email"
+// @Success      200  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Router       /profile/email/verify [post]
+func (c *ProfileController) verifyEmail(ctx *gin.Context) {
+	var input inputs.VerifyEmailInput
+
+	if berr := ctx.ShouldBindBodyWithJSON(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	ok, err := c.db.ConfirmEmailVerification(helpers.Sha256(input.Token))
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	if !ok {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.NewVerificationInvalidError())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "ok")
+}
+
+// Upload the avatar
+// @Summary      Upload the avatar
+// @Tags         profile
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param 		 Authorization	header		string	true	"access token for authorization"
+// @Param 		 avatar			formData	file	true	"PNG, JPEG or WebP image"
+// @Success      200  {object}  models.Profile
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /profile/avatar [post]
+func (c *ProfileController) uploadAvatar(ctx *gin.Context) {
+	user, ok := c.loadUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	header, ferr := ctx.FormFile("avatar")
+
+	if ferr != nil || header.Size > AvatarMaxSize {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.NewInvalidAvatarError(AvatarMaxSize))
+		return
+	}
+
+	file, ferr := header.Open()
+
+	if ferr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewInternalSecretError())
+		return
+	}
+
+	defer file.Close()
+
+	// The content type is detected by the content, the header from the client is not trusted
+	sniff := make([]byte, 512)
+	n, _ := io.ReadFull(file, sniff)
+	ctype := http.DetectContentType(sniff[:n])
+
+	ext, ok := avatarExtensions[ctype]
+
+	if !ok {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.NewInvalidAvatarError(AvatarMaxSize))
+		return
+	}
+
+	if _, serr := file.Seek(0, io.SeekStart); serr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewInternalSecretError())
+		return
+	}
+
+	suffix, terr := helpers.RandomToken(8)
+
+	if terr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewInternalSecretError())
+		return
+	}
+
+	name := fmt.Sprintf("avatars/%d/%s%s", user.ID, suffix, ext)
+
+	locs, err := c.static.UploadFiles([]static.StaticFile{*static.NewStaticFile(file, name, ctype)})
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.SetUserAvatar(user.ID, locs[0])) {
+		return
+	}
+
+	user.AvatarURL = &locs[0]
+
+	profile, err := c.loadProfile(user)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, profile)
+}
+
+// Get the delivery addresses. The default address goes first
+// @Summary      Get the delivery addresses
+// @Tags         profile
+// @Produce      json
+// @Param 		 Authorization	header	string	true	"access token for authorization"
+// @Success      200  {array}   models.Address
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /profile/addresses [get]
+func (c *ProfileController) getAddresses(ctx *gin.Context) {
+	user, ok := c.loadUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	addresses, err := c.db.GetUserAddresses(user.ID)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, addresses)
+}
+
+// Add the delivery address. The first address becomes the default one
+// @Summary      Add the delivery address
+// @Tags         profile
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization	header	string				true	"access token for authorization"
+// @Param		 input			body	inputs.AddressInput	true	"Address"
+// @Success      201  {object}  models.Address
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /profile/addresses [post]
+func (c *ProfileController) addAddress(ctx *gin.Context) {
+	user, ok := c.loadUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	var input inputs.AddressInput
+
+	if berr := ctx.ShouldBindBodyWithJSON(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	address, err := c.db.AddUserAddress(user.ID, &input)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, address)
+}
+
+// Update the delivery address
+// @Summary      Update the delivery address
+// @Tags         profile
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization	header	string				true	"access token for authorization"
+// @Param		 id				path	int					true	"Address ID"
+// @Param		 input			body	inputs.AddressInput	true	"Address"
+// @Success      200  {object}  models.Address
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /profile/addresses/{id} [put]
+func (c *ProfileController) updateAddress(ctx *gin.Context) {
+	user, ok := c.loadUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	id, perr := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if perr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(perr))
+		return
+	}
+
+	var input inputs.AddressInput
+
+	if berr := ctx.ShouldBindBodyWithJSON(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	address, err := c.db.UpdateUserAddress(user.ID, id, &input)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, address)
+}
+
+// Delete the delivery address
+// @Summary      Delete the delivery address
+// @Tags         profile
+// @Produce      json
+// @Param 		 Authorization	header	string	true	"access token for authorization"
+// @Param		 id				path	int		true	"Address ID"
+// @Success      200  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /profile/addresses/{id} [delete]
+func (c *ProfileController) deleteAddress(ctx *gin.Context) {
+	user, ok := c.loadUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	id, perr := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if perr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(perr))
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.DeleteUserAddress(user.ID, id)) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "ok")
+}
+
+// Make the delivery address the default one
+// @Summary      Make the delivery address the default one
+// @Tags         profile
+// @Produce      json
+// @Param 		 Authorization	header	string	true	"access token for authorization"
+// @Param		 id				path	int		true	"Address ID"
+// @Success      200  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /profile/addresses/{id}/default [post]
+func (c *ProfileController) setDefaultAddress(ctx *gin.Context) {
+	user, ok := c.loadUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	id, perr := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if perr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(perr))
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.SetDefaultUserAddress(user.ID, id)) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "ok")
 }
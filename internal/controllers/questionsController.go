@@ -0,0 +1,343 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/PC-Core/pc-core-backend/internal/controllers/conerrors"
+	"github.com/PC-Core/pc-core-backend/internal/database"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/helpers"
+	"github.com/PC-Core/pc-core-backend/internal/middlewares"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"github.com/PC-Core/pc-core-backend/pkg/models/outputs"
+	"github.com/gin-gonic/gin"
+)
+
+// QuestionsController serves the questions about the products. They are kept apart
+// from the reviews and don't affect the rating
+type QuestionsController struct {
+	engine                       *gin.Engine
+	db                           database.DbController
+	auth_middleware              gin.HandlerFunc
+	auth_not_required_middleware gin.HandlerFunc
+	pucaster                     helpers.PublicUserCaster
+}
+
+func NewQuestionsController(engine *gin.Engine, db database.DbController, auth_middleware gin.HandlerFunc, auth_not_req_middleware gin.HandlerFunc, pucaster helpers.PublicUserCaster) *QuestionsController {
+	return &QuestionsController{
+		engine,
+		db,
+		auth_middleware,
+		auth_not_req_middleware,
+		pucaster,
+	}
+}
+
+func (c *QuestionsController) ApplyRoutes() {
+	g := c.engine.Group("/questions")
+	{
+		g.GET("/product/:id", c.getQuestions)
+		g.POST("/product/:id", c.auth_middleware, c.addQuestion)
+		g.GET("/:id", c.auth_not_required_middleware, c.getAnswers)
+		g.DELETE("/:id", c.auth_middleware, c.deleteQuestion)
+		g.POST("/:id/answers", c.auth_middleware, c.addAnswer)
+		g.PUT("/:id/accepted", c.auth_middleware, c.setAcceptedAnswer)
+		g.POST("/answers/:id/upvote", c.auth_middleware, c.upvoteAnswer)
+		g.DELETE("/answers/:id", c.auth_middleware, c.deleteAnswer)
+	}
+}
+
+// Get product questions
+// @Summary      Get the questions about the product with their accepted answers, the newest go first
+// @Tags         questions
+// @Produce      json
+// @Param 		 id 	path	int							true	"ID of the product"
+// @Param		 input	query	inputs.GetQuestionsInput	true	"Page and count"
+// @Success      200  {object}  outputs.GetQuestionsResult
+// @Failure      400  {object}  errors.PublicPCCError
+// @Router       /questions/product/{id} [get]
+func (c *QuestionsController) getQuestions(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(err))
+		return
+	}
+
+	var input inputs.GetQuestionsInput
+
+	if err := ctx.ShouldBindQuery(&input); err != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(err))
+		return
+	}
+
+	start := (input.Page * input.Count) - input.Count
+
+	questions, amount, perr := c.db.GetProductQuestions(id, input.Unanswered, start, input.Count)
+
+	if CheckErrorAndWriteBadRequest(ctx, perr) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, outputs.NewGetQuestionsResult(questions, amount, input.Page))
+}
+
+// Ask question
+// @Summary      Ask the question about the product. The asker is notified about the answers
+// @Tags         questions
+// @Accept       json
+// @Produce      json
+// @Param 		 id 			path	int						true	"ID of the product"
+// @Param		 Authorization  header	string					true	"access token"
+// @Param		 input			body	inputs.AddQuestionInput	true	"input"
+// @Success      201  {object} 	int
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /questions/product/{id} [post]
+func (c *QuestionsController) addQuestion(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(err))
+		return
+	}
+
+	pu, ok := GetRegisteredUser(ctx, c.pucaster)
+
+	if !ok {
+		return
+	}
+
+	var input inputs.AddQuestionInput
+
+	if err := ctx.ShouldBindBodyWithJSON(&input); err != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(err))
+		return
+	}
+
+	newID, perr := c.db.AddQuestion(&input, int64(pu.ID), id)
+
+	if CheckErrorAndWriteBadRequest(ctx, perr) {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newID)
+}
+
+// Get question answers
+// @Summary      Get the question and its answers. The accepted answer goes first, the others are ordered by the upvotes
+// @Tags         questions
+// @Produce      json
+// @Param 		 id 			path	int								true	"ID of the question"
+// @Param		 Authorization  header	string							false	"access token for user is used to check your upvotes, is not required"
+// @Param		 input			query	inputs.GetQuestionAnswersInput	true	"Page and count"
+// @Success      200  {object}  outputs.GetQuestionAnswersResult
+// @Failure      400  {object}  errors.PublicPCCError
+// @Router       /questions/{id} [get]
+func (c *QuestionsController) getAnswers(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(err))
+		return
+	}
+
+	var input inputs.GetQuestionAnswersInput
+
+	if err := ctx.ShouldBindQuery(&input); err != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(err))
+		return
+	}
+
+	start := (input.Page * input.Count) - input.Count
+	userID := GetNotRequiredUserID(ctx, c.pucaster)
+
+	question, answers, amount, perr := c.db.GetQuestionAnswers(id, userID, start, input.Count)
+
+	if CheckErrorAndWriteBadRequest(ctx, perr) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, outputs.NewGetQuestionAnswersResult(question, answers, amount, input.Page))
+}
+
+// Delete question
+// @Summary      Delete your question. The moderators can delete any question
+// @Tags         questions
+// @Produce      json
+// @Param 		 id 			path	int		true	"ID of the question"
+// @Param		 Authorization  header	string	true	"access token"
+// @Success      200  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /questions/{id} [delete]
+func (c *QuestionsController) deleteQuestion(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(err))
+		return
+	}
+
+	pu, perr := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, perr) {
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.DeleteQuestion(id, int64(pu.ID), middlewares.HasPermission(pu, models.PermissionCommentsModerate))) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "ok")
+}
+
+// Answer question
+// @Summary      Answer the question. The answers of the staff and of the buyers of the product are highlighted
+// @Tags         questions
+// @Accept       json
+// @Produce      json
+// @Param 		 id 			path	int								true	"ID of the question"
+// @Param		 Authorization  header	string							true	"access token"
+// @Param		 input			body	inputs.AddQuestionAnswerInput	true	"input"
+// @Success      201  {object} 	int
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /questions/{id}/answers [post]
+func (c *QuestionsController) addAnswer(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(err))
+		return
+	}
+
+	pu, ok := GetRegisteredUser(ctx, c.pucaster)
+
+	if !ok {
+		return
+	}
+
+	var input inputs.AddQuestionAnswerInput
+
+	if err := ctx.ShouldBindBodyWithJSON(&input); err != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(err))
+		return
+	}
+
+	newID, perr := c.db.AddQuestionAnswer(&input, id, int64(pu.ID))
+
+	if CheckErrorAndWriteBadRequest(ctx, perr) {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newID)
+}
+
+// Accept answer
+// @Summary      Mark the answer on your question as accepted or remove the mark. The moderators can do it on any question
+// @Tags         questions
+// @Accept       json
+// @Produce      json
+// @Param 		 id 			path	int							true	"ID of the question"
+// @Param		 Authorization  header	string						true	"access token"
+// @Param		 input			body	inputs.AcceptAnswerInput	true	"input"
+// @Success      200  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /questions/{id}/accepted [put]
+func (c *QuestionsController) setAcceptedAnswer(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(err))
+		return
+	}
+
+	pu, perr := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, perr) {
+		return
+	}
+
+	var input inputs.AcceptAnswerInput
+
+	if err := ctx.ShouldBindBodyWithJSON(&input); err != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(err))
+		return
+	}
+
+	perr = c.db.SetAcceptedAnswer(id, input.AnswerID, int64(pu.ID), middlewares.HasPermission(pu, models.PermissionCommentsModerate))
+
+	if CheckErrorAndWriteBadRequest(ctx, perr) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "ok")
+}
+
+// Upvote answer
+// @Summary      Upvote the answer or remove the upvote. Returns the answer with the updated upvotes
+// @Tags         questions
+// @Produce      json
+// @Param 		 id 			path	int		true	"ID of the answer"
+// @Param		 Authorization  header	string	true	"access token"
+// @Success      200  {object} 	models.QuestionAnswer
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /questions/answers/{id}/upvote [post]
+func (c *QuestionsController) upvoteAnswer(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(err))
+		return
+	}
+
+	pu, ok := GetRegisteredUser(ctx, c.pucaster)
+
+	if !ok {
+		return
+	}
+
+	answer, perr := c.db.UpvoteAnswer(id, int64(pu.ID))
+
+	if CheckErrorAndWriteBadRequest(ctx, perr) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, answer)
+}
+
+// Delete answer
+// @Summary      Delete your answer. The moderators can delete any answer
+// @Tags         questions
+// @Produce      json
+// @Param 		 id 			path	int		true	"ID of the answer"
+// @Param		 Authorization  header	string	true	"access token"
+// @Success      200  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /questions/answers/{id} [delete]
+func (c *QuestionsController) deleteAnswer(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(err))
+		return
+	}
+
+	pu, perr := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, perr) {
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.DeleteQuestionAnswer(id, int64(pu.ID), middlewares.HasPermission(pu, models.PermissionCommentsModerate))) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "ok")
+}
@@ -2,16 +2,23 @@ package controllers
 
 import (
 	"net/http"
+	"regexp"
 	"strconv"
 
 	"github.com/PC-Core/pc-core-backend/internal/controllers/conerrors"
 	"github.com/PC-Core/pc-core-backend/internal/database"
 	"github.com/PC-Core/pc-core-backend/internal/errors"
 	"github.com/PC-Core/pc-core-backend/internal/helpers"
+	"github.com/PC-Core/pc-core-backend/internal/middlewares"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
 	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"github.com/PC-Core/pc-core-backend/pkg/models/outputs"
 	"github.com/gin-gonic/gin"
 )
 
+// reactionNameRegexp is the format of the reaction names, it is checked by the database as well
+var reactionNameRegexp = regexp.MustCompile(`^[a-z_]{1,32}$`)
+
 type ReactionsController struct {
 	engine          *gin.Engine
 	db              database.DbController
@@ -31,22 +38,114 @@ func NewReactionsController(engine *gin.Engine, db database.DbController, auth_m
 func (c *ReactionsController) ApplyRoutes() {
 	gr := c.engine.Group("/reactions")
 	{
+		gr.GET("/types", c.getReactionTypes)
+		gr.PUT("/types/:name", c.auth_middleware, middlewares.RequirePermission(models.PermissionCommentsModerate, c.pucaster), c.setReactionType)
+		gr.GET("/:id", c.getReactions)
 		gr.POST("/:id", c.auth_middleware, c.setReaction)
 	}
 }
 
+// Get the reaction types
+// @Summary      Get the reactions the users can choose, ordered by the position
+// @Tags         reactions
+// @Produce      json
+// @Success      200  {array}   models.ReactionTypeInfo
+// @Failure      400  {object}  errors.PublicPCCError
+// @Router       /reactions/types [get]
+func (c *ReactionsController) getReactionTypes(ctx *gin.Context) {
+	types, err := c.db.GetReactionTypes(false)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types)
+}
+
+// Add or change the reaction type
+// @Summary      Add the reaction type or change the existing one. The inactive reactions can't be added to the comments
+// @Tags         reactions
+// @Accept       json
+// @Produce      json
+// @Param		 Authorization  header	string						true	"access token"
+// @Param		 name			path	string						true	"Name of the reaction"
+// @Param		 input			body	inputs.SetReactionTypeInput	true	"Input"
+// @Success      200  {object}  models.ReactionTypeInfo
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /reactions/types/{name} [put]
+func (c *ReactionsController) setReactionType(ctx *gin.Context) {
+	name := ctx.Param("name")
+
+	if !reactionNameRegexp.MatchString(name) {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.NewBindValidationError([]conerrors.ValError{{Field: "name", Tag: "format", Reason: conerrors.VFR_UNKNOWN}}))
+		return
+	}
+
+	var input inputs.SetReactionTypeInput
+
+	if err := ctx.ShouldBindBodyWithJSON(&input); err != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(err))
+		return
+	}
+
+	reactionType, err := c.db.SetReactionType(models.ReactionType(name), &input)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reactionType)
+}
+
+// Get the reactions of the comment
+// @Summary      Get who reacted to the comment, the latest reactions go first
+// @Tags         reactions
+// @Produce      json
+// @Param		 id		path	int							true	"ID of the comment"
+// @Param		 input	query	inputs.GetReactionsInput	true	"Type, page and count"
+// @Success      200  {object}  outputs.GetReactionsResult
+// @Failure      400  {object}  errors.PublicPCCError
+// @Router       /reactions/{id} [get]
+func (c *ReactionsController) getReactions(ctx *gin.Context) {
+	id, perr := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if perr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(perr))
+		return
+	}
+
+	var input inputs.GetReactionsInput
+
+	if err := ctx.ShouldBindQuery(&input); err != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(err))
+		return
+	}
+
+	start := (input.Page * input.Count) - input.Count
+
+	reactions, amount, err := c.db.GetReactions(id, input.Type, start, input.Count)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, outputs.NewGetReactionsResult(reactions, amount, input.Page))
+}
+
 // Add, change or delete reaction from a comment
-// @Summary      Add, change or delete reaction from a comment
+// @Summary      Add, change or delete reaction from a comment. Returns the reactions of the comment after the change
 // @Tags         reactions
 // @Accept       json
 // @Produce      json
-// @Param 		 comment_id 	query	int						true	"ID of the comment"
+// @Param 		 id 			path	int						true	"ID of the comment"
 // @param		 input			body	inputs.SetReactionInput	true	"Input"
-// @Param		 Authorization  header	string					true	"access token for user is used to check your reaction, is not required"
-// @Success      200  {object} 	int
+// @Param		 Authorization  header	string					true	"access token"
+// @Success      200  {object} 	models.CommentReactions
 // @Failure      400  {object}  errors.PublicPCCError
 // @Failure      403  {object}  errors.PublicPCCError
-// @Router       /reactions/:id [post]
+// @Router       /reactions/{id} [post]
 func (c *ReactionsController) setReaction(ctx *gin.Context) {
 	pu, err := GetPubUser(ctx, c.pucaster)
 
@@ -58,7 +157,7 @@ func (c *ReactionsController) setReaction(ctx *gin.Context) {
 
 	id, perr := strconv.ParseInt(idStr, 10, 64)
 
-	if perr != nil && CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(err)) {
+	if perr != nil && CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(perr)) {
 		return
 	}
 
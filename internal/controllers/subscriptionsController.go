@@ -0,0 +1,267 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/PC-Core/pc-core-backend/internal/controllers/conerrors"
+	"github.com/PC-Core/pc-core-backend/internal/database"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/helpers"
+	"github.com/PC-Core/pc-core-backend/internal/notifications"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"github.com/PC-Core/pc-core-backend/pkg/models/outputs"
+	"github.com/gin-gonic/gin"
+)
+
+// UnsubscribeTokenBytes is the amount of the random bytes in the unsubscribe link token
+const UnsubscribeTokenBytes = 24
+
+type SubscriptionsController struct {
+	engine          *gin.Engine
+	db              database.DbController
+	pucaster        helpers.PublicUserCaster
+	auth_middleware gin.HandlerFunc
+}
+
+func NewSubscriptionsController(engine *gin.Engine, db database.DbController, pucaster helpers.PublicUserCaster, auth_middleware gin.HandlerFunc) *SubscriptionsController {
+	return &SubscriptionsController{
+		engine, db, pucaster, auth_middleware,
+	}
+}
+
+func (c *SubscriptionsController) ApplyRoutes() {
+	c.engine.POST("/subscriptions/unsubscribe", c.unsubscribeByToken)
+	c.engine.POST("/products/:id/subscriptions", c.auth_middleware, c.subscribe)
+
+	gr := c.engine.Group("/", c.auth_middleware)
+	{
+		gr.GET("/subscriptions", c.getSubscriptions)
+		gr.DELETE("/subscriptions/:id", c.unsubscribe)
+		gr.GET("/notifications", c.getNotifications)
+		gr.POST("/notifications/:id/read", c.markRead)
+	}
+}
+
+// checkSubscribeInput validates the fields which depend on the kind and the channel
+func checkSubscribeInput(input *inputs.SubscribeInput) errors.PCCError {
+	details := make([]conerrors.ValError, 0)
+
+	if input.Kind == models.SubscriptionPriceDrop && (input.PriceThreshold == nil || input.PriceThreshold.Minor <= 0) {
+		details = append(details, conerrors.ValError{Field: "PriceThreshold", Tag: "required", Reason: conerrors.VFR_REQUIRED})
+	}
+
+	if input.Channel == models.NotificationWebhook {
+		if input.WebhookURL == nil {
+			details = append(details, conerrors.ValError{Field: "WebhookURL", Tag: "required", Reason: conerrors.VFR_REQUIRED})
+		} else if notifications.CheckWebhookURL(*input.WebhookURL) != nil {
+			details = append(details, conerrors.ValError{Field: "WebhookURL", Tag: "url", Reason: conerrors.VFR_UNKNOWN})
+		}
+	}
+
+	if len(details) != 0 {
+		return conerrors.NewBindValidationError(details)
+	}
+
+	return nil
+}
+
+// Subscribe to the product
+// @Summary      Subscribe to the product becoming available again or its price dropping
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization	header	string					true	"access token for authorization"
+// @Param		 id				path	int						true	"Product ID"
+// @Param		 input			body	inputs.SubscribeInput	true	"Subscription"
+// @Success      201  {object}  models.ProductSubscription
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /products/{id}/subscriptions [post]
+func (c *SubscriptionsController) subscribe(ctx *gin.Context) {
+	pu, ok := GetRegisteredUser(ctx, c.pucaster)
+
+	if !ok {
+		return
+	}
+
+	id, perr := strconv.ParseUint(ctx.Param("id"), 10, 64)
+
+	if perr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(perr))
+		return
+	}
+
+	var input inputs.SubscribeInput
+
+	if berr := ctx.ShouldBindBodyWithJSON(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, checkSubscribeInput(&input)) {
+		return
+	}
+
+	token, terr := helpers.RandomToken(UnsubscribeTokenBytes)
+
+	if terr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewInternalSecretError())
+		return
+	}
+
+	subscription, err := c.db.AddProductSubscription(pu.ID, id, &input, token)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, subscription)
+}
+
+// Get user's subscriptions
+// @Summary      Get user's subscriptions
+// @Tags         subscriptions
+// @Produce      json
+// @Param 		 Authorization	header	string	true	"access token for authorization"
+// @Success      200  {array}   models.ProductSubscription
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /subscriptions [get]
+func (c *SubscriptionsController) getSubscriptions(ctx *gin.Context) {
+	pu, ok := GetRegisteredUser(ctx, c.pucaster)
+
+	if !ok {
+		return
+	}
+
+	subscriptions, err := c.db.GetUserSubscriptions(pu.ID)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subscriptions)
+}
+
+// Delete the subscription
+// @Summary      Delete the subscription
+// @Tags         subscriptions
+// @Produce      json
+// @Param 		 Authorization	header	string	true	"access token for authorization"
+// @Param		 id				path	int		true	"Subscription ID"
+// @Success      200  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /subscriptions/{id} [delete]
+func (c *SubscriptionsController) unsubscribe(ctx *gin.Context) {
+	pu, ok := GetRegisteredUser(ctx, c.pucaster)
+
+	if !ok {
+		return
+	}
+
+	id, perr := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if perr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(perr))
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.DeleteUserSubscription(pu.ID, id)) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "ok")
+}
+
+// Delete the subscription by the token from the unsubscribe link
+// @Summary      Delete the subscription by the token from the unsubscribe link
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Param		 input	body	inputs.UnsubscribeInput	true	"Unsubscribe token"
+// @Success      200  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Router       /subscriptions/unsubscribe [post]
+func (c *SubscriptionsController) unsubscribeByToken(ctx *gin.Context) {
+	var input inputs.UnsubscribeInput
+
+	if berr := ctx.ShouldBindBodyWithJSON(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.DeleteSubscriptionByToken(input.Token)) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "ok")
+}
+
+// Get user's in-app notifications
+// @Summary      Get user's in-app notifications
+// @Tags         subscriptions
+// @Produce      json
+// @Param 		 Authorization	header	string							true	"access token for authorization"
+// @Param		 page			query	inputs.GetNotificationsInput	true	"Page and count"
+// @Success      200  {object}  outputs.GetNotificationsResult
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /notifications [get]
+func (c *SubscriptionsController) getNotifications(ctx *gin.Context) {
+	pu, ok := GetRegisteredUser(ctx, c.pucaster)
+
+	if !ok {
+		return
+	}
+
+	var input inputs.GetNotificationsInput
+
+	if berr := ctx.ShouldBindQuery(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	start := (input.Page * input.Count) - input.Count
+
+	items, amount, err := c.db.GetUserNotifications(pu.ID, start, input.Count)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, outputs.NewGetNotificationsResult(items, amount, input.Page))
+}
+
+// Mark the in-app notification as read
+// @Summary      Mark the in-app notification as read
+// @Tags         subscriptions
+// @Produce      json
+// @Param 		 Authorization	header	string	true	"access token for authorization"
+// @Param		 id				path	int		true	"Notification ID"
+// @Success      200  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /notifications/{id}/read [post]
+func (c *SubscriptionsController) markRead(ctx *gin.Context) {
+	pu, ok := GetRegisteredUser(ctx, c.pucaster)
+
+	if !ok {
+		return
+	}
+
+	id, perr := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if perr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(perr))
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.MarkNotificationRead(pu.ID, id)) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "ok")
+}
@@ -1,9 +1,11 @@
 package controllers
 
 import (
+	"log"
 	"net/http"
 
 	"github.com/PC-Core/pc-core-backend/internal/auth"
+	"github.com/PC-Core/pc-core-backend/internal/auth/totp/terrors"
 	"github.com/PC-Core/pc-core-backend/internal/controllers/conerrors"
 	"github.com/PC-Core/pc-core-backend/internal/database"
 	"github.com/PC-Core/pc-core-backend/internal/errors"
@@ -16,23 +18,25 @@ import (
 )
 
 type UserController struct {
-	engine *gin.Engine
-	db     database.DbController
-	rctrl  *redis.RedisController
-	auth   auth.Auth
+	engine   *gin.Engine
+	db       database.DbController
+	rctrl    *redis.RedisController
+	auth     auth.Auth
+	pucaster helpers.PublicUserCaster
 }
 
 const CookieUseHttps = false
 
-func NewUserController(engine *gin.Engine, db database.DbController, rctrl *redis.RedisController, auth auth.Auth) *UserController {
+func NewUserController(engine *gin.Engine, db database.DbController, rctrl *redis.RedisController, auth auth.Auth, pucaster helpers.PublicUserCaster) *UserController {
 	return &UserController{
-		engine, db, rctrl, auth,
+		engine, db, rctrl, auth, pucaster,
 	}
 }
 
 func (c *UserController) ApplyRoutes() {
 	c.engine.POST("/users/register", c.registerUser)
 	c.engine.POST("/users/login", c.loginUser)
+	c.engine.POST("/users/login/mfa", c.loginUserMfa)
 	c.engine.POST("/users/temp/new", c.createTempUser)
 	c.engine.GET("/users/logout", c.logoutUser)
 }
@@ -64,18 +68,21 @@ func (c *UserController) registerUser(ctx *gin.Context) {
 
 	if err != nil {
 		CheckErrorAndWriteBadRequest(ctx, errors.NewInternalSecretError())
+		return
 	}
 
-	sendAuthData(ctx, res, http.StatusCreated, models.NewPublicUserFromUser(user), input.Remember)
+	sendAuthData(ctx, res, http.StatusCreated, models.NewPublicUserFromUser(user), input.Remember, c.mergeTempUser(user.ID, input.TempToken))
 }
 
-// Login
+// Login. If the user has the MFA enabled, the challenge token is returned
+// instead of the auth data. The token should be sent to /users/login/mfa
 // @Summary      Login
 // @Tags         users
 // @Accept       json
 // @Produce      json
 // @Param 		 user	body inputs.LoginUserInput	true	"User data to login"
 // @Success      200  {object}  outputs.LoginResult
+// @Success      202  {object}  outputs.MfaChallengeResult
 // @Failure      400  {object}  errors.PublicPCCError
 // @Router       /users/login [post]
 func (c *UserController) loginUser(ctx *gin.Context) {
@@ -93,18 +100,231 @@ func (c *UserController) loginUser(ctx *gin.Context) {
 		return
 	}
 
+	if user.Disabled {
+		CheckErrorAndWriteUnauthorized(ctx, conerrors.NewUserDisabledError())
+		return
+	}
+
+	if user.MfaEnabled {
+		challenge, err := c.auth.CreateMfaChallenge(user.ID)
+
+		if CheckErrorAndWriteBadRequest(ctx, err) {
+			return
+		}
+
+		ctx.JSON(http.StatusAccepted, outputs.NewMfaChallengeResult(challenge))
+		return
+	}
+
 	res, err := c.auth.Authentificate(models.NewPublicUserFromUser(user))
 
 	if CheckErrorAndWriteBadRequest(ctx, err) {
 		return
 	}
 
-	sendAuthData(ctx, res, http.StatusOK, models.NewPublicUserFromUser(user), input.Remember)
+	sendAuthData(ctx, res, http.StatusOK, models.NewPublicUserFromUser(user), input.Remember, c.mergeTempUser(user.ID, input.TempToken))
+}
+
+// Finish the login with the second factor
+// @Summary      Finish the login with the second factor
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param 		 input	body inputs.LoginMfaInput	true	"Challenge token from /users/login and the TOTP or recovery code"
+// @Success      200  {object}  outputs.LoginResult
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /users/login/mfa [post]
+func (c *UserController) loginUserMfa(ctx *gin.Context) {
+	var input inputs.LoginMfaInput
+
+	if berr := ctx.ShouldBindJSON(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	id, challengeID, err := c.auth.ValidateMfaChallenge(input.ChallengeToken)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	// The attempt is counted before the check, so the parallel requests can't exceed the limit
+	attempts, err := c.rctrl.AddMfaChallengeAttempt(challengeID, auth.AuthMfaChallengeLifetime)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	if attempts > auth.MfaChallengeMaxAttempts {
+		CheckErrorAndWriteUnauthorized(ctx, terrors.NewChallengeSpentError())
+		return
+	}
+
+	user, err := c.db.GetUserByID(id)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	if user.Disabled {
+		CheckErrorAndWriteUnauthorized(ctx, conerrors.NewUserDisabledError())
+		return
+	}
+
+	if CheckErrorAndWriteUnauthorized(ctx, verifySecondFactor(c.db, user, &input.MfaCodeInput)) {
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.rctrl.SpendMfaChallenge(challengeID, auth.MfaChallengeMaxAttempts, auth.AuthMfaChallengeLifetime)) {
+		return
+	}
+
+	pu := models.NewPublicUserFromUser(user)
+	pu.Mfa = true
+
+	res, err := c.auth.Authentificate(pu)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	sendAuthData(ctx, res, http.StatusOK, pu, input.Remember, c.mergeTempUser(user.ID, input.TempToken))
+}
+
+// tempMerge is the outcome of the merge of the temporary user
+type tempMerge struct {
+	cart     *outputs.CartMergeResult
+	wishlist *outputs.WishlistMergeResult
+	failed   bool
 }
 
-func sendAuthData(ctx *gin.Context, ad *models.AuthData, status int, user *models.PublicUser, remember *bool) {
+func sendAuthData(ctx *gin.Context, ad *models.AuthData, status int, user *models.PublicUser, remember *bool, merge tempMerge) {
 	setRefreshCookie(ctx, ad.GetPrivate().String(), remember, int(auth.AuthPrivateCookieLifetime.Seconds()))
-	ctx.JSON(status, outputs.NewLoginResult(user, outputs.TokensMap{"access": ad.GetPublic().String()}))
+
+	res := outputs.NewLoginResult(user, outputs.TokensMap{"access": ad.GetPublic().String()})
+	res.CartMerge = merge.cart
+	res.WishlistMerge = merge.wishlist
+	res.MergeFailed = merge.failed
+
+	ctx.JSON(status, res)
+}
+
+// mergeTempUser moves the cart and the wishlist of the temporary user into the account
+// of the registered one. The invalid or expired temporary token is ignored as there is
+// nothing to merge. The temporary cart is taken at once, so the concurrent logins can't
+// merge it twice, and it is put back if the merge fails.
+//
+// The merge is best-effort: the user is already authenticated, so the failed part is logged
+// and reported, and the temporary user is kept, so the merge can be retried with its token.
+// The temporary user is deleted after both parts are merged
+func (c *UserController) mergeTempUser(userID int, tempToken *string) tempMerge {
+	var merge tempMerge
+
+	if tempToken == nil {
+		return merge
+	}
+
+	data, err := c.auth.Authorize(*tempToken)
+
+	if err != nil {
+		return merge
+	}
+
+	temp, err := c.pucaster(data)
+
+	if err != nil || temp.Role != models.Temporary {
+		return merge
+	}
+
+	if merge.cart, err = c.mergeTempCart(userID, temp.ID); err != nil {
+		log.Printf("temp user %d cart merge into user %d: %s", temp.ID, userID, err.Error())
+		merge.failed = true
+	}
+
+	if merge.wishlist, err = c.mergeTempWishlist(userID, temp.ID); err != nil {
+		log.Printf("temp user %d wishlist merge into user %d: %s", temp.ID, userID, err.Error())
+		merge.failed = true
+	}
+
+	if merge.failed || merge.cart == nil || merge.wishlist == nil {
+		return merge
+	}
+
+	// Both parts are already merged, the left temporary user has nothing to merge again
+	if err := c.rctrl.DeleteTempUser(uint64(temp.ID)); err != nil {
+		log.Printf("temp user %d delete: %s", temp.ID, err.Error())
+	}
+
+	return merge
+}
+
+// isTempUserExpired checks if the temporary user expired between the token check and the merge
+func isTempUserExpired(err errors.PCCError) bool {
+	return err.GetErrorCode() == errors.EC_REDIS_TEMP_USER_EXPIRED
+}
+
+func (c *UserController) mergeTempCart(userID int, tempID int) (*outputs.CartMergeResult, errors.PCCError) {
+	items, err := c.rctrl.TakeCart(uint64(tempID))
+
+	if err != nil {
+		if isTempUserExpired(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	conflicts, err := c.db.MergeCart(uint64(userID), items)
+
+	if err != nil {
+		if rerr := c.rctrl.RestoreCart(uint64(tempID), items); rerr != nil {
+			log.Printf("temp cart %d restore: %s", tempID, rerr.Error())
+		}
+
+		return nil, err
+	}
+
+	return outputs.NewCartMergeResult(len(items)-countEmptyMerges(conflicts), conflicts), nil
+}
+
+// mergeTempWishlist merges the temporary wishlist. The merge adds the missing products
+// only, so merging the same wishlist twice changes nothing
+func (c *UserController) mergeTempWishlist(userID int, tempID int) (*outputs.WishlistMergeResult, errors.PCCError) {
+	items, err := c.rctrl.GetWishlist(uint64(tempID))
+
+	if err != nil {
+		if isTempUserExpired(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	merged, err := c.db.MergeWishlist(userID, items)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.rctrl.DeleteWishlist(uint64(tempID)); err != nil {
+		return nil, err
+	}
+
+	return outputs.NewWishlistMergeResult(merged), nil
+}
+
+// countEmptyMerges returns the amount of the items which were not added at all
+func countEmptyMerges(conflicts []models.CartMergeConflict) int {
+	amount := 0
+
+	for _, conflict := range conflicts {
+		if conflict.Merged == 0 {
+			amount++
+		}
+	}
+
+	return amount
 }
 
 func (c *UserController) createTempUser(ctx *gin.Context) {
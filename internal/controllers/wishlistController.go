@@ -0,0 +1,340 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/PC-Core/pc-core-backend/internal/controllers/conerrors"
+	"github.com/PC-Core/pc-core-backend/internal/database"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/helpers"
+	"github.com/PC-Core/pc-core-backend/internal/middlewares"
+	"github.com/PC-Core/pc-core-backend/internal/redis"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"github.com/PC-Core/pc-core-backend/pkg/models/outputs"
+	"github.com/gin-gonic/gin"
+)
+
+// WishlistShareTokenBytes is the amount of the random bytes in the public link token
+const WishlistShareTokenBytes = 24
+
+type WishlistController struct {
+	engine          *gin.Engine
+	db              database.DbController
+	rctrl           *redis.RedisController
+	pucaster        helpers.PublicUserCaster
+	auth_middleware gin.HandlerFunc
+}
+
+func NewWishlistController(engine *gin.Engine, db database.DbController, rctrl *redis.RedisController, pucaster helpers.PublicUserCaster, auth_middleware gin.HandlerFunc) *WishlistController {
+	return &WishlistController{
+		engine, db, rctrl, pucaster, auth_middleware,
+	}
+}
+
+func (c *WishlistController) ApplyRoutes() {
+	c.engine.GET("/wishlist/shared/:token", c.getSharedWishlist)
+
+	gr := c.engine.Group("/wishlist", c.auth_middleware)
+	{
+		gr.GET("/", c.getWishlist)
+		gr.GET("/share", c.getShare)
+		gr.POST("/share", c.share)
+		gr.DELETE("/share", c.unshare)
+		gr.POST("/:id", c.addToWishlist)
+		gr.DELETE("/:id", c.removeFromWishlist)
+		gr.POST("/:id/cart", c.moveToCart)
+	}
+
+	c.engine.GET("/admin/wishlist/counts", c.auth_middleware, middlewares.RequirePermission(models.PermissionCatalogWrite, c.pucaster), c.getWishlistCounts)
+}
+
+func (c *WishlistController) getTempWishlist(pu *models.PublicUser) ([]models.WishlistItem, errors.PCCError) {
+	items, err := c.rctrl.GetWishlist(uint64(pu.ID))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return c.db.LoadProductsRangeAsWishlistItem(items)
+}
+
+// Get user's wishlist
+// @Summary      Get user's wishlist
+// @Tags         wishlist
+// @Produce      json
+// @Param 		 Authorization	header	string	true	"access token for authorization"
+// @Success      200  {object}  models.Wishlist
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /wishlist/ [get]
+func (c *WishlistController) getWishlist(ctx *gin.Context) {
+	pu, err := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	var items []models.WishlistItem
+
+	switch pu.Role {
+	case models.Temporary:
+		items, err = c.getTempWishlist(pu)
+	default:
+		items, err = c.db.GetWishlist(pu.ID)
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewWishlist(items))
+}
+
+// Save the product to the wishlist
+// @Summary      Save the product to the wishlist
+// @Tags         wishlist
+// @Produce      json
+// @Param 		 Authorization	header	string	true	"access token for authorization"
+// @Param		 id				path	int		true	"Product ID"
+// @Success      201  {object}  uint64
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /wishlist/{id} [post]
+func (c *WishlistController) addToWishlist(ctx *gin.Context) {
+	pu, err := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	id, perr := strconv.ParseUint(ctx.Param("id"), 10, 64)
+
+	if perr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(perr))
+		return
+	}
+
+	switch pu.Role {
+	case models.Temporary:
+		if _, err = c.db.GetProductById(id); err == nil {
+			_, err = c.rctrl.AddToWishlist(uint64(pu.ID), id)
+		}
+	default:
+		err = c.db.AddToWishlist(pu.ID, id)
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"product_id": id})
+}
+
+// Remove the product from the wishlist
+// @Summary      Remove the product from the wishlist
+// @Tags         wishlist
+// @Produce      json
+// @Param 		 Authorization	header	string	true	"access token for authorization"
+// @Param		 id				path	int		true	"Product ID"
+// @Success      200  {object}  uint64
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /wishlist/{id} [delete]
+func (c *WishlistController) removeFromWishlist(ctx *gin.Context) {
+	pu, err := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	id, perr := strconv.ParseUint(ctx.Param("id"), 10, 64)
+
+	if perr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(perr))
+		return
+	}
+
+	switch pu.Role {
+	case models.Temporary:
+		err = c.rctrl.RemoveFromWishlist(uint64(pu.ID), id)
+	default:
+		err = c.db.RemoveFromWishlist(pu.ID, id)
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"product_id": id})
+}
+
+// Move the product from the wishlist to the cart. One item of the product is added to the cart
+// @Summary      Move the product from the wishlist to the cart
+// @Tags         wishlist
+// @Produce      json
+// @Param 		 Authorization	header	string	true	"access token for authorization"
+// @Param		 id				path	int		true	"Product ID"
+// @Success      200  {object}  uint64
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /wishlist/{id}/cart [post]
+func (c *WishlistController) moveToCart(ctx *gin.Context) {
+	pu, err := GetPubUser(ctx, c.pucaster)
+
+	if CheckErrorAndWriteUnauthorized(ctx, err) {
+		return
+	}
+
+	id, perr := strconv.ParseUint(ctx.Param("id"), 10, 64)
+
+	if perr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewAtoiError(perr))
+		return
+	}
+
+	switch pu.Role {
+	case models.Temporary:
+		err = c.rctrl.MoveWishlistItemToCart(uint64(pu.ID), id)
+	default:
+		_, err = c.db.MoveWishlistItemToCart(pu.ID, id)
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"product_id": id})
+}
+
+// Get the token of the public link to the wishlist
+// @Summary      Get the token of the public link to the wishlist
+// @Tags         wishlist
+// @Produce      json
+// @Param 		 Authorization	header	string	true	"access token for authorization"
+// @Success      200  {object}  outputs.WishlistShareResult
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /wishlist/share [get]
+func (c *WishlistController) getShare(ctx *gin.Context) {
+	pu, ok := GetRegisteredUser(ctx, c.pucaster)
+
+	if !ok {
+		return
+	}
+
+	token, err := c.db.GetWishlistShareToken(pu.ID)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	if token == nil {
+		ctx.JSON(http.StatusOK, nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, outputs.NewWishlistShareResult(*token))
+}
+
+// Create the public link to the wishlist. The previous link stops working
+// @Summary      Create the public link to the wishlist
+// @Tags         wishlist
+// @Produce      json
+// @Param 		 Authorization	header	string	true	"access token for authorization"
+// @Success      201  {object}  outputs.WishlistShareResult
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /wishlist/share [post]
+func (c *WishlistController) share(ctx *gin.Context) {
+	pu, ok := GetRegisteredUser(ctx, c.pucaster)
+
+	if !ok {
+		return
+	}
+
+	token, terr := helpers.RandomToken(WishlistShareTokenBytes)
+
+	if terr != nil {
+		CheckErrorAndWriteBadRequest(ctx, errors.NewInternalSecretError())
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.SetWishlistShareToken(pu.ID, token)) {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, outputs.NewWishlistShareResult(token))
+}
+
+// Revoke the public link to the wishlist
+// @Summary      Revoke the public link to the wishlist
+// @Tags         wishlist
+// @Produce      json
+// @Param 		 Authorization	header	string	true	"access token for authorization"
+// @Success      200  {string}	ok
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Router       /wishlist/share [delete]
+func (c *WishlistController) unshare(ctx *gin.Context) {
+	pu, ok := GetRegisteredUser(ctx, c.pucaster)
+
+	if !ok {
+		return
+	}
+
+	if CheckErrorAndWriteBadRequest(ctx, c.db.DeleteWishlistShare(pu.ID)) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "ok")
+}
+
+// Get the wishlist by the public link
+// @Summary      Get the wishlist by the public link
+// @Tags         wishlist
+// @Produce      json
+// @Param		 token	path	string	true	"Token of the public link"
+// @Success      200  {object}  models.Wishlist
+// @Failure      400  {object}  errors.PublicPCCError
+// @Router       /wishlist/shared/{token} [get]
+func (c *WishlistController) getSharedWishlist(ctx *gin.Context) {
+	items, err := c.db.GetSharedWishlist(ctx.Param("token"))
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewWishlist(items))
+}
+
+// Get the products ordered by the amount of the wishlists they are saved in
+// @Summary      Get the wishlist counts of the products
+// @Tags         admin
+// @Produce      json
+// @Param		 Authorization  header	string							true	"access token"
+// @Param		 page			query	inputs.GetWishlistCountsInput	true	"Page and count"
+// @Success      200  {object}  outputs.GetWishlistCountsResult
+// @Failure      400  {object}  errors.PublicPCCError
+// @Failure      401  {object}  errors.PublicPCCError
+// @Failure      403  {object}  errors.PublicPCCError
+// @Router       /admin/wishlist/counts [get]
+func (c *WishlistController) getWishlistCounts(ctx *gin.Context) {
+	var input inputs.GetWishlistCountsInput
+
+	if berr := ctx.ShouldBindQuery(&input); berr != nil {
+		CheckErrorAndWriteBadRequest(ctx, conerrors.BindErrorCast(berr))
+		return
+	}
+
+	start := (input.Page * input.Count) - input.Count
+
+	counts, amount, err := c.db.GetWishlistCounts(start, input.Count)
+
+	if CheckErrorAndWriteBadRequest(ctx, err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, outputs.NewGetWishlistCountsResult(counts, amount, input.Page))
+}
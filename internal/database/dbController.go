@@ -2,6 +2,7 @@ package database
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/PC-Core/pc-core-backend/internal/errors"
 	"github.com/PC-Core/pc-core-backend/pkg/models"
@@ -17,6 +18,15 @@ const (
 	MouseCharsTable    = "MouseChars"
 )
 
+// CategoryCharsTables maps the category slug to the chars table of its products
+var CategoryCharsTables = map[string]string{
+	"laptop":   LaptopCharsTable,
+	"cpu":      CpuCharsTable,
+	"gpu":      GpuCharsTable,
+	"keyboard": KeyboardCharsTable,
+	"mouse":    MouseCharsTable,
+}
+
 // An interface for Product Characterics
 type ProductChars any
 
@@ -26,6 +36,7 @@ type RowLike interface {
 
 type DbController interface {
 	GetCartByUserID(userID uint64) (*models.Cart, errors.PCCError)
+	MergeCart(userID uint64, items []models.TempCartItem) ([]models.CartMergeConflict, errors.PCCError)
 	AddToCart(product_id, user_id, quantity uint64) (uint64, errors.PCCError)
 	SetToCart(product_id, user_id, quantity uint64) (uint64, errors.PCCError)
 	RemoveFromCart(product_id, user_id uint64) (uint64, errors.PCCError)
@@ -33,24 +44,120 @@ type DbController interface {
 	GetCategories() ([]models.Category, errors.PCCError)
 	GetLaptopChars(charId uint64) (*models.LaptopChars, errors.PCCError)
 	AddLaptop(laptop *inputs.AddLaptopInput) (*models.Product, *models.LaptopChars, errors.PCCError)
-	GetProducts(start uint64, count uint64) ([]models.Product, uint64, errors.PCCError)
+	GetProducts(start uint64, input *inputs.GetProductsInput) ([]models.Product, uint64, errors.PCCError)
 	GetProductCharsByProductID(productId uint64) (ProductChars, errors.PCCError)
 	GetProductById(id uint64) (*models.Product, errors.PCCError)
 	LoadProductsRangeAsCartItem(tempCart []models.TempCartItem) ([]models.CartItem, errors.PCCError)
+	LoadProductsRangeAsWishlistItem(tempWishlist []models.TempWishlistItem) ([]models.WishlistItem, errors.PCCError)
 	RegisterUser(register *inputs.RegisterUserInput) (*models.User, errors.PCCError)
 	LoginUser(login *inputs.LoginUserInput) (*models.User, errors.PCCError)
 	GetUserByID(id int) (*models.User, errors.PCCError)
+	SetUserTotpSecret(userID int, secret string) errors.PCCError
+	EnableUserMfa(userID int, recoveryHashes []string) errors.PCCError
+	DisableUserMfa(userID int) errors.PCCError
+	ReplaceRecoveryCodes(userID int, recoveryHashes []string) errors.PCCError
+	UseRecoveryCode(userID int, codeHash string) (bool, errors.PCCError)
+	GetUsers(role models.UserRole, email string, start uint64, count uint64) ([]models.User, uint64, errors.PCCError)
+	GetRoles() ([]models.Role, errors.PCCError)
+	SetUserRole(actorID int, userID int, role models.UserRole) errors.PCCError
+	SetUserDisabled(actorID int, userID int, disabled bool) errors.PCCError
+	AddAuditRecord(actorID int, action models.AuditAction, targetUserID *int, details map[string]any) errors.PCCError
+	SetUserName(userID int, name string) errors.PCCError
+	SetUserPassword(userID int, passwordHash string) errors.PCCError
+	SetUserAvatar(userID int, avatarURL string) errors.PCCError
+	CreateEmailVerification(userID int, email string, tokenHash string, expiresAt time.Time) errors.PCCError
+	GetPendingEmail(userID int) (*string, errors.PCCError)
+	ConfirmEmailVerification(tokenHash string) (bool, errors.PCCError)
+	GetUserAddresses(userID int) ([]models.Address, errors.PCCError)
+	AddUserAddress(userID int, input *inputs.AddressInput) (*models.Address, errors.PCCError)
+	UpdateUserAddress(userID int, addressID int64, input *inputs.AddressInput) (*models.Address, errors.PCCError)
+	DeleteUserAddress(userID int, addressID int64) errors.PCCError
+	SetDefaultUserAddress(userID int, addressID int64) errors.PCCError
 	GetCpuChars(charId uint64) (*models.CpuChars, errors.PCCError)
 	AddCpu(cpu *inputs.AddCpuInput) (*models.Product, *models.CpuChars, errors.PCCError)
-	GetRootCommentsForProduct(product_id int64, userID *int64, limit int, offset int) (*outputs.CommentsOutput, errors.PCCError)
-	GetAnswersOnComment(product_id int64, userID *int64, comment_id int64, limit int, offset int) (*outputs.CommentsOutput, errors.PCCError)
-	AddComment(input *inputs.AddCommentInput, userID int64, product_id int64) (int64, errors.PCCError)
-	EditComment(newText string, commentID int64, userID int64) (int64, errors.PCCError)
+	GetRootCommentsForProduct(product_id int64, userID *int64, input *inputs.GetRootCommentsInput) (*outputs.CommentsOutput, errors.PCCError)
+	GetAnswersOnComment(product_id int64, userID *int64, moderator bool, comment_id int64, limit int, offset int) (*outputs.CommentsOutput, errors.PCCError)
+	AddComment(input *inputs.AddCommentInput, userID int64, product_id int64, premoderation time.Duration) (int64, errors.PCCError)
+	EditComment(input *inputs.EditCommentInput, commentID int64, userID int64, reviewEditWindow time.Duration) (int64, errors.PCCError)
 	DeleteComment(commentID int64, userID int64) (int64, errors.PCCError)
 	AddGpu(gpu *inputs.AddGpuInput) (*models.GpuChars, *models.Product, errors.PCCError)
-	SetReaction(commentID int64, userID int64, ty models.ReactionType) (int64, errors.PCCError)
+	SetReaction(commentID int64, userID int64, ty models.ReactionType) (*models.CommentReactions, errors.PCCError)
 	AddKeyBoard(keyboard *inputs.AddKeyBoardInput) (*models.KeyboardChars, *models.Product, errors.PCCError)
 	AddMouse(mouse *inputs.AddMouseInput) (*models.MouseChars, *models.Product, errors.PCCError)
+	GetPromotions(start uint64, count uint64) ([]models.Promotion, uint64, errors.PCCError)
+	GetPromotionByID(id int64) (*models.Promotion, errors.PCCError)
+	GetPromotionByCode(code string, userID int) (*models.Promotion, errors.PCCError)
+	AddPromotion(input *inputs.PromotionInput) (*models.Promotion, errors.PCCError)
+	UpdatePromotion(id int64, input *inputs.PromotionInput) (*models.Promotion, errors.PCCError)
+	DeletePromotion(id int64) errors.PCCError
+	GetCartPromotions(userID int) ([]models.Promotion, errors.PCCError)
+	AddCartPromotion(userID int, promotionID int64) errors.PCCError
+	RemoveCartPromotion(userID int, code string) errors.PCCError
+	GetWishlist(userID int) ([]models.WishlistItem, errors.PCCError)
+	AddToWishlist(userID int, productID uint64) errors.PCCError
+	RemoveFromWishlist(userID int, productID uint64) errors.PCCError
+	MoveWishlistItemToCart(userID int, productID uint64) (uint64, errors.PCCError)
+	MergeWishlist(userID int, items []models.TempWishlistItem) (int, errors.PCCError)
+	SetWishlistShareToken(userID int, token string) errors.PCCError
+	GetWishlistShareToken(userID int) (*string, errors.PCCError)
+	DeleteWishlistShare(userID int) errors.PCCError
+	GetSharedWishlist(token string) ([]models.WishlistItem, errors.PCCError)
+	GetWishlistCounts(start uint64, count uint64) ([]models.WishlistProductCount, uint64, errors.PCCError)
+	UpdateProduct(actorID int, id uint64, input *inputs.UpdateProductInput) (*models.Product, errors.PCCError)
+	AddProductSubscription(userID int, productID uint64, input *inputs.SubscribeInput, token string) (*models.ProductSubscription, errors.PCCError)
+	GetUserSubscriptions(userID int) ([]models.ProductSubscription, errors.PCCError)
+	DeleteUserSubscription(userID int, subscriptionID int64) errors.PCCError
+	DeleteSubscriptionByToken(token string) errors.PCCError
+	ClaimNotifications(limit int, staleAfter time.Duration) ([]models.Notification, errors.PCCError)
+	MarkNotificationSent(notificationID int64) errors.PCCError
+	MarkNotificationFailed(notificationID int64, reason string, maxAttempts int) errors.PCCError
+	GetUserNotifications(userID int, start uint64, count uint64) ([]models.Notification, uint64, errors.PCCError)
+	MarkNotificationRead(userID int, notificationID int64) errors.PCCError
+	StartCheckout(userID int, cart *models.Cart, ttl time.Duration) (*models.Order, errors.PCCError)
+	CompleteCheckout(userID int, orderID int64) (*models.Order, errors.PCCError)
+	CancelCheckout(userID int, orderID int64) errors.PCCError
+	ReleaseExpiredReservations() (int64, errors.PCCError)
+	GetUserOrders(userID int, start uint64, count uint64) ([]models.Order, uint64, errors.PCCError)
+	GetUserOrder(userID int, orderID int64) (*models.Order, errors.PCCError)
+	GetWarehouses() ([]models.Warehouse, errors.PCCError)
+	AddWarehouse(input *inputs.WarehouseInput) (*models.Warehouse, errors.PCCError)
+	UpdateWarehouse(id int, input *inputs.WarehouseInput) (*models.Warehouse, errors.PCCError)
+	RecordGoodsReceipt(actorID int, input *inputs.GoodsReceiptInput) ([]models.StockMovement, errors.PCCError)
+	TransferStock(actorID int, input *inputs.StockTransferInput) (*models.StockMovement, errors.PCCError)
+	WriteOffStock(actorID int, input *inputs.WriteOffInput) (*models.StockMovement, errors.PCCError)
+	GetStockMovements(productID *uint64, warehouseID *int, start uint64, count uint64) ([]models.StockMovement, uint64, errors.PCCError)
+	GetProductAvailability(productID uint64) ([]models.LocationStock, errors.PCCError)
+	GetPriceHistory(productID uint64, from time.Time, to time.Time) ([]models.PriceHistoryEntry, errors.PCCError)
+	GetLowestPrice(productID uint64, period time.Duration) (*models.Money, errors.PCCError)
+	GetScheduledPriceChanges(productID *uint64, status *models.ScheduledPriceChangeStatus, start uint64, count uint64) ([]models.ScheduledPriceChange, uint64, errors.PCCError)
+	AddScheduledPriceChange(actorID int, input *inputs.ScheduledPriceChangeInput) (*models.ScheduledPriceChange, errors.PCCError)
+	CancelScheduledPriceChange(id int64) errors.PCCError
+	ApplyScheduledPriceChanges() (int64, errors.PCCError)
+	ReportComment(userID int, commentID int64, input *inputs.ReportCommentInput) errors.PCCError
+	GetModerationQueue(start uint64, count uint64) ([]models.ModerationQueueItem, uint64, errors.PCCError)
+	HideComment(actorID int, commentID int64, reason *string) errors.PCCError
+	RestoreComment(actorID int, commentID int64, reason *string) errors.PCCError
+	GetCommentAuthorID(commentID int64) (int, errors.PCCError)
+	BanCommentAuthor(actorID int, commentID int64, reason *string) errors.PCCError
+	UnbanCommentAuthor(actorID int, userID int, reason *string) errors.PCCError
+	AddCommentMedia(userID int, url string, thumbnailUrl string) (*models.Media, errors.PCCError)
+	GetCommentHistory(commentID int64, full bool) (*models.CommentHistory, errors.PCCError)
+	GetReactionTypes(all bool) ([]models.ReactionTypeInfo, errors.PCCError)
+	SetReactionType(name models.ReactionType, input *inputs.SetReactionTypeInput) (*models.ReactionTypeInfo, errors.PCCError)
+	GetReactions(commentID int64, ty *models.ReactionType, start uint64, count uint64) ([]models.Reaction, uint64, errors.PCCError)
+	GetProductQuestions(productID int64, unanswered bool, start uint64, count uint64) ([]models.Question, uint64, errors.PCCError)
+	GetQuestionAnswers(questionID int64, userID *int64, start uint64, count uint64) (*models.Question, []models.QuestionAnswer, uint64, errors.PCCError)
+	AddQuestion(input *inputs.AddQuestionInput, userID int64, productID int64) (int64, errors.PCCError)
+	AddQuestionAnswer(input *inputs.AddQuestionAnswerInput, questionID int64, userID int64) (int64, errors.PCCError)
+	SetAcceptedAnswer(questionID int64, answerID *int64, userID int64, moderator bool) errors.PCCError
+	UpvoteAnswer(answerID int64, userID int64) (*models.QuestionAnswer, errors.PCCError)
+	DeleteQuestion(questionID int64, userID int64, moderator bool) errors.PCCError
+	DeleteQuestionAnswer(answerID int64, userID int64, moderator bool) errors.PCCError
+	UseTotpStep(userID int, step int64) (bool, errors.PCCError)
+	UpdateUserProfile(userID int, name *string, passwordHash *string, email *string, tokenHash string, expiresAt time.Time) errors.PCCError
+	GetOrders(input *inputs.GetAdminOrdersInput) ([]models.Order, uint64, errors.PCCError)
+	GetOrder(orderID int64) (*models.Order, errors.PCCError)
+	CancelOrder(actorID int, orderID int64) errors.PCCError
 }
 
 // Database controller
@@ -0,0 +1,178 @@
+package gormpostgres
+
+import (
+	gormerrors "github.com/PC-Core/pc-core-backend/internal/database/gormPostgres/gormErrors"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"gorm.io/gorm"
+)
+
+func (c *GormPostgresController) GetUserAddresses(userID int) ([]models.Address, errors.PCCError) {
+	var dbaddresses []DbAddress
+
+	err := c.db.
+		Where("user_id = ?", userID).
+		Order("is_default DESC, id").
+		Find(&dbaddresses).Error
+
+	if err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	addresses := make([]models.Address, 0, len(dbaddresses))
+
+	for _, address := range dbaddresses {
+		addresses = append(addresses, *address.IntoAddress())
+	}
+
+	return addresses, nil
+}
+
+// AddUserAddress saves the address. The first address of the user becomes the default one
+func (c *GormPostgresController) AddUserAddress(userID int, input *inputs.AddressInput) (*models.Address, errors.PCCError) {
+	address := DbAddress{
+		UserID:     userID,
+		Label:      input.Label,
+		Recipient:  input.Recipient,
+		Phone:      input.Phone,
+		Country:    input.Country,
+		City:       input.City,
+		Street:     input.Street,
+		PostalCode: input.PostalCode,
+	}
+
+	err := c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		var count int64
+
+		if err := tx.Model(&DbAddress{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		if err := tx.Create(&address).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		if count == 0 || input.IsDefault {
+			if err := setDefaultAddress(tx, userID, address.ID); err != nil {
+				return err
+			}
+
+			address.IsDefault = true
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return address.IntoAddress(), nil
+}
+
+func (c *GormPostgresController) UpdateUserAddress(userID int, addressID int64, input *inputs.AddressInput) (*models.Address, errors.PCCError) {
+	var address DbAddress
+
+	err := c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		res := tx.Model(&DbAddress{}).
+			Where("id = ? AND user_id = ?", addressID, userID).
+			Updates(map[string]interface{}{
+				"label":       input.Label,
+				"recipient":   input.Recipient,
+				"phone":       input.Phone,
+				"country":     input.Country,
+				"city":        input.City,
+				"street":      input.Street,
+				"postal_code": input.PostalCode,
+			})
+
+		if res.Error != nil {
+			return gormerrors.GormErrorCast(res.Error)
+		}
+
+		if res.RowsAffected == 0 {
+			return gormerrors.GormErrorCast(gorm.ErrRecordNotFound)
+		}
+
+		if input.IsDefault {
+			if err := setDefaultAddress(tx, userID, addressID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("id = ?", addressID).First(&address).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return address.IntoAddress(), nil
+}
+
+// DeleteUserAddress removes the address. If it was the default one,
+// the oldest of the remaining addresses becomes the default
+func (c *GormPostgresController) DeleteUserAddress(userID int, addressID int64) errors.PCCError {
+	return c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		var address DbAddress
+
+		if err := tx.Where("id = ? AND user_id = ?", addressID, userID).First(&address).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		if err := tx.Delete(&address).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		if !address.IsDefault {
+			return nil
+		}
+
+		var next []DbAddress
+
+		if err := tx.Where("user_id = ?", userID).Order("id").Limit(1).Find(&next).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		if len(next) == 0 {
+			return nil
+		}
+
+		return setDefaultAddress(tx, userID, next[0].ID)
+	})
+}
+
+func (c *GormPostgresController) SetDefaultUserAddress(userID int, addressID int64) errors.PCCError {
+	return c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		return setDefaultAddress(tx, userID, addressID)
+	})
+}
+
+func setDefaultAddress(tx *gorm.DB, userID int, addressID int64) errors.PCCError {
+	err := tx.Model(&DbAddress{}).
+		Where("user_id = ? AND is_default", userID).
+		Update("is_default", false).Error
+
+	if err != nil {
+		return gormerrors.GormErrorCast(err)
+	}
+
+	res := tx.Model(&DbAddress{}).
+		Where("id = ? AND user_id = ?", addressID, userID).
+		Update("is_default", true)
+
+	if res.Error != nil {
+		return gormerrors.GormErrorCast(res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return gormerrors.GormErrorCast(gorm.ErrRecordNotFound)
+	}
+
+	return nil
+}
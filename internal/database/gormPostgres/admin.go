@@ -0,0 +1,139 @@
+package gormpostgres
+
+import (
+	gormerrors "github.com/PC-Core/pc-core-backend/internal/database/gormPostgres/gormErrors"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetUsers returns the users filtered by the exact role and the email substring.
+// Empty filters are ignored
+func (c *GormPostgresController) GetUsers(role models.UserRole, email string, start uint64, count uint64) ([]models.User, uint64, errors.PCCError) {
+	var (
+		dbusers    []DbUser
+		totalCount int64
+	)
+
+	query := c.db.Model(&DbUser{})
+
+	if role != "" {
+		query = query.Where("role = ?", role)
+	}
+
+	if email != "" {
+		query = query.Where("email ILIKE ?", "%"+email+"%")
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	err := query.
+		Preload("Permissions").
+		Order("id").
+		Limit(int(count)).
+		Offset(int(start)).
+		Find(&dbusers).Error
+
+	if err != nil {
+		return nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	users := make([]models.User, 0, len(dbusers))
+
+	for _, user := range dbusers {
+		users = append(users, *user.IntoUser())
+	}
+
+	return users, uint64(totalCount), nil
+}
+
+func (c *GormPostgresController) GetRoles() ([]models.Role, errors.PCCError) {
+	var dbroles []DbRole
+
+	if err := c.db.Preload("Permissions").Order("name").Find(&dbroles).Error; err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	roles := make([]models.Role, 0, len(dbroles))
+
+	for _, role := range dbroles {
+		roles = append(roles, *role.IntoRole())
+	}
+
+	return roles, nil
+}
+
+// SetUserRole changes the role of the user and records the change in the audit log
+func (c *GormPostgresController) SetUserRole(actorID int, userID int, role models.UserRole) errors.PCCError {
+	return c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		if err := tx.Where("name = ?", role).First(&DbRole{}).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		var user DbUser
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id, role").First(&user, userID).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		if err := updateUser(tx, userID, "role", role); err != nil {
+			return err
+		}
+
+		return addAuditRecord(tx, actorID, models.AuditUserRoleChanged, &userID, map[string]any{"from": user.Role, "to": role})
+	})
+}
+
+// SetUserDisabled disables or enables the user and records the change in the audit log
+func (c *GormPostgresController) SetUserDisabled(actorID int, userID int, disabled bool) errors.PCCError {
+	return c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		if err := updateUser(tx, userID, "disabled", disabled); err != nil {
+			return err
+		}
+
+		action := models.AuditUserEnabled
+
+		if disabled {
+			action = models.AuditUserDisabled
+		}
+
+		return addAuditRecord(tx, actorID, action, &userID, nil)
+	})
+}
+
+func (c *GormPostgresController) AddAuditRecord(actorID int, action models.AuditAction, targetUserID *int, details map[string]any) errors.PCCError {
+	return addAuditRecord(c.db, actorID, action, targetUserID, details)
+}
+
+// addAuditRecord records the action in the transaction of the action itself
+func addAuditRecord(tx *gorm.DB, actorID int, action models.AuditAction, targetUserID *int, details map[string]any) errors.PCCError {
+	record := DbAuditRecord{
+		ActorID:      actorID,
+		Action:       action,
+		TargetUserID: targetUserID,
+		Details:      details,
+	}
+
+	if err := tx.Create(&record).Error; err != nil {
+		return gormerrors.GormErrorCast(err)
+	}
+
+	return nil
+}
+
+func updateUser(tx *gorm.DB, userID int, column string, value any) errors.PCCError {
+	res := tx.Model(&DbUser{}).Where("id = ?", userID).Update(column, value)
+
+	if res.Error != nil {
+		return gormerrors.GormErrorCast(res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return gormerrors.GormErrorCast(gorm.ErrRecordNotFound)
+	}
+
+	return nil
+}
@@ -15,6 +15,7 @@ func (c *GormPostgresController) GetCartByUserID(userID uint64) (*models.Cart, e
 		Joins("JOIN products ON products.id = cart.product_id").
 		Where("cart.user_id = ?", userID).
 		Preload("Product.Medias").
+		Preload("Product.Rating").
 		Find(&cart).Error
 
 	if err != nil {
@@ -37,13 +38,17 @@ func (c *GormPostgresController) SetToCart(product_id, user_id, quantity uint64)
 }
 
 func (c *GormPostgresController) addOrSetToCart(product_id, user_id, quantity uint64, on_conflict clause.Set) (uint64, errors.PCCError) {
+	return product_id, upsertCartItem(c.db, product_id, user_id, quantity, on_conflict)
+}
+
+func upsertCartItem(tx *gorm.DB, product_id, user_id, quantity uint64, on_conflict clause.Set) errors.PCCError {
 	cartItem := DbCart{
 		UserID:    user_id,
 		ProductID: product_id,
 		Quantity:  uint(quantity),
 	}
 
-	err := c.db.
+	err := tx.
 		Clauses(clause.OnConflict{
 			Columns:   []clause.Column{{Name: "user_id"}, {Name: "product_id"}},
 			DoUpdates: on_conflict,
@@ -51,10 +56,10 @@ func (c *GormPostgresController) addOrSetToCart(product_id, user_id, quantity ui
 		Create(&cartItem).Error
 
 	if err != nil {
-		return product_id, gormerrors.GormErrorCast(err)
+		return gormerrors.GormErrorCast(err)
 	}
 
-	return product_id, nil
+	return nil
 }
 
 func (c *GormPostgresController) RemoveFromCart(productID, userID uint64) (uint64, errors.PCCError) {
@@ -79,3 +84,88 @@ func (c *GormPostgresController) ChangeQuantity(productID, userID uint64, val in
 
 	return productID, nil
 }
+
+// MergeCart adds the items of the temporary cart to the cart of the user. The resulting
+// quantity is clamped to the stock which is not reserved, so the trigger from the migration
+// 0005 never fires. The clamped and the missing products are reported as conflicts
+func (c *GormPostgresController) MergeCart(userID uint64, items []models.TempCartItem) ([]models.CartMergeConflict, errors.PCCError) {
+	conflicts := make([]models.CartMergeConflict, 0)
+
+	err := c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		for _, item := range items {
+			var products []DbProduct
+
+			// The product row is locked, so the stock and the reservations can't change until
+			// the merge is finished
+			err := tx.
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ?", item.ProductID).
+				Limit(1).
+				Find(&products).Error
+
+			if err != nil {
+				return gormerrors.GormErrorCast(err)
+			}
+
+			if len(products) == 0 {
+				conflicts = append(conflicts, *models.NewCartMergeConflict(item.ProductID, item.Quantity, 0, 0))
+				continue
+			}
+
+			available := products[0].Available()
+
+			var existing []DbCart
+
+			if err := tx.Where("user_id = ? AND product_id = ?", userID, item.ProductID).Limit(1).Find(&existing).Error; err != nil {
+				return gormerrors.GormErrorCast(err)
+			}
+
+			var current uint64
+
+			if len(existing) != 0 {
+				current = uint64(existing[0].Quantity)
+			}
+
+			target := current + uint64(item.Quantity)
+
+			if target > available {
+				target = max(available, current)
+			}
+
+			merged := uint(target - current)
+
+			if merged != item.Quantity {
+				conflicts = append(conflicts, *models.NewCartMergeConflict(item.ProductID, item.Quantity, merged, available))
+			}
+
+			if merged == 0 {
+				continue
+			}
+
+			cartItem := DbCart{
+				UserID:    userID,
+				ProductID: item.ProductID,
+				Quantity:  uint(target),
+			}
+
+			err = tx.
+				Clauses(clause.OnConflict{
+					Columns:   []clause.Column{{Name: "user_id"}, {Name: "product_id"}},
+					DoUpdates: clause.Assignments(map[string]interface{}{"quantity": target}),
+				}).
+				Create(&cartItem).Error
+
+			if err != nil {
+				return gormerrors.GormErrorCast(err)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return conflicts, nil
+}
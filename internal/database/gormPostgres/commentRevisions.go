@@ -0,0 +1,60 @@
+package gormpostgres
+
+import (
+	gormerrors "github.com/PC-Core/pc-core-backend/internal/database/gormPostgres/gormErrors"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/helpers"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+)
+
+// GetCommentHistory returns the edit history of the comment. The revisions are loaded only
+// if full is set, the others see only whether the visible comment was edited
+func (c *GormPostgresController) GetCommentHistory(commentID int64, full bool) (*models.CommentHistory, errors.PCCError) {
+	var comment DbComment
+
+	query := c.db.Where("id = ?", commentID)
+
+	if !full {
+		query = query.Where("moderation_status = ?", models.ModerationVisible)
+	}
+
+	if err := query.First(&comment).Error; err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	if !full {
+		var edits int64
+
+		if err := c.db.Model(&DbCommentRevision{}).Where("comment_id = ?", commentID).Count(&edits).Error; err != nil {
+			return nil, gormerrors.GormErrorCast(err)
+		}
+
+		return models.NewCommentHistory(commentID, uint64(edits), comment.UpdatedAt, nil), nil
+	}
+
+	var revisions []DbCommentRevision
+
+	if err := c.db.Where("comment_id = ?", commentID).Order("id").Find(&revisions).Error; err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	current := comment.CreatedAt
+
+	if comment.UpdatedAt != nil {
+		current = *comment.UpdatedAt
+	}
+
+	result := make([]models.CommentRevision, 0, len(revisions)+1)
+
+	for _, revision := range revisions {
+		result = append(result, *models.NewCommentRevision(revision.CommentText, revision.Rating, revision.WrittenAt, nil))
+	}
+
+	result = append(result, *models.NewCommentRevision(comment.CommentText, comment.Rating, current, nil))
+
+	for i := 1; i < len(result); i++ {
+		result[i].Diff = helpers.WordDiff(result[i-1].Text, result[i].Text)
+	}
+
+	return models.NewCommentHistory(commentID, uint64(len(revisions)), comment.UpdatedAt, result), nil
+}
@@ -3,6 +3,7 @@ package gormpostgres
 import (
 	"time"
 
+	"github.com/PC-Core/pc-core-backend/internal/contentfilter"
 	gormerrors "github.com/PC-Core/pc-core-backend/internal/database/gormPostgres/gormErrors"
 	"github.com/PC-Core/pc-core-backend/internal/errors"
 	"github.com/PC-Core/pc-core-backend/pkg/models"
@@ -23,6 +24,10 @@ type commentRow struct {
 	UpdatedAt   *time.Time    `gorm:"column:updated_at"`
 	MediaIDs    pq.Int64Array `gorm:"column:media_ids;type:bigint[]"`
 	Deleted     bool          `gorm:"column:is_deleted"`
+	// VerifiedPurchase is set by the trigger
+	VerifiedPurchase bool `gorm:"column:verified_purchase"`
+	// ReactionCounts are cached in the comment by the trigger
+	ReactionCounts map[models.ReactionType]uint64 `gorm:"column:reaction_counts;serializer:json"`
 
 	UserID_A  int64           `gorm:"column:user.id"`
 	UserName  string          `gorm:"column:user.name"`
@@ -48,151 +53,234 @@ func (*GormPostgresController) loadCommentIds(comments []DbComment) []int64 {
 	return result
 }
 
-func (c *GormPostgresController) getAnswersCount(parent_id int64) (int64, errors.PCCError) {
-	var count int64
+type answersCountRow struct {
+	AnswerOn int64  `gorm:"column:answer_on"`
+	Count    uint64 `gorm:"column:count"`
+}
+
+// loadAnswersCounts counts the visible direct answers of all the comments in a single query
+func (c *GormPostgresController) loadAnswersCounts(ids []int64) (map[int64]uint64, errors.PCCError) {
+	result := make(map[int64]uint64, len(ids))
+
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	var rows []answersCountRow
 
-	err := c.db.Model(&DbComment{}).Where("answer_on = ?", parent_id).Count(&count).Error
+	err := c.db.Model(&DbComment{}).
+		Select("answer_on, COUNT(*) AS count").
+		Where("answer_on IN ? AND moderation_status = ?", ids, models.ModerationVisible).
+		Group("answer_on").
+		Scan(&rows).Error
 
 	if err != nil {
-		return 0, gormerrors.GormErrorCast(err)
+		return nil, gormerrors.GormErrorCast(err)
 	}
 
-	return count, nil
+	for _, row := range rows {
+		result[row.AnswerOn] = row.Count
+	}
 
-	// 	var count int64
+	return result, nil
+}
 
-	// 	cte := `
-	// WITH RECURSIVE comment_tree AS (
-	//     SELECT id, answer_on
-	//     FROM comments
-	//     WHERE answer_on = ?
+// loadReactionsForComments takes the reaction counts cached in the comments and finds the reactions
+// of the user. The reactions themselves are not loaded, so the popular comments are as cheap as the others
+func (c *GormPostgresController) loadReactionsForComments(comments []DbComment, userID *int64) (map[int64]models.CommentReactions, errors.PCCError) {
+	result := make(map[int64]models.CommentReactions, len(comments))
 
-	//     UNION ALL
+	if len(comments) == 0 {
+		return result, nil
+	}
 
-	//     SELECT c.id, c.answer_on
-	//     FROM comments c
-	//     INNER JOIN comment_tree ct ON c.answer_on = ct.id
-	// )
-	// SELECT COUNT(*) FROM comment_tree;
-	// `
+	for _, comment := range comments {
+		amount := comment.ReactionCounts
 
-	// 	err := c.db.Raw(cte, parent_id).Scan(&count).Error
-	// 	if err != nil {
-	// 		return -1, gormerrors.GormErrorCast(err)
-	// 	}
+		if amount == nil {
+			amount = make(map[models.ReactionType]uint64)
+		}
 
-	// return count, nil
-}
+		result[comment.ID] = models.CommentReactions{ReactionsAmount: amount}
+	}
 
-func (c *GormPostgresController) loadReactionsForComments(ids []int64) (map[int64][]DbCommentReaction, errors.PCCError) {
-	var reactions []DbCommentReaction
-	err := c.db.Where("comment_id = ANY(?)", pq.Array(ids)).Find(&reactions).Error
+	if userID == nil {
+		return result, nil
+	}
 
-	result := make(map[int64][]DbCommentReaction)
+	var yours []DbCommentReaction
 
-	if err != nil {
+	if err := c.db.Where("comment_id IN ? AND user_id = ?", c.loadCommentIds(comments), *userID).Find(&yours).Error; err != nil {
 		return nil, gormerrors.GormErrorCast(err)
 	}
 
-	for _, reaction := range reactions {
-		result[reaction.CommentID] = append(result[reaction.CommentID], reaction)
+	for i := range yours {
+		reactions := result[yours[i].CommentID]
+		reactions.YourReaction = &yours[i].Type
+		result[yours[i].CommentID] = reactions
 	}
 
 	return result, nil
 }
 
-func (*GormPostgresController) getCommentReactions(dbreactions []DbCommentReaction, userID *int64) models.CommentReactions {
-	reactions := make(map[models.ReactionType]uint64)
-	var yourReaction *models.ReactionType = nil
+// LoadMediasForComments loads the medias of all the comments in a single query.
+// The medias of every comment keep the order of its MediaIDs
+func (c *GormPostgresController) LoadMediasForComments(comments []DbComment) (map[int64]models.Medias, errors.PCCError) {
+	result := make(map[int64]models.Medias, len(comments))
+	ids := make(map[int64]bool)
 
-	for _, reaction := range dbreactions {
-		if prev, ok := reactions[reaction.Type]; ok {
-			reactions[reaction.Type] = prev + 1
-		} else {
-			reactions[reaction.Type] = 1
+	for _, comment := range comments {
+		for _, id := range comment.MediaIDs {
+			ids[id] = true
 		}
+	}
+
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	var medias []DbCommentMedia
+
+	if err := c.db.Where("id IN ?", mapKeys(ids)).Find(&medias).Error; err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	byID := make(map[int64]*DbCommentMedia, len(medias))
 
-		if userID != nil && *userID == reaction.UserID {
-			yourReaction = &reaction.Type
+	for i := range medias {
+		byID[medias[i].ID] = &medias[i]
+	}
+
+	for _, comment := range comments {
+		for _, id := range comment.MediaIDs {
+			if media, ok := byID[id]; ok {
+				result[comment.ID] = append(result[comment.ID], *media.IntoMedia(comment.ProductID))
+			}
 		}
 	}
 
-	return models.CommentReactions{ReactionsAmount: reactions, YourReaction: yourReaction}
+	return result, nil
 }
 
-func (c *GormPostgresController) LoadMediasForComment(mediaIDs []int64) (DbMedias, errors.PCCError) {
-	var medias DbMedias
+// attachCommentMedias attaches the medias uploaded by the user to the comment. The medias
+// attached to the comment before and not listed are detached
+func attachCommentMedias(tx *gorm.DB, userID int64, commentID int64, mediaIDs []int64) errors.PCCError {
+	detach := tx.Model(&DbCommentMedia{}).Where("comment_id = ?", commentID)
 
-	err := c.db.Where("id = ANY(?)", pq.Array(mediaIDs)).Find(&medias).Error
+	if len(mediaIDs) > 0 {
+		detach = detach.Where("id NOT IN ?", mediaIDs)
+	}
 
-	if err != nil {
-		return nil, gormerrors.GormErrorCast(err)
+	if err := detach.Update("comment_id", nil).Error; err != nil {
+		return gormerrors.GormErrorCast(err)
 	}
 
-	return medias, nil
-}
+	if len(mediaIDs) == 0 {
+		return nil
+	}
 
-func (c *GormPostgresController) getRootCommentsCount(product_id int64) (int64, errors.PCCError) {
-	var count int64
+	res := tx.Model(&DbCommentMedia{}).
+		Where("id IN ? AND owner_id = ? AND (comment_id IS NULL OR comment_id = ?)", mediaIDs, userID, commentID).
+		Update("comment_id", commentID)
 
-	err := c.db.Model(&DbComment{}).Where("product_id = ? AND answer_on IS NULL", product_id).Count(&count).Error
+	if res.Error != nil {
+		return gormerrors.GormErrorCast(res.Error)
+	}
 
-	if err != nil {
-		return -1, gormerrors.GormErrorCast(err)
+	if res.RowsAffected != int64(len(mediaIDs)) {
+		return gormerrors.NewCommentMediaUnavailableError()
 	}
 
-	return count, nil
+	return nil
 }
 
-func (c *GormPostgresController) loadRootComments(product_id int64, userID *int64, limit int, offset int) (*LoadedComments, errors.PCCError) {
-	var comments []DbComment
-	commentReactions := make(map[int64]models.CommentReactions)
-
-	err := c.db.Preload("User").Preload("Product").Order("created_at DESC").Limit(limit).Offset(offset).Where("product_id = ? AND answer_on is NULL", product_id).Find(&comments).Error
+// AddCommentMedia saves the uploaded image. It is attached to the comment later
+func (c *GormPostgresController) AddCommentMedia(userID int, url string, thumbnailUrl string) (*models.Media, errors.PCCError) {
+	media := DbCommentMedia{
+		OwnerID:      userID,
+		Url:          url,
+		ThumbnailUrl: thumbnailUrl,
+		Type:         models.MediaImage,
+	}
 
-	if err != nil {
+	if err := c.db.Create(&media).Error; err != nil {
 		return nil, gormerrors.GormErrorCast(err)
 	}
 
-	commentIds := c.loadCommentIds(comments)
-	dbreactions, perr := c.loadReactionsForComments(commentIds)
+	return media.IntoMedia(0), nil
+}
 
-	if perr != nil {
-		return nil, perr
+// commentOrders are the orders of the sort modes. The ID keeps the order stable for the pagination
+var commentOrders = map[models.CommentSort]string{
+	models.CommentSortNewest:     "comments.created_at DESC, comments.id DESC",
+	models.CommentSortOldest:     "comments.created_at, comments.id",
+	models.CommentSortHelpful:    "wilson_lower_bound(COALESCE((comments.reaction_counts->>'like')::bigint, 0), COALESCE((comments.reaction_counts->>'dislike')::bigint, 0)) DESC, comments.created_at DESC, comments.id DESC",
+	models.CommentSortRatingHigh: "comments.rating DESC NULLS LAST, comments.created_at DESC, comments.id DESC",
+	models.CommentSortRatingLow:  "comments.rating ASC NULLS LAST, comments.created_at DESC, comments.id DESC",
+	models.CommentSortWithMedia:  "cardinality(comments.media_ids) > 0 DESC, comments.created_at DESC, comments.id DESC",
+}
+
+// rootCommentsQuery selects the visible root comments of the product rated in the range of the stars
+func (c *GormPostgresController) rootCommentsQuery(product_id int64, input *inputs.GetRootCommentsInput) *gorm.DB {
+	query := c.db.Model(&DbComment{}).
+		Where("comments.product_id = ? AND comments.answer_on IS NULL AND comments.moderation_status = ?", product_id, models.ModerationVisible)
+
+	if input.MinStars != nil {
+		query = query.Where("comments.rating IS NOT NULL AND rating_stars(comments.rating) >= ?", *input.MinStars)
 	}
 
-	for k, v := range dbreactions {
-		commentReactions[k] = c.getCommentReactions(v, userID)
+	if input.MaxStars != nil {
+		query = query.Where("comments.rating IS NOT NULL AND rating_stars(comments.rating) <= ?", *input.MaxStars)
 	}
 
-	rootCount, perr := c.getRootCommentsCount(product_id)
+	return query
+}
 
-	if perr != nil {
-		return nil, perr
+// loadRootComments loads the page of the root comments in a constant number of queries
+// regardless of the page size. The comments are ordered in the database, so the pages don't overlap
+func (c *GormPostgresController) loadRootComments(product_id int64, userID *int64, input *inputs.GetRootCommentsInput) (*LoadedComments, errors.PCCError) {
+	var (
+		comments  []DbComment
+		rootCount int64
+	)
+
+	sort := input.Sort
+
+	if _, ok := commentOrders[sort]; !ok {
+		sort = models.CommentSortNewest
 	}
 
-	result, perr := c.dbCommentsIntoComments(comments, rootCount, userID)
+	if err := c.rootCommentsQuery(product_id, input).Count(&rootCount).Error; err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
 
-	for i := range result.Comments {
-		cmt := &result.Comments[i]
+	err := c.rootCommentsQuery(product_id, input).Preload("User").Order(commentOrders[sort]).Limit(input.Limit).Offset(input.Offset).Find(&comments).Error
 
-		cnt, perr := c.getAnswersCount(cmt.ID)
-		if perr != nil {
-			continue
-		}
+	if err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
 
-		cmt.ChildrenCount = uint64(cnt)
+	result, perr := c.dbCommentsIntoComments(comments, rootCount, userID)
+
+	if perr != nil {
+		return nil, perr
 	}
 
+	answers, perr := c.loadAnswersCounts(c.loadCommentIds(comments))
+
 	if perr != nil {
 		return nil, perr
 	}
 
+	for i := range result.Comments {
+		result.Comments[i].ChildrenCount = answers[result.Comments[i].ID]
+	}
+
 	return result, nil
 }
 
-func (c *GormPostgresController) GetRootCommentsForProduct(product_id int64, userID *int64, limit int, offset int) (*outputs.CommentsOutput, errors.PCCError) {
-	result, err := c.loadRootComments(product_id, userID, limit, offset)
+func (c *GormPostgresController) GetRootCommentsForProduct(product_id int64, userID *int64, input *inputs.GetRootCommentsInput) (*outputs.CommentsOutput, errors.PCCError) {
+	result, err := c.loadRootComments(product_id, userID, input)
 
 	if err != nil {
 		return nil, err
@@ -221,7 +309,6 @@ func buildTree(comment *models.Comment, idToChildrenMap map[int64][]int64, idToC
 
 func (c *GormPostgresController) dbCommentsIntoComments(comments []DbComment, all_count int64, userID *int64) (*LoadedComments, errors.PCCError) {
 	result := make([]models.Comment, 0)
-	commentReactions := make(map[int64]models.CommentReactions)
 
 	counts := make(map[int64]uint64)
 	for _, c := range comments {
@@ -230,31 +317,34 @@ func (c *GormPostgresController) dbCommentsIntoComments(comments []DbComment, al
 		}
 	}
 
-	commentIds := c.loadCommentIds(comments)
-	dbreactions, perr := c.loadReactionsForComments(commentIds)
+	commentReactions, perr := c.loadReactionsForComments(comments, userID)
 
 	if perr != nil {
 		return nil, perr
 	}
 
-	for k, v := range dbreactions {
-		commentReactions[k] = c.getCommentReactions(v, userID)
+	medias, perr := c.LoadMediasForComments(comments)
+
+	if perr != nil {
+		return nil, perr
 	}
 
 	for _, comment := range comments {
-		medias, perr := c.LoadMediasForComment(comment.MediaIDs)
+		commentMedias := medias[comment.ID]
 
-		if perr != nil {
-			return nil, perr
+		if commentMedias == nil {
+			commentMedias = models.Medias{}
 		}
 
-		result = append(result, *models.NewComment(comment.ID, comment.User.IntoUser(), comment.CommentText, []models.Comment{}, counts[comment.ID], comment.Rating, &comment.CreatedAt, comment.UpdatedAt, medias.IntoMedias(), commentReactions[comment.ID], comment.Deleted))
+		result = append(result, *models.NewComment(comment.ID, comment.User.IntoUser(), comment.CommentText, []models.Comment{}, counts[comment.ID], comment.Rating, &comment.CreatedAt, comment.UpdatedAt, commentMedias, commentReactions[comment.ID], comment.Deleted, comment.VerifiedPurchase))
 	}
 
 	return &LoadedComments{comments, result, all_count}, nil
 }
 
-func (c *GormPostgresController) loadAnswersV2(parent_id int64, limit int, offset int) ([]DbComment, errors.PCCError) {
+// loadAnswersV2 loads the visible answers in the tree of the comment. The hidden comment itself
+// is loaded only for its author and the moderators, so its answers are not shown to the others
+func (c *GormPostgresController) loadAnswersV2(parent_id int64, userID *int64, moderator bool, limit int, offset int) ([]DbComment, errors.PCCError) {
 	var raw []commentRow
 
 	query := `
@@ -265,10 +355,16 @@ WITH RECURSIVE comment_tree AS (
         c.product_id,
         c.answer_on,
         c.comment_text,
+        c.media_ids,
+        c.reaction_counts,
+        c.rating,
+        c.verified_purchase,
+        c.is_deleted,
         c.created_at,
+        c.updated_at,
         0 AS depth
     FROM comments c
-    WHERE c.id = ?
+    WHERE c.id = ? AND (c.moderation_status = 'visible' OR c.user_id = ? OR ?)
 
     UNION ALL
 
@@ -278,17 +374,31 @@ WITH RECURSIVE comment_tree AS (
         c.product_id,
         c.answer_on,
         c.comment_text,
+        c.media_ids,
+        c.reaction_counts,
+        c.rating,
+        c.verified_purchase,
+        c.is_deleted,
         c.created_at,
+        c.updated_at,
         ct.depth + 1
     FROM comments c
     INNER JOIN comment_tree ct ON c.answer_on = ct.id
+    WHERE c.moderation_status = 'visible'
 )
 SELECT 
     ct.id,
+    ct.user_id,
     ct.product_id,
     ct.answer_on,
     ct.comment_text,
+    ct.media_ids,
+    ct.reaction_counts,
+    ct.rating,
+    ct.verified_purchase,
+    ct.is_deleted,
     ct.created_at,
+    ct.updated_at,
     ct.depth,
     u.id AS "user.id",
     u.name AS "user.name",
@@ -304,7 +414,7 @@ WHERE ct.depth = 0 OR ct.id IN (
 ORDER BY depth, ct.created_at;
 `
 
-	if err := c.db.Raw(query, parent_id, limit, offset).Scan(&raw).Error; err != nil {
+	if err := c.db.Raw(query, parent_id, userID, moderator, limit, offset).Scan(&raw).Error; err != nil {
 		return nil, gormerrors.GormErrorCast(err)
 	}
 
@@ -322,6 +432,9 @@ ORDER BY depth, ct.created_at;
 			MediaIDs:    r.MediaIDs,
 			Deleted:     r.Deleted,
 
+			VerifiedPurchase: r.VerifiedPurchase,
+			ReactionCounts:   r.ReactionCounts,
+
 			User: DbUser{
 				ID: int(r.UserID), Name: r.UserName, Email: r.UserEmail, Role: r.UserRole,
 			},
@@ -332,8 +445,8 @@ ORDER BY depth, ct.created_at;
 	return comments, nil
 }
 
-func (c *GormPostgresController) GetAnswersOnComment(product_id int64, userID *int64, comment_id int64, limit int, offset int) (*outputs.CommentsOutput, errors.PCCError) {
-	ans, err := c.loadAnswersV2(comment_id, limit, offset)
+func (c *GormPostgresController) GetAnswersOnComment(product_id int64, userID *int64, moderator bool, comment_id int64, limit int, offset int) (*outputs.CommentsOutput, errors.PCCError) {
+	ans, err := c.loadAnswersV2(comment_id, userID, moderator, limit, offset)
 
 	if err != nil {
 		return nil, err
@@ -378,7 +491,7 @@ func (c *GormPostgresController) GetAnswersOnComment(product_id int64, userID *i
 func (c *GormPostgresController) CheckUserOwnCommentByID(commentID int64, userID int64) errors.PCCError {
 	var comment DbComment
 
-	err := c.db.Where("id = ? AND user_id = ?", commentID, userID).Find(&comment).Error
+	err := c.db.Where("id = ? AND user_id = ?", commentID, userID).First(&comment).Error
 
 	if err != nil {
 		return gormerrors.GormErrorCastUserOwn(err)
@@ -387,33 +500,134 @@ func (c *GormPostgresController) CheckUserOwnCommentByID(commentID int64, userID
 	return nil
 }
 
-func (c *GormPostgresController) AddComment(input *inputs.AddCommentInput, userID int64, product_id int64) (int64, errors.PCCError) {
+// checkCommentAuthor returns the author of the comment if the author is not banned from commenting
+func checkCommentAuthor(tx *gorm.DB, userID int64) (*DbUser, errors.PCCError) {
+	var user DbUser
+
+	if err := tx.Where("id = ?", userID).First(&user).Error; err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	if user.CommentsBanned {
+		return nil, gormerrors.NewCommentsBannedError()
+	}
+
+	return &user, nil
+}
+
+// filterComment runs the content filter on the text of the new or the edited comment.
+// The rejected text is returned as the error
+func (c *GormPostgresController) filterComment(userID int64, productID int64, text string, edited *int64) (*contentfilter.Result, errors.PCCError) {
+	if c.filter == nil {
+		return &contentfilter.Result{Verdict: contentfilter.Allow, Text: text}, nil
+	}
+
+	var recent []DbComment
+
+	if window := c.filter.HistoryWindow(); window > 0 {
+		query := c.db.Select("product_id", "comment_text", "created_at").
+			Where("user_id = ? AND created_at >= ?", userID, time.Now().Add(-window))
+
+		if edited != nil {
+			query = query.Where("id <> ?", *edited)
+		}
+
+		if err := query.Find(&recent).Error; err != nil {
+			return nil, gormerrors.GormErrorCast(err)
+		}
+	}
+
+	past := make([]contentfilter.PastComment, 0, len(recent))
+
+	for _, comment := range recent {
+		past = append(past, contentfilter.PastComment{
+			ProductID: comment.ProductID,
+			Text:      comment.CommentText,
+			CreatedAt: comment.CreatedAt,
+		})
+	}
+
+	return c.filter.Run(&contentfilter.Comment{
+		UserID:    userID,
+		ProductID: productID,
+		Text:      text,
+		Edited:    edited != nil,
+		Recent:    past,
+	})
+}
+
+// AddComment saves the comment. The comments of the accounts created less than premoderation ago
+// wait for the approval of the moderators. The zero premoderation disables it
+func (c *GormPostgresController) AddComment(input *inputs.AddCommentInput, userID int64, product_id int64, premoderation time.Duration) (int64, errors.PCCError) {
+	user, perr := checkCommentAuthor(c.db, userID)
+
+	if perr != nil {
+		return -1, perr
+	}
+
+	filtered, perr := c.filterComment(userID, product_id, input.Text, nil)
+
+	if perr != nil {
+		return -1, perr
+	}
+
+	status := models.ModerationVisible
+
+	if premoderation > 0 && user.CreatedAt != nil && user.CreatedAt.After(time.Now().Add(-premoderation)) {
+		status = models.ModerationPending
+	}
+
+	if filtered.Verdict == contentfilter.Hold {
+		status = models.ModerationPending
+	}
+
 	comment := DbComment{
 		ID:          0,
 		UserID:      userID,
 		ProductID:   product_id,
-		CommentText: input.Text,
+		CommentText: filtered.Text,
 		AnswerOn:    input.Answer,
 		Rating:      input.Rating,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   nil,
-		MediaIDs:    []int64{},
+		MediaIDs:    pq.Int64Array(input.Medias),
+
+		ModerationStatus: status,
+		FilterFlags:      filtered.Flags(),
 	}
 
-	err := c.db.Create(&comment).Error
+	if comment.MediaIDs == nil {
+		comment.MediaIDs = []int64{}
+	}
 
-	if err != nil {
-		return -1, gormerrors.GormErrorCast(err)
+	perr = c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		if err := tx.Create(&comment).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		return attachCommentMedias(tx, userID, comment.ID, comment.MediaIDs)
+	})
+
+	if perr != nil {
+		return -1, perr
 	}
 
 	return comment.ID, nil
 }
 
-func (c *GormPostgresController) EditComment(newText string, commentID int64, userID int64) (int64, errors.PCCError) {
+// EditComment changes the text of the comment and the rating of the review. The rating
+// can't be set on the answers. The reviews can be edited during the reviewEditWindow after
+// they are written, the zero window doesn't limit the edits. The previous version is kept
+// in the revisions by the trigger
+func (c *GormPostgresController) EditComment(input *inputs.EditCommentInput, commentID int64, userID int64, reviewEditWindow time.Duration) (int64, errors.PCCError) {
 	if err := c.CheckUserOwnCommentByID(commentID, userID); err != nil {
 		return -1, err
 	}
 
+	if _, err := checkCommentAuthor(c.db, userID); err != nil {
+		return -1, err
+	}
+
 	var comment DbComment
 
 	err := c.db.First(&comment, commentID).Error
@@ -422,15 +636,49 @@ func (c *GormPostgresController) EditComment(newText string, commentID int64, us
 		return -1, gormerrors.GormErrorCast(err)
 	}
 
+	if reviewEditWindow > 0 && comment.Rating != nil && comment.CreatedAt.Before(time.Now().Add(-reviewEditWindow)) {
+		return -1, gormerrors.NewReviewEditExpiredError()
+	}
+
+	filtered, perr := c.filterComment(userID, comment.ProductID, input.Text, &commentID)
+
+	if perr != nil {
+		return -1, perr
+	}
+
 	now := time.Now()
 
-	comment.CommentText = newText
+	comment.CommentText = filtered.Text
 	comment.UpdatedAt = &now
+	comment.FilterFlags = filtered.Flags()
 
-	err = c.db.Save(&comment).Error
+	// The held edit is hidden until the approval, the hidden comment stays hidden
+	if filtered.Verdict == contentfilter.Hold && comment.ModerationStatus == models.ModerationVisible {
+		comment.ModerationStatus = models.ModerationPending
+	}
 
-	if err != nil {
-		return -1, gormerrors.GormErrorCast(err)
+	if input.Rating != nil {
+		comment.Rating = input.Rating
+	}
+
+	if input.Medias != nil {
+		comment.MediaIDs = input.Medias
+	}
+
+	perr = c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		if err := tx.Save(&comment).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		if input.Medias == nil {
+			return nil
+		}
+
+		return attachCommentMedias(tx, userID, commentID, comment.MediaIDs)
+	})
+
+	if perr != nil {
+		return -1, perr
 	}
 
 	return commentID, nil
@@ -451,6 +699,7 @@ func (c *GormPostgresController) DeleteComment(commentID int64, userID int64) (i
 
 	comment.CommentText = ""
 	comment.Deleted = true
+	comment.MediaIDs = []int64{}
 
 	err = c.db.Save(&comment).Error
 
@@ -461,14 +710,15 @@ func (c *GormPostgresController) DeleteComment(commentID int64, userID int64) (i
 	return commentID, nil
 }
 
-func (c *GormPostgresController) CreateReaction(commentID int64, userID int64, ty models.ReactionType) errors.PCCError {
-	reaction := DbCommentReaction{
-		UserID:    userID,
-		CommentID: commentID,
-		Type:      ty,
-	}
+// checkReactionType checks that the reaction can be added
+func (c *GormPostgresController) checkReactionType(tx *gorm.DB, ty models.ReactionType) errors.PCCError {
+	var reactionType DbReactionType
 
-	err := c.db.Create(&reaction).Error
+	err := tx.Where("name = ? AND active", ty).First(&reactionType).Error
+
+	if err == gorm.ErrRecordNotFound {
+		return gormerrors.NewReactionUnavailableError(string(ty))
+	}
 
 	if err != nil {
 		return gormerrors.GormErrorCast(err)
@@ -477,37 +727,69 @@ func (c *GormPostgresController) CreateReaction(commentID int64, userID int64, t
 	return nil
 }
 
-func (c *GormPostgresController) DeleteReaction(existing *DbCommentReaction) errors.PCCError {
-	err := c.db.Delete(existing).Error
+// SetReaction adds the reaction, changes its type or removes it if the type is the same.
+// The inactive reactions can still be removed. It returns the reactions of the comment after the change
+func (c *GormPostgresController) SetReaction(commentID int64, userID int64, ty models.ReactionType) (*models.CommentReactions, errors.PCCError) {
+	var comment DbComment
 
-	if err != nil {
-		return gormerrors.GormErrorCast(err)
-	}
+	perr := c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		err := tx.Select("id").Where("moderation_status = ? AND NOT is_deleted", models.ModerationVisible).First(&comment, commentID).Error
 
-	return nil
-}
+		if err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
 
-func (c *GormPostgresController) SetReaction(commentID int64, userID int64, ty models.ReactionType) (int64, errors.PCCError) {
+		var existing DbCommentReaction
 
-	var existing DbCommentReaction
+		err = tx.First(&existing, "comment_id = ? AND user_id = ?", commentID, userID).Error
 
-	err := c.db.First(&existing, "comment_id = ? AND user_id = ?", &commentID, &userID).Error
+		found := err == nil
 
-	if err == gorm.ErrRecordNotFound {
-		return commentID, c.CreateReaction(commentID, userID, ty)
-	}
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return gormerrors.GormErrorCast(err)
+		}
 
-	if existing.Type == ty {
-		return commentID, c.DeleteReaction(&existing)
-	}
+		switch {
+		case found && existing.Type == ty:
+			err = tx.Delete(&existing).Error
+		case found:
+			if perr := c.checkReactionType(tx, ty); perr != nil {
+				return perr
+			}
+
+			existing.Type = ty
+			err = tx.Save(&existing).Error
+		default:
+			if perr := c.checkReactionType(tx, ty); perr != nil {
+				return perr
+			}
+
+			err = tx.Create(&DbCommentReaction{UserID: userID, CommentID: commentID, Type: ty, AddedAt: time.Now()}).Error
+		}
 
-	existing.Type = ty
+		if err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
 
-	err = c.db.Save(&existing).Error
+		// The counts are updated by the trigger
+		if err := tx.Select("id, reaction_counts").First(&comment, commentID).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
 
-	if err != nil {
-		return -1, gormerrors.GormErrorCast(err)
+		return nil
+	})
+
+	if perr != nil {
+		return nil, perr
 	}
 
-	return commentID, nil
+	reactions, perr := c.loadReactionsForComments([]DbComment{comment}, &userID)
+
+	if perr != nil {
+		return nil, perr
+	}
+
+	result := reactions[commentID]
+
+	return &result, nil
 }
@@ -0,0 +1,110 @@
+package gormpostgres
+
+import (
+	"math"
+	"testing"
+)
+
+// TestWilsonLowerBound checks the ranking function of the helpful sort against the
+// values of the Wilson score interval with z = 1.96
+func TestWilsonLowerBound(t *testing.T) {
+	tx := newTestTx(t)
+
+	cases := []struct {
+		likes, dislikes int64
+		expected        float64
+	}{
+		{0, 0, 0},
+		{0, 3, 0},
+		{1, 0, 0.2065},
+		{5, 5, 0.2366},
+		{10, 0, 0.7225},
+		{100, 10, 0.8407},
+	}
+
+	for _, c := range cases {
+		var score float64
+
+		if err := tx.Raw("SELECT wilson_lower_bound(?, ?)", c.likes, c.dislikes).Scan(&score).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		if math.Abs(score-c.expected) > 1e-3 {
+			t.Errorf("wilson_lower_bound(%d, %d) = %.4f, expected %.4f", c.likes, c.dislikes, score, c.expected)
+		}
+	}
+}
+
+// TestLoadAnswersV2 checks that the answers tree is loaded with the authors, the ratings
+// and the edit and deletion state
+func TestLoadAnswersV2(t *testing.T) {
+	tx := newTestTx(t)
+
+	var userID, productID, rootID, answerID int64
+
+	err := tx.Raw(`
+		INSERT INTO Users (name, email, passwordhash) VALUES ('answers-test', 'answers-test@example.com', '')
+		RETURNING id`,
+	).Scan(&userID).Error
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = tx.Raw(`
+		INSERT INTO Products (name, price, currency, chars_table_name, chars_id)
+		VALUES ('answers-test', 100, 'RUB', 'laptopchars', 0)
+		RETURNING id`,
+	).Scan(&productID).Error
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = tx.Raw(`
+		INSERT INTO Comments (user_id, product_id, comment_text, rating) VALUES (?, ?, 'review', 80)
+		RETURNING id`, userID, productID,
+	).Scan(&rootID).Error
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = tx.Raw(`
+		INSERT INTO Comments (user_id, product_id, comment_text, answer_on, updated_at, is_deleted)
+		VALUES (?, ?, 'answer', ?, now(), true)
+		RETURNING id`, userID, productID, rootID,
+	).Scan(&answerID).Error
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.Exec("UPDATE Comments SET verified_purchase = true WHERE id = ?", rootID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	comments, perr := NewGormPostgresControllerFromDB(tx).loadAnswersV2(rootID, nil, false, 10, 0)
+
+	if perr != nil {
+		t.Fatal(perr)
+	}
+
+	if len(comments) != 2 || comments[0].ID != rootID || comments[1].ID != answerID {
+		t.Fatalf("unexpected comments %+v", comments)
+	}
+
+	for _, c := range comments {
+		if c.UserID != userID || c.User.ID != int(userID) {
+			t.Errorf("comment %d: author %d, user %d, expected %d", c.ID, c.UserID, c.User.ID, userID)
+		}
+	}
+
+	if root := comments[0]; root.Rating == nil || *root.Rating != 80 || !root.VerifiedPurchase || root.Deleted {
+		t.Errorf("unexpected root comment %+v", root)
+	}
+
+	if answer := comments[1]; answer.UpdatedAt == nil || !answer.Deleted || answer.Rating != nil {
+		t.Errorf("unexpected answer %+v", answer)
+	}
+}
@@ -1,12 +1,16 @@
 package gormpostgres
 
 import (
+	"github.com/PC-Core/pc-core-backend/internal/contentfilter"
+	gormerrors "github.com/PC-Core/pc-core-backend/internal/database/gormPostgres/gormErrors"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 type GormPostgresController struct {
-	db *gorm.DB
+	db     *gorm.DB
+	filter *contentfilter.Pipeline
 }
 
 func NewGormPostgresController(conn string) (*GormPostgresController, error) {
@@ -16,5 +20,39 @@ func NewGormPostgresController(conn string) (*GormPostgresController, error) {
 		return nil, err
 	}
 
-	return &GormPostgresController{db}, nil
+	return &GormPostgresController{db, nil}, nil
+}
+
+// NewGormPostgresControllerFromDB wraps the opened connection, e.g. the transaction
+// or the session with the custom logger
+func NewGormPostgresControllerFromDB(db *gorm.DB) *GormPostgresController {
+	return &GormPostgresController{db, nil}
+}
+
+// UseContentFilter enables the checks of the comment texts. Without the filter
+// all the texts are saved as is
+func (c *GormPostgresController) UseContentFilter(filter *contentfilter.Pipeline) {
+	c.filter = filter
+}
+
+// withTransaction runs fn inside the transaction. The transaction is committed
+// only if fn returns nil
+func (c *GormPostgresController) withTransaction(fn func(tx *gorm.DB) errors.PCCError) errors.PCCError {
+	tx := c.db.Begin()
+
+	if tx.Error != nil {
+		return gormerrors.GormErrorCast(tx.Error)
+	}
+
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return gormerrors.GormErrorCast(err)
+	}
+
+	return nil
 }
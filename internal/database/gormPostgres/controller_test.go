@@ -0,0 +1,40 @@
+package gormpostgres
+
+import (
+	"os"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const envTestPostgres = "PCCORE_POSTGRES_CONN"
+
+// newTestTx opens the transaction in the database migrated to the latest version. The
+// transaction is rolled back when the test is finished. The test is skipped if the
+// database is not configured
+func newTestTx(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	conn := os.Getenv(envTestPostgres)
+
+	if conn == "" {
+		t.Skipf("%s is not set", envTestPostgres)
+	}
+
+	db, err := gorm.Open(postgres.Open(conn), &gorm.Config{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := db.Begin()
+
+	if tx.Error != nil {
+		t.Fatal(tx.Error)
+	}
+
+	t.Cleanup(func() { tx.Rollback() })
+
+	return tx
+}
@@ -62,6 +62,7 @@ func (c *GormPostgresController) AddCpu(cpu *inputs.AddCpuInput) (*models.Produc
 	product := DbProduct{
 		Name:           cpu.Name,
 		Price:          cpu.Price,
+		Currency:       cpu.Price.Currency,
 		Selled:         0,
 		Stock:          cpu.Stock,
 		CharsTableName: database.CpuCharsTable,
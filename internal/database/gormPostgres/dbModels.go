@@ -14,6 +14,8 @@ type DbCart struct {
 	Product   DbProductWithMedias `gorm:"foreignKey:ProductID"`
 	Quantity  uint                `gorm:"not null"`
 	AddedAt   time.Time           `gorm:"autoCreateTime"`
+	// PriceAtAdd is set by the trigger on insert
+	PriceAtAdd *models.Money `gorm:"column:price_at_add;->"`
 }
 
 func (DbCart) TableName() string {
@@ -26,21 +28,30 @@ func DbCartIntoCart(cart []DbCart) *models.Cart {
 
 	for _, c := range cart {
 		user_id = c.UserID
-		items = append(items, *models.NewCartItem(*c.Product.IntoProduct(), c.Quantity, c.AddedAt))
+		item := models.NewCartItem(*c.Product.IntoProduct(), c.Quantity, c.AddedAt)
+
+		if c.PriceAtAdd != nil {
+			// The price is copied from the product, so it has the product currency
+			price := c.PriceAtAdd.In(c.Product.Currency)
+			item.PriceAtAdd = &price
+		}
+
+		items = append(items, *item)
 	}
 
 	return models.NewCart(user_id, items)
 }
 
 type DbProducts struct {
-	ID            uint64   `gorm:"primaryKey"`
-	Name          string   `gorm:"column:name"`
-	Price         float64  `gorm:"column:price"`
-	Selled        int      `gorm:"column:selled"`
-	Stock         int      `gorm:"column:stock"`
-	CharTableName string   `gorm:"column:chars_table_name"`
-	CharId        uint64   `gorm:"column:chars_id"`
-	Medias        []uint64 `gorm:"column:medias"`
+	ID            uint64          `gorm:"primaryKey"`
+	Name          string          `gorm:"column:name"`
+	Price         models.Money    `gorm:"column:price"`
+	Currency      models.Currency `gorm:"column:currency"`
+	Selled        int             `gorm:"column:selled"`
+	Stock         int             `gorm:"column:stock"`
+	CharTableName string          `gorm:"column:chars_table_name"`
+	CharId        uint64          `gorm:"column:chars_id"`
+	Medias        []uint64        `gorm:"column:medias"`
 }
 
 func (DbProducts) TableName() string {
@@ -120,6 +131,7 @@ func (m *DbMedia) IntoMedia() *models.Media {
 		m.Url,
 		m.Type,
 		m.ProductID,
+		nil,
 	)
 }
 
@@ -136,26 +148,33 @@ func (ms DbMedias) IntoMedias() models.Medias {
 }
 
 type DbProductWithMedias struct {
-	ID             uint64   `gorm:"column:id"`
-	Name           string   `gorm:"column:name"`
-	Price          float64  `gorm:"column:price"`
-	Selled         uint64   `gorm:"column:selled"`
-	Stock          uint64   `gorm:"column:stock"`
-	CharsTableName string   `gorm:"column:chars_table_name"`
-	CharsID        uint64   `gorm:"column:chars_id"`
-	Medias         DbMedias `gorm:"foreignKey:ProductID"`
+	ID             uint64           `gorm:"column:id"`
+	Name           string           `gorm:"column:name"`
+	Price          models.Money     `gorm:"column:price"`
+	Currency       models.Currency  `gorm:"column:currency"`
+	Selled         uint64           `gorm:"column:selled"`
+	Stock          uint64           `gorm:"column:stock"`
+	Reserved       uint64           `gorm:"column:reserved;->"`
+	CharsTableName string           `gorm:"column:chars_table_name"`
+	CharsID        uint64           `gorm:"column:chars_id"`
+	Medias         DbMedias         `gorm:"foreignKey:ProductID"`
+	Rating         *DbProductRating `gorm:"foreignKey:ProductID"`
 }
 
+// IntoProduct converts the product. The rating must be preloaded, the products
+// without the rating row have the empty rating
 func (p *DbProductWithMedias) IntoProduct() *models.Product {
 	return models.NewProduct(
 		p.ID,
 		p.Name,
-		p.Price,
+		p.Price.In(p.Currency),
 		p.Selled,
 		p.Stock,
+		p.Reserved,
 		p.Medias.IntoMedias(),
 		p.CharsTableName,
 		p.CharsID,
+		p.Rating.IntoProductRating(),
 	)
 }
 
@@ -164,29 +183,42 @@ func (DbProductWithMedias) TableName() string {
 }
 
 type DbProduct struct {
-	ID             uint64  `gorm:"primaryKey"`
-	Name           string  `gorm:"column:name"`
-	Price          float64 `gorm:"column:price"`
-	Selled         uint64  `gorm:"column:selled"`
-	Stock          uint64  `gorm:"column:stock"`
-	CharsTableName string  `gorm:"column:chars_table_name"`
-	CharsID        uint64  `gorm:"column:chars_id"`
+	ID             uint64          `gorm:"primaryKey"`
+	Name           string          `gorm:"column:name"`
+	Price          models.Money    `gorm:"column:price"`
+	Currency       models.Currency `gorm:"column:currency"`
+	Selled         uint64          `gorm:"column:selled"`
+	Stock          uint64          `gorm:"column:stock"`
+	Reserved       uint64          `gorm:"column:reserved;->"`
+	CharsTableName string          `gorm:"column:chars_table_name"`
+	CharsID        uint64          `gorm:"column:chars_id"`
 }
 
 func (DbProduct) TableName() string {
 	return "products"
 }
 
+// Available returns the stock which is not reserved by the checkouts
+func (p *DbProduct) Available() uint64 {
+	if p.Reserved >= p.Stock {
+		return 0
+	}
+
+	return p.Stock - p.Reserved
+}
+
 func (p *DbProduct) WithMediasIntoProduct(medias models.Medias) *models.Product {
 	return models.NewProduct(
 		p.ID,
 		p.Name,
-		p.Price,
+		p.Price.In(p.Currency),
 		p.Selled,
 		p.Stock,
+		p.Reserved,
 		medias,
 		p.CharsTableName,
 		p.CharsID,
+		nil,
 	)
 }
 
@@ -219,6 +251,15 @@ type DbUser struct {
 	Email        string          `gorm:"column:email"`
 	Role         models.UserRole `gorm:"column:role;default:'Default'"`
 	PasswordHash string          `gorm:"column:passwordhash"`
+	TotpSecret   *string         `gorm:"column:totp_secret"`
+	TotpLastStep *int64          `gorm:"column:totp_last_step"`
+	MfaEnabled   bool            `gorm:"column:mfa_enabled"`
+	Disabled     bool            `gorm:"column:disabled"`
+	AvatarURL    *string         `gorm:"column:avatar_url"`
+	// CreatedAt is not set for the accounts created before it was tracked
+	CreatedAt      *time.Time         `gorm:"column:created_at;->"`
+	CommentsBanned bool               `gorm:"column:comments_banned"`
+	Permissions    []DbRolePermission `gorm:"foreignKey:Role;references:Role"`
 }
 
 func (DbUser) TableName() string {
@@ -226,7 +267,107 @@ func (DbUser) TableName() string {
 }
 
 func (u *DbUser) IntoUser() *models.User {
-	return models.NewUser(u.ID, u.Name, u.Email, u.Role, u.PasswordHash)
+	user := models.NewUser(u.ID, u.Name, u.Email, u.Role, u.PasswordHash)
+	user.MfaEnabled = u.MfaEnabled
+	user.TotpSecret = u.TotpSecret
+	user.Disabled = u.Disabled
+	user.AvatarURL = u.AvatarURL
+	user.CommentsBanned = u.CommentsBanned
+
+	for _, perm := range u.Permissions {
+		user.Permissions = append(user.Permissions, perm.Permission)
+	}
+
+	return user
+}
+
+type DbRole struct {
+	Name        string             `gorm:"column:name;primaryKey"`
+	Permissions []DbRolePermission `gorm:"foreignKey:Role;references:Name"`
+}
+
+func (DbRole) TableName() string {
+	return "roles"
+}
+
+func (r *DbRole) IntoRole() *models.Role {
+	perms := make(models.Permissions, 0, len(r.Permissions))
+
+	for _, perm := range r.Permissions {
+		perms = append(perms, perm.Permission)
+	}
+
+	return models.NewRole(models.UserRole(r.Name), perms)
+}
+
+type DbRolePermission struct {
+	Role       models.UserRole   `gorm:"column:role;primaryKey"`
+	Permission models.Permission `gorm:"column:permission;primaryKey"`
+}
+
+func (DbRolePermission) TableName() string {
+	return "rolepermissions"
+}
+
+type DbRecoveryCode struct {
+	ID       int        `gorm:"column:id;primaryKey"`
+	UserID   int        `gorm:"column:user_id"`
+	CodeHash string     `gorm:"column:code_hash"`
+	UsedAt   *time.Time `gorm:"column:used_at"`
+}
+
+func (DbRecoveryCode) TableName() string {
+	return "recoverycodes"
+}
+
+type DbEmailVerification struct {
+	ID        int       `gorm:"column:id;primaryKey"`
+	UserID    int       `gorm:"column:user_id"`
+	Email     string    `gorm:"column:email"`
+	TokenHash string    `gorm:"column:token_hash"`
+	ExpiresAt time.Time `gorm:"column:expires_at"`
+}
+
+func (DbEmailVerification) TableName() string {
+	return "emailverifications"
+}
+
+type DbAddress struct {
+	ID         int64  `gorm:"column:id;primaryKey"`
+	UserID     int    `gorm:"column:user_id"`
+	Label      string `gorm:"column:label"`
+	Recipient  string `gorm:"column:recipient"`
+	Phone      string `gorm:"column:phone"`
+	Country    string `gorm:"column:country"`
+	City       string `gorm:"column:city"`
+	Street     string `gorm:"column:street"`
+	PostalCode string `gorm:"column:postal_code"`
+	IsDefault  bool   `gorm:"column:is_default"`
+}
+
+func (DbAddress) TableName() string {
+	return "useraddresses"
+}
+
+func (a *DbAddress) IntoAddress() *models.Address {
+	return models.NewAddress(a.ID, a.Label, a.Recipient, a.Phone, a.Country, a.City, a.Street, a.PostalCode, a.IsDefault)
+}
+
+type DbAuditRecord struct {
+	ID           int64              `gorm:"column:id;primaryKey"`
+	ActorID      int                `gorm:"column:actor_id"`
+	Action       models.AuditAction `gorm:"column:action"`
+	TargetUserID *int               `gorm:"column:target_user_id"`
+	Details      map[string]any     `gorm:"column:details;type:jsonb;serializer:json"`
+	CreatedAt    time.Time          `gorm:"column:created_at;default:now()"`
+}
+
+func (DbAuditRecord) TableName() string {
+	return "auditlog"
+}
+
+func (r *DbAuditRecord) IntoAuditRecord() *models.AuditRecord {
+	return models.NewAuditRecord(r.ID, r.ActorID, r.Action, r.TargetUserID, r.Details, r.CreatedAt)
 }
 
 type DbComment struct {
@@ -240,6 +381,13 @@ type DbComment struct {
 	UpdatedAt   *time.Time    `gorm:"column:updated_at"`
 	MediaIDs    pq.Int64Array `gorm:"column:media_ids;type:bigint[]"`
 	Deleted     bool          `gorm:"column:is_deleted"`
+	// VerifiedPurchase is set by the trigger
+	VerifiedPurchase bool                    `gorm:"column:verified_purchase;->"`
+	ModerationStatus models.ModerationStatus `gorm:"column:moderation_status;default:visible"`
+	// FilterFlags are the reasons of the content filter to mask or hold the comment
+	FilterFlags pq.StringArray `gorm:"column:filter_flags;type:text[]"`
+	// ReactionCounts are maintained by the trigger on the reactions
+	ReactionCounts map[models.ReactionType]uint64 `gorm:"column:reaction_counts;type:jsonb;serializer:json;->"`
 
 	User    DbUser    `gorm:"foreignKey:UserID;references:ID"`
 	Product DbProduct `gorm:"foreignKey:ProductID;references:ID"`
@@ -260,6 +408,21 @@ func (DbCommentReaction) TableName() string {
 	return "commentreactions"
 }
 
+type DbReactionType struct {
+	Name     models.ReactionType `gorm:"column:name;primaryKey"`
+	Emoji    string              `gorm:"column:emoji"`
+	Position int                 `gorm:"column:position"`
+	Active   bool                `gorm:"column:active"`
+}
+
+func (DbReactionType) TableName() string {
+	return "reactiontypes"
+}
+
+func (t *DbReactionType) IntoReactionTypeInfo() *models.ReactionTypeInfo {
+	return models.NewReactionTypeInfo(t.Name, t.Emoji, t.Position, t.Active)
+}
+
 type DbGpuChars struct {
 	ID           uint64 `gorm:"column:id;primaryKey"`
 	Name         string `gorm:"column:name"`
@@ -335,3 +498,538 @@ func (chars *DbMouseChars) IntoMouse() *models.MouseChars {
 func (DbMouseChars) TableName() string {
 	return "mousechars"
 }
+
+type DbPromotion struct {
+	ID           int64                 `gorm:"column:id;primaryKey"`
+	Code         string                `gorm:"column:code"`
+	Title        string                `gorm:"column:title"`
+	Kind         models.PromotionKind  `gorm:"column:kind"`
+	Percent      uint                  `gorm:"column:percent"`
+	Amount       *models.Money         `gorm:"column:amount"`
+	Currency     models.Currency       `gorm:"column:currency"`
+	Scope        models.PromotionScope `gorm:"column:scope"`
+	ProductIDs   pq.Int64Array         `gorm:"column:product_ids;type:bigint[]"`
+	CategoryIDs  pq.Int64Array         `gorm:"column:category_ids;type:bigint[]"`
+	CharsTables  pq.StringArray        `gorm:"column:chars_tables;type:text[]"`
+	MinCart      *models.Money         `gorm:"column:min_cart"`
+	UsageLimit   *uint                 `gorm:"column:usage_limit"`
+	PerUserLimit *uint                 `gorm:"column:per_user_limit"`
+	StartsAt     time.Time             `gorm:"column:starts_at"`
+	EndsAt       *time.Time            `gorm:"column:ends_at"`
+	Stackable    bool                  `gorm:"column:stackable"`
+	Priority     int                   `gorm:"column:priority"`
+	Active       bool                  `gorm:"column:active"`
+	CreatedAt    time.Time             `gorm:"column:created_at;default:now()"`
+	// Used and UsedByUser are selected with the redemptions count
+	Used       uint `gorm:"column:used;->"`
+	UsedByUser uint `gorm:"column:used_by_user;->"`
+}
+
+func (DbPromotion) TableName() string {
+	return "promotions"
+}
+
+func (p *DbPromotion) IntoPromotion(categoryCharsTables []string) *models.Promotion {
+	var amount, minCart *models.Money
+
+	if p.Amount != nil {
+		value := p.Amount.In(p.Currency)
+		amount = &value
+	}
+
+	if p.MinCart != nil {
+		value := p.MinCart.In(p.Currency)
+		minCart = &value
+	}
+
+	return &models.Promotion{
+		ID:                  p.ID,
+		Code:                p.Code,
+		Title:               p.Title,
+		Kind:                p.Kind,
+		Percent:             p.Percent,
+		Amount:              amount,
+		Currency:            p.Currency,
+		Scope:               p.Scope,
+		ProductIDs:          int64ArrayIntoUint64(p.ProductIDs),
+		CategoryIDs:         int64ArrayIntoUint64(p.CategoryIDs),
+		CharsTables:         []string(p.CharsTables),
+		CategoryCharsTables: categoryCharsTables,
+		MinCart:             minCart,
+		UsageLimit:          p.UsageLimit,
+		PerUserLimit:        p.PerUserLimit,
+		StartsAt:            p.StartsAt,
+		EndsAt:              p.EndsAt,
+		Stackable:           p.Stackable,
+		Priority:            p.Priority,
+		Active:              p.Active,
+		Used:                p.Used,
+		UsedByUser:          p.UsedByUser,
+	}
+}
+
+func int64ArrayIntoUint64(arr pq.Int64Array) []uint64 {
+	res := make([]uint64, 0, len(arr))
+
+	for _, v := range arr {
+		res = append(res, uint64(v))
+	}
+
+	return res
+}
+
+type DbCartPromotion struct {
+	UserID      int       `gorm:"column:user_id;primaryKey"`
+	PromotionID int64     `gorm:"column:promotion_id;primaryKey"`
+	AppliedAt   time.Time `gorm:"column:applied_at;default:now()"`
+}
+
+func (DbCartPromotion) TableName() string {
+	return "cartpromotions"
+}
+
+type DbPromotionRedemption struct {
+	ID          int64     `gorm:"column:id;primaryKey"`
+	PromotionID int64     `gorm:"column:promotion_id"`
+	UserID      int       `gorm:"column:user_id"`
+	OrderID     *int64    `gorm:"column:order_id"`
+	RedeemedAt  time.Time `gorm:"column:redeemed_at;default:now()"`
+}
+
+func (DbPromotionRedemption) TableName() string {
+	return "promotionredemptions"
+}
+
+type DbWishlist struct {
+	UserID    int                 `gorm:"column:user_id;primaryKey"`
+	ProductID uint64              `gorm:"column:product_id;primaryKey"`
+	Product   DbProductWithMedias `gorm:"foreignKey:ProductID"`
+	AddedAt   time.Time           `gorm:"column:added_at;default:now()"`
+}
+
+func (DbWishlist) TableName() string {
+	return "wishlist"
+}
+
+func DbWishlistIntoItems(wishlist []DbWishlist) []models.WishlistItem {
+	items := make([]models.WishlistItem, 0, len(wishlist))
+
+	for _, w := range wishlist {
+		items = append(items, *models.NewWishlistItem(*w.Product.IntoProduct(), w.AddedAt))
+	}
+
+	return items
+}
+
+type DbWishlistShare struct {
+	UserID    int       `gorm:"column:user_id;primaryKey"`
+	Token     string    `gorm:"column:token"`
+	CreatedAt time.Time `gorm:"column:created_at;default:now()"`
+}
+
+func (DbWishlistShare) TableName() string {
+	return "wishlistshares"
+}
+
+type DbProductSubscription struct {
+	ID               int64                      `gorm:"column:id;primaryKey"`
+	UserID           int                        `gorm:"column:user_id"`
+	ProductID        uint64                     `gorm:"column:product_id"`
+	Kind             models.SubscriptionKind    `gorm:"column:kind"`
+	PriceThreshold   *models.Money              `gorm:"column:price_threshold"`
+	Channel          models.NotificationChannel `gorm:"column:channel"`
+	WebhookURL       *string                    `gorm:"column:webhook_url"`
+	UnsubscribeToken string                     `gorm:"column:unsubscribe_token"`
+	LastNotifiedAt   *time.Time                 `gorm:"column:last_notified_at"`
+	CreatedAt        time.Time                  `gorm:"column:created_at;default:now()"`
+	// Currency is the currency of the product selected with the subscription
+	Currency models.Currency `gorm:"column:currency;->"`
+}
+
+func (DbProductSubscription) TableName() string {
+	return "productsubscriptions"
+}
+
+func (s *DbProductSubscription) IntoProductSubscription() *models.ProductSubscription {
+	var threshold *models.Money
+
+	if s.PriceThreshold != nil {
+		value := s.PriceThreshold.In(s.Currency)
+		threshold = &value
+	}
+
+	return models.NewProductSubscription(s.ID, s.ProductID, s.Kind, threshold, s.Channel, s.WebhookURL, s.LastNotifiedAt, s.CreatedAt)
+}
+
+type DbNotification struct {
+	ID             int64                      `gorm:"column:id;primaryKey"`
+	SubscriptionID *int64                     `gorm:"column:subscription_id"`
+	UserID         int                        `gorm:"column:user_id"`
+	ProductID      uint64                     `gorm:"column:product_id"`
+	Kind           models.SubscriptionKind    `gorm:"column:kind"`
+	Channel        models.NotificationChannel `gorm:"column:channel"`
+	Payload        models.NotificationPayload `gorm:"column:payload;type:jsonb"`
+	DedupKey       string                     `gorm:"column:dedup_key"`
+	Status         models.NotificationStatus  `gorm:"column:status"`
+	Attempts       int                        `gorm:"column:attempts"`
+	LastError      *string                    `gorm:"column:last_error"`
+	ClaimedAt      *time.Time                 `gorm:"column:claimed_at"`
+	CreatedAt      time.Time                  `gorm:"column:created_at"`
+	SentAt         *time.Time                 `gorm:"column:sent_at"`
+	ReadAt         *time.Time                 `gorm:"column:read_at"`
+	// The delivery data is selected only when the notifications are claimed
+	UserEmail        string  `gorm:"column:user_email;->"`
+	WebhookURL       *string `gorm:"column:webhook_url;->"`
+	UnsubscribeToken *string `gorm:"column:unsubscribe_token;->"`
+}
+
+func (DbNotification) TableName() string {
+	return "notifications"
+}
+
+func (n *DbNotification) IntoNotification() *models.Notification {
+	return &models.Notification{
+		ID:               n.ID,
+		SubscriptionID:   n.SubscriptionID,
+		ProductID:        n.ProductID,
+		Kind:             n.Kind,
+		Channel:          n.Channel,
+		Payload:          n.Payload,
+		CreatedAt:        n.CreatedAt,
+		ReadAt:           n.ReadAt,
+		UserEmail:        n.UserEmail,
+		WebhookURL:       n.WebhookURL,
+		UnsubscribeToken: n.UnsubscribeToken,
+	}
+}
+
+func DbNotificationsIntoNotifications(notifications []DbNotification) []models.Notification {
+	result := make([]models.Notification, 0, len(notifications))
+
+	for i := range notifications {
+		result = append(result, *notifications[i].IntoNotification())
+	}
+
+	return result
+}
+
+type DbOrder struct {
+	ID          int64              `gorm:"column:id;primaryKey"`
+	UserID      int                `gorm:"column:user_id"`
+	Status      models.OrderStatus `gorm:"column:status"`
+	Subtotal    models.Money       `gorm:"column:subtotal"`
+	Discount    models.Money       `gorm:"column:discount"`
+	Total       models.Money       `gorm:"column:total"`
+	Currency    models.Currency    `gorm:"column:currency"`
+	ExpiresAt   time.Time          `gorm:"column:expires_at"`
+	CreatedAt   time.Time          `gorm:"column:created_at;default:now()"`
+	CompletedAt *time.Time         `gorm:"column:completed_at"`
+	Items       []DbOrderItem      `gorm:"foreignKey:OrderID"`
+}
+
+func (DbOrder) TableName() string {
+	return "orders"
+}
+
+func (o *DbOrder) IntoOrder() *models.Order {
+	items := make([]models.OrderItem, 0, len(o.Items))
+
+	for _, item := range o.Items {
+		items = append(items, *models.NewOrderItem(item.ProductID, item.Name, item.Quantity, item.Price.In(o.Currency), item.Discount.In(o.Currency)))
+	}
+
+	return models.NewOrder(
+		o.ID,
+		o.UserID,
+		o.Status,
+		items,
+		o.Subtotal.In(o.Currency),
+		o.Discount.In(o.Currency),
+		o.Total.In(o.Currency),
+		o.ExpiresAt,
+		o.CreatedAt,
+		o.CompletedAt,
+	)
+}
+
+type DbOrderItem struct {
+	OrderID   int64        `gorm:"column:order_id;primaryKey"`
+	ProductID uint64       `gorm:"column:product_id;primaryKey"`
+	Name      string       `gorm:"column:name"`
+	Quantity  uint         `gorm:"column:quantity"`
+	Price     models.Money `gorm:"column:price"`
+	Discount  models.Money `gorm:"column:discount"`
+}
+
+func (DbOrderItem) TableName() string {
+	return "orderitems"
+}
+
+type DbOrderPromotion struct {
+	OrderID     int64        `gorm:"column:order_id;primaryKey"`
+	PromotionID int64        `gorm:"column:promotion_id;primaryKey"`
+	Discount    models.Money `gorm:"column:discount"`
+}
+
+func (DbOrderPromotion) TableName() string {
+	return "orderpromotions"
+}
+
+type DbStockReservation struct {
+	OrderID   int64  `gorm:"column:order_id;primaryKey"`
+	ProductID uint64 `gorm:"column:product_id;primaryKey"`
+	Quantity  uint   `gorm:"column:quantity"`
+}
+
+func (DbStockReservation) TableName() string {
+	return "stockreservations"
+}
+
+type DbWarehouse struct {
+	ID        int                  `gorm:"column:id;primaryKey"`
+	Name      string               `gorm:"column:name"`
+	Kind      models.WarehouseKind `gorm:"column:kind"`
+	Address   string               `gorm:"column:address"`
+	IsDefault bool                 `gorm:"column:is_default;->"`
+	Active    bool                 `gorm:"column:active"`
+	CreatedAt time.Time            `gorm:"column:created_at;default:now()"`
+}
+
+func (DbWarehouse) TableName() string {
+	return "warehouses"
+}
+
+func (w *DbWarehouse) IntoWarehouse() *models.Warehouse {
+	return models.NewWarehouse(w.ID, w.Name, w.Kind, w.Address, w.IsDefault, w.Active)
+}
+
+type DbWarehouseStock struct {
+	WarehouseID int    `gorm:"column:warehouse_id;primaryKey"`
+	ProductID   uint64 `gorm:"column:product_id;primaryKey"`
+	Quantity    uint64 `gorm:"column:quantity"`
+}
+
+func (DbWarehouseStock) TableName() string {
+	return "warehousestock"
+}
+
+type DbStockMovement struct {
+	ID              int64                    `gorm:"column:id;primaryKey"`
+	Kind            models.StockMovementKind `gorm:"column:kind"`
+	ProductID       uint64                   `gorm:"column:product_id"`
+	FromWarehouseID *int                     `gorm:"column:from_warehouse_id"`
+	ToWarehouseID   *int                     `gorm:"column:to_warehouse_id"`
+	Quantity        uint64                   `gorm:"column:quantity"`
+	Supplier        *string                  `gorm:"column:supplier"`
+	OrderID         *int64                   `gorm:"column:order_id"`
+	Note            *string                  `gorm:"column:note"`
+	CreatedBy       *int                     `gorm:"column:created_by"`
+	CreatedAt       time.Time                `gorm:"column:created_at;default:now()"`
+}
+
+func (DbStockMovement) TableName() string {
+	return "stockmovements"
+}
+
+func (m *DbStockMovement) IntoStockMovement() *models.StockMovement {
+	return models.NewStockMovement(m.ID, m.Kind, m.ProductID, m.FromWarehouseID, m.ToWarehouseID, m.Quantity, m.Supplier, m.OrderID, m.Note, m.CreatedBy, m.CreatedAt)
+}
+
+func DbStockMovementsIntoMovements(movements []DbStockMovement) []models.StockMovement {
+	result := make([]models.StockMovement, 0, len(movements))
+
+	for i := range movements {
+		result = append(result, *movements[i].IntoStockMovement())
+	}
+
+	return result
+}
+
+type DbPriceHistory struct {
+	ID                int64           `gorm:"column:id;primaryKey"`
+	ProductID         uint64          `gorm:"column:product_id"`
+	Price             models.Money    `gorm:"column:price"`
+	Currency          models.Currency `gorm:"column:currency"`
+	ChangedBy         *int            `gorm:"column:changed_by"`
+	ScheduledChangeID *int64          `gorm:"column:scheduled_change_id"`
+	ChangedAt         time.Time       `gorm:"column:changed_at"`
+}
+
+func (DbPriceHistory) TableName() string {
+	return "pricehistory"
+}
+
+func DbPriceHistoryIntoEntries(history []DbPriceHistory) []models.PriceHistoryEntry {
+	result := make([]models.PriceHistoryEntry, 0, len(history))
+
+	for _, h := range history {
+		result = append(result, *models.NewPriceHistoryEntry(h.ID, h.ProductID, h.Price.In(h.Currency), h.ChangedBy, h.ScheduledChangeID, h.ChangedAt))
+	}
+
+	return result
+}
+
+type DbScheduledPriceChange struct {
+	ID        int64                             `gorm:"column:id;primaryKey"`
+	ProductID uint64                            `gorm:"column:product_id"`
+	Price     models.Money                      `gorm:"column:price"`
+	ApplyAt   time.Time                         `gorm:"column:apply_at"`
+	Status    models.ScheduledPriceChangeStatus `gorm:"column:status;default:pending"`
+	Note      *string                           `gorm:"column:note"`
+	CreatedBy *int                              `gorm:"column:created_by"`
+	CreatedAt time.Time                         `gorm:"column:created_at;default:now()"`
+	AppliedAt *time.Time                        `gorm:"column:applied_at"`
+	// Currency is selected from the product
+	Currency models.Currency `gorm:"column:currency;->"`
+}
+
+func (DbScheduledPriceChange) TableName() string {
+	return "scheduledpricechanges"
+}
+
+func (c *DbScheduledPriceChange) IntoScheduledPriceChange() *models.ScheduledPriceChange {
+	return models.NewScheduledPriceChange(c.ID, c.ProductID, c.Price.In(c.Currency), c.ApplyAt, c.Status, c.Note, c.CreatedBy, c.CreatedAt, c.AppliedAt)
+}
+
+func DbScheduledPriceChangesIntoChanges(changes []DbScheduledPriceChange) []models.ScheduledPriceChange {
+	result := make([]models.ScheduledPriceChange, 0, len(changes))
+
+	for i := range changes {
+		result = append(result, *changes[i].IntoScheduledPriceChange())
+	}
+
+	return result
+}
+
+type DbProductRating struct {
+	ProductID       uint64        `gorm:"column:product_id;primaryKey"`
+	RatingCount     uint64        `gorm:"column:rating_count"`
+	Average         float64       `gorm:"column:average"`
+	Histogram       pq.Int64Array `gorm:"column:histogram;type:integer[]"`
+	VerifiedCount   uint64        `gorm:"column:verified_count"`
+	VerifiedAverage float64       `gorm:"column:verified_average"`
+}
+
+func (DbProductRating) TableName() string {
+	return "productratings"
+}
+
+func (r *DbProductRating) IntoProductRating() *models.ProductRating {
+	if r == nil {
+		return models.NewProductRating(0, 0, nil, 0, 0)
+	}
+
+	histogram := make([]uint64, 0, len(r.Histogram))
+
+	for _, v := range r.Histogram {
+		histogram = append(histogram, uint64(v))
+	}
+
+	return models.NewProductRating(r.Average, r.RatingCount, histogram, r.VerifiedAverage, r.VerifiedCount)
+}
+
+type DbCommentReport struct {
+	ID         int64               `gorm:"column:id;primaryKey"`
+	CommentID  int64               `gorm:"column:comment_id"`
+	ReporterID int                 `gorm:"column:reporter_id"`
+	Reason     models.ReportReason `gorm:"column:reason"`
+	Note       *string             `gorm:"column:note"`
+	CreatedAt  time.Time           `gorm:"column:created_at;default:now()"`
+	ResolvedAt *time.Time          `gorm:"column:resolved_at"`
+	ResolvedBy *int                `gorm:"column:resolved_by"`
+}
+
+func (DbCommentReport) TableName() string {
+	return "commentreports"
+}
+
+// DbCommentMedia is the image uploaded for the comment. CommentID is nil until the comment is saved
+type DbCommentMedia struct {
+	ID           int64            `gorm:"column:id;primaryKey"`
+	OwnerID      int              `gorm:"column:owner_id"`
+	CommentID    *int64           `gorm:"column:comment_id"`
+	Url          string           `gorm:"column:url"`
+	ThumbnailUrl string           `gorm:"column:thumbnail_url"`
+	Type         models.MediaType `gorm:"column:type"`
+	CreatedAt    time.Time        `gorm:"column:created_at;default:now()"`
+}
+
+func (DbCommentMedia) TableName() string {
+	return "commentmedias"
+}
+
+func (m *DbCommentMedia) IntoMedia(productID int64) *models.Media {
+	return models.NewMedia(uint64(m.ID), m.Url, m.Type, uint64(productID), &m.ThumbnailUrl)
+}
+
+// DbCommentRevision is the replaced version of the comment. The revisions are written by the trigger
+type DbCommentRevision struct {
+	ID          int64     `gorm:"column:id;primaryKey"`
+	CommentID   int64     `gorm:"column:comment_id"`
+	CommentText string    `gorm:"column:comment_text"`
+	Rating      *int16    `gorm:"column:rating"`
+	WrittenAt   time.Time `gorm:"column:written_at"`
+	ReplacedAt  time.Time `gorm:"column:replaced_at"`
+}
+
+func (DbCommentRevision) TableName() string {
+	return "commentrevisions"
+}
+
+type DbProductQuestion struct {
+	ID               int64                      `gorm:"column:id;primaryKey"`
+	UserID           int                        `gorm:"column:user_id"`
+	ProductID        int64                      `gorm:"column:product_id"`
+	QuestionText     string                     `gorm:"column:question_text"`
+	NotifyChannel    models.NotificationChannel `gorm:"column:notify_channel"`
+	AcceptedAnswerID *int64                     `gorm:"column:accepted_answer_id"`
+	// AnswersCount is maintained by the trigger on the answers
+	AnswersCount uint64     `gorm:"column:answers_count;->"`
+	Deleted      bool       `gorm:"column:is_deleted"`
+	CreatedAt    time.Time  `gorm:"column:created_at"`
+	UpdatedAt    *time.Time `gorm:"column:updated_at"`
+
+	User DbUser `gorm:"foreignKey:UserID;references:ID"`
+}
+
+func (DbProductQuestion) TableName() string {
+	return "productquestions"
+}
+
+func (q *DbProductQuestion) IntoQuestion(accepted *models.QuestionAnswer) *models.Question {
+	return models.NewQuestion(q.ID, q.ProductID, q.User.ID, q.User.Name, q.User.AvatarURL, q.QuestionText, q.AnswersCount, accepted, q.CreatedAt, q.UpdatedAt)
+}
+
+type DbQuestionAnswer struct {
+	ID         int64  `gorm:"column:id;primaryKey"`
+	QuestionID int64  `gorm:"column:question_id"`
+	UserID     int    `gorm:"column:user_id"`
+	AnswerText string `gorm:"column:answer_text"`
+	// Staff, VerifiedPurchase and Upvotes are set by the triggers
+	Staff            bool       `gorm:"column:staff;->"`
+	VerifiedPurchase bool       `gorm:"column:verified_purchase;->"`
+	Upvotes          uint64     `gorm:"column:upvotes;->"`
+	Deleted          bool       `gorm:"column:is_deleted"`
+	CreatedAt        time.Time  `gorm:"column:created_at"`
+	UpdatedAt        *time.Time `gorm:"column:updated_at"`
+
+	User DbUser `gorm:"foreignKey:UserID;references:ID"`
+}
+
+func (DbQuestionAnswer) TableName() string {
+	return "questionanswers"
+}
+
+func (a *DbQuestionAnswer) IntoQuestionAnswer(accepted bool, yourUpvote bool) *models.QuestionAnswer {
+	return models.NewQuestionAnswer(a.ID, a.QuestionID, a.User.ID, a.User.Name, a.User.AvatarURL, a.AnswerText, a.Staff, a.VerifiedPurchase, accepted, a.Upvotes, yourUpvote, a.CreatedAt, a.UpdatedAt)
+}
+
+type DbAnswerUpvote struct {
+	AnswerID  int64     `gorm:"column:answer_id;primaryKey"`
+	UserID    int       `gorm:"column:user_id;primaryKey"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (DbAnswerUpvote) TableName() string {
+	return "answerupvotes"
+}
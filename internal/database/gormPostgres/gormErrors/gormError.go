@@ -9,14 +9,33 @@ import (
 )
 
 const (
-	CART_QUANTITY_ERROR = "You're trying to add too many products"
-	UNKNOWN             = "Unknown database error"
-	RECORD_NOT_FOUND    = "Not found"
-	NOT_YOUR_COMMENT    = "You're trying to perform operations with others comment"
+	CART_QUANTITY_ERROR  = "You're trying to add too many products"
+	UNKNOWN              = "Unknown database error"
+	RECORD_NOT_FOUND     = "Not found"
+	NOT_YOUR_COMMENT     = "You're trying to perform operations with others comment"
+	UNIQUE_FAIL          = "The value is already taken"
+	STOCK_UNAVAILABLE    = "Not enough products are available"
+	ORDER_NOT_PENDING    = "The order is already completed, cancelled or expired"
+	REVIEW_EXISTS        = "You have already reviewed this product"
+	RATING_ON_ANSWER     = "Only the root comments can be rated"
+	COMMENTS_BANNED      = "You are banned from commenting"
+	MEDIA_UNAVAILABLE    = "The media is not found or is attached to another comment"
+	REVIEW_EDIT_EXPIRED  = "The review can't be edited anymore"
+	REACTION_UNAVAILABLE = "The reaction is not available"
 )
 
 const KIND = ierrors.EK_DATABASE
 
+// PG_UNIQUE_VIOLATION is the Postgres error code of the UNIQUE constraint failure
+const PG_UNIQUE_VIOLATION = "23505"
+
+const (
+	// COMMENTS_ONE_REVIEW is the unique index of the reviews of the user
+	COMMENTS_ONE_REVIEW = "comments_one_review"
+	// COMMENTS_RATING_ON_ROOT is the check of the rating of the answers
+	COMMENTS_RATING_ON_ROOT = "comments_rating_on_root"
+)
+
 type GormError struct {
 	// code contains the error code in terms of this project
 	code ierrors.ErrorCode
@@ -41,6 +60,29 @@ func parseInnerError(pgErr *pgconn.PgError) *GormError {
 			details: nil,
 			message: CART_QUANTITY_ERROR,
 		}
+	} else if pgErr.Message == "Warehouse stock is insufficient" {
+		return NewStockUnavailableError(nil)
+	} else if pgErr.ConstraintName == COMMENTS_ONE_REVIEW {
+		return &GormError{
+			code:    ierrors.EC_DB_REVIEW_EXISTS,
+			kind:    KIND,
+			details: nil,
+			message: REVIEW_EXISTS,
+		}
+	} else if pgErr.ConstraintName == COMMENTS_RATING_ON_ROOT {
+		return &GormError{
+			code:    ierrors.EC_DB_RATING_ON_ANSWER,
+			kind:    KIND,
+			details: nil,
+			message: RATING_ON_ANSWER,
+		}
+	} else if pgErr.Code == PG_UNIQUE_VIOLATION {
+		return &GormError{
+			code:    ierrors.EC_DB_UNIQUE_FAIL,
+			kind:    KIND,
+			details: pgErr.ConstraintName,
+			message: UNIQUE_FAIL,
+		}
 	} else {
 		return &UNKNOWN_ERROR
 	}
@@ -76,6 +118,71 @@ func NewGormErrorUserOwn(err error) *GormError {
 	return NewGormError(err)
 }
 
+// NewUniqueFailError is returned when the value is checked to be taken before the insert
+func NewUniqueFailError(details any) *GormError {
+	return &GormError{
+		code:    ierrors.EC_DB_UNIQUE_FAIL,
+		kind:    KIND,
+		details: details,
+		message: UNIQUE_FAIL,
+	}
+}
+
+// NewStockUnavailableError creates the error with the products which lack the available stock
+func NewStockUnavailableError(details any) *GormError {
+	return &GormError{
+		code:    ierrors.EC_DB_STOCK_UNAVAILABLE,
+		kind:    KIND,
+		details: details,
+		message: STOCK_UNAVAILABLE,
+	}
+}
+
+func NewOrderNotPendingError() *GormError {
+	return &GormError{
+		code:    ierrors.EC_DB_ORDER_NOT_PENDING,
+		kind:    KIND,
+		details: nil,
+		message: ORDER_NOT_PENDING,
+	}
+}
+
+func NewCommentMediaUnavailableError() *GormError {
+	return &GormError{
+		code:    ierrors.EC_DB_COMMENT_MEDIA_UNAVAILABLE,
+		kind:    KIND,
+		details: nil,
+		message: MEDIA_UNAVAILABLE,
+	}
+}
+
+func NewReviewEditExpiredError() *GormError {
+	return &GormError{
+		code:    ierrors.EC_DB_REVIEW_EDIT_EXPIRED,
+		kind:    KIND,
+		details: nil,
+		message: REVIEW_EDIT_EXPIRED,
+	}
+}
+
+func NewReactionUnavailableError(ty string) *GormError {
+	return &GormError{
+		code:    ierrors.EC_DB_REACTION_UNAVAILABLE,
+		kind:    KIND,
+		details: ty,
+		message: REACTION_UNAVAILABLE,
+	}
+}
+
+func NewCommentsBannedError() *GormError {
+	return &GormError{
+		code:    ierrors.EC_DB_COMMENTS_BANNED,
+		kind:    KIND,
+		details: nil,
+		message: COMMENTS_BANNED,
+	}
+}
+
 func (g *GormError) Error() string {
 	return g.message
 }
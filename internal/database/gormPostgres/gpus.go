@@ -71,6 +71,7 @@ func (c *GormPostgresController) AddGpu(gpu *inputs.AddGpuInput) (*models.GpuCha
 	product := DbProduct{
 		Name:           gpu.Name,
 		Price:          gpu.Price,
+		Currency:       gpu.Price.Currency,
 		Selled:         0,
 		Stock:          gpu.Stock,
 		CharsTableName: database.GpuCharsTable,
@@ -0,0 +1,328 @@
+package gormpostgres
+
+import (
+	gormerrors "github.com/PC-Core/pc-core-backend/internal/database/gormPostgres/gormErrors"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (c *GormPostgresController) GetWarehouses() ([]models.Warehouse, errors.PCCError) {
+	var dbwarehouses []DbWarehouse
+
+	if err := c.db.Order("id").Find(&dbwarehouses).Error; err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	warehouses := make([]models.Warehouse, 0, len(dbwarehouses))
+
+	for i := range dbwarehouses {
+		warehouses = append(warehouses, *dbwarehouses[i].IntoWarehouse())
+	}
+
+	return warehouses, nil
+}
+
+func (c *GormPostgresController) AddWarehouse(input *inputs.WarehouseInput) (*models.Warehouse, errors.PCCError) {
+	warehouse := DbWarehouse{
+		Name:    input.Name,
+		Kind:    input.Kind,
+		Address: input.Address,
+		Active:  input.Active,
+	}
+
+	if err := c.db.Create(&warehouse).Error; err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	return warehouse.IntoWarehouse(), nil
+}
+
+func (c *GormPostgresController) UpdateWarehouse(id int, input *inputs.WarehouseInput) (*models.Warehouse, errors.PCCError) {
+	res := c.db.Model(&DbWarehouse{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"name":    input.Name,
+			"kind":    input.Kind,
+			"address": input.Address,
+			"active":  input.Active,
+		})
+
+	if res.Error != nil {
+		return nil, gormerrors.GormErrorCast(res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return nil, gormerrors.GormErrorCast(gorm.ErrRecordNotFound)
+	}
+
+	var warehouse DbWarehouse
+
+	if err := c.db.Where("id = ?", id).First(&warehouse).Error; err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	return warehouse.IntoWarehouse(), nil
+}
+
+// recordMovements checks the referenced warehouses and products and appends the movements
+// to the ledger. The trigger from the migration 0024 applies them to the stock
+func recordMovements(tx *gorm.DB, movements []DbStockMovement) errors.PCCError {
+	warehouseIDs := make(map[int]bool)
+	productIDs := make(map[uint64]bool)
+
+	for _, m := range movements {
+		productIDs[m.ProductID] = true
+
+		for _, id := range []*int{m.FromWarehouseID, m.ToWarehouseID} {
+			if id != nil {
+				warehouseIDs[*id] = true
+			}
+		}
+	}
+
+	var count int64
+
+	if err := tx.Model(&DbWarehouse{}).Where("id IN ?", mapKeys(warehouseIDs)).Count(&count).Error; err != nil {
+		return gormerrors.GormErrorCast(err)
+	}
+
+	if int(count) != len(warehouseIDs) {
+		return gormerrors.GormErrorCast(gorm.ErrRecordNotFound)
+	}
+
+	if err := tx.Model(&DbProduct{}).Where("id IN ?", mapKeys(productIDs)).Count(&count).Error; err != nil {
+		return gormerrors.GormErrorCast(err)
+	}
+
+	if int(count) != len(productIDs) {
+		return gormerrors.GormErrorCast(gorm.ErrRecordNotFound)
+	}
+
+	if err := tx.Create(&movements).Error; err != nil {
+		return gormerrors.GormErrorCast(err)
+	}
+
+	return nil
+}
+
+func mapKeys[K comparable](m map[K]bool) []K {
+	keys := make([]K, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// RecordGoodsReceipt records the goods received from the supplier into the warehouse
+func (c *GormPostgresController) RecordGoodsReceipt(actorID int, input *inputs.GoodsReceiptInput) ([]models.StockMovement, errors.PCCError) {
+	movements := make([]DbStockMovement, 0, len(input.Items))
+
+	for _, item := range input.Items {
+		movements = append(movements, DbStockMovement{
+			Kind:          models.StockReceipt,
+			ProductID:     item.ProductID,
+			ToWarehouseID: &input.WarehouseID,
+			Quantity:      item.Quantity,
+			Supplier:      &input.Supplier,
+			Note:          input.Note,
+			CreatedBy:     &actorID,
+		})
+	}
+
+	err := c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		return recordMovements(tx, movements)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return DbStockMovementsIntoMovements(movements), nil
+}
+
+func (c *GormPostgresController) TransferStock(actorID int, input *inputs.StockTransferInput) (*models.StockMovement, errors.PCCError) {
+	movement := DbStockMovement{
+		Kind:            models.StockTransfer,
+		ProductID:       input.ProductID,
+		FromWarehouseID: &input.FromWarehouseID,
+		ToWarehouseID:   &input.ToWarehouseID,
+		Quantity:        input.Quantity,
+		Note:            input.Note,
+		CreatedBy:       &actorID,
+	}
+
+	// The transfer does not change the stock of the product, but the product row is still
+	// locked, so the transfer is not applied in the middle of a checkout
+	return c.recordMovement(&movement, 0)
+}
+
+func (c *GormPostgresController) WriteOffStock(actorID int, input *inputs.WriteOffInput) (*models.StockMovement, errors.PCCError) {
+	movement := DbStockMovement{
+		Kind:            models.StockWriteOff,
+		ProductID:       input.ProductID,
+		FromWarehouseID: &input.WarehouseID,
+		Quantity:        input.Quantity,
+		Note:            &input.Note,
+		CreatedBy:       &actorID,
+	}
+
+	return c.recordMovement(&movement, input.Quantity)
+}
+
+// recordMovement records the movement which takes the given quantity from the stock of
+// the product. The stock reserved by the checkouts can't be taken
+func (c *GormPostgresController) recordMovement(movement *DbStockMovement, taken uint64) (*models.StockMovement, errors.PCCError) {
+	movements := []DbStockMovement{*movement}
+
+	err := c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		if err := checkUnreservedStock(tx, movement.ProductID, taken); err != nil {
+			return err
+		}
+
+		return recordMovements(tx, movements)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return movements[0].IntoStockMovement(), nil
+}
+
+// checkUnreservedStock locks the product row, so the reservations can't change until the
+// transaction is finished, and checks that the stock left after taking the quantity still
+// covers the reservations
+func checkUnreservedStock(tx *gorm.DB, productID uint64, taken uint64) errors.PCCError {
+	var products []DbProduct
+
+	err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", productID).
+		Limit(1).
+		Find(&products).Error
+
+	if err != nil {
+		return gormerrors.GormErrorCast(err)
+	}
+
+	if len(products) == 0 {
+		return gormerrors.GormErrorCast(gorm.ErrRecordNotFound)
+	}
+
+	if available := products[0].Available(); taken > available {
+		return gormerrors.NewStockUnavailableError([]models.StockShortage{{
+			ProductID: productID,
+			Requested: uint(taken),
+			Available: available,
+		}})
+	}
+
+	return nil
+}
+
+// recordSale takes the sold quantity from the warehouses, the default one goes first,
+// then the ones with the most stock
+func recordSale(tx *gorm.DB, orderID int64, productID uint64, quantity uint64) errors.PCCError {
+	var stocks []DbWarehouseStock
+
+	err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "warehousestock"}}).
+		Joins("JOIN warehouses ON warehouses.id = warehousestock.warehouse_id").
+		Where("warehousestock.product_id = ? AND warehousestock.quantity > 0", productID).
+		Order("warehouses.is_default DESC, warehousestock.quantity DESC, warehousestock.warehouse_id").
+		Find(&stocks).Error
+
+	if err != nil {
+		return gormerrors.GormErrorCast(err)
+	}
+
+	movements := make([]DbStockMovement, 0, 1)
+	left := quantity
+
+	for i := range stocks {
+		if left == 0 {
+			break
+		}
+
+		taken := min(left, stocks[i].Quantity)
+		left -= taken
+
+		movements = append(movements, DbStockMovement{
+			Kind:            models.StockSale,
+			ProductID:       productID,
+			FromWarehouseID: &stocks[i].WarehouseID,
+			Quantity:        taken,
+			OrderID:         &orderID,
+		})
+	}
+
+	if left != 0 {
+		return gormerrors.NewStockUnavailableError([]models.StockShortage{{
+			ProductID: productID,
+			Requested: uint(quantity),
+			Available: quantity - left,
+		}})
+	}
+
+	if err := tx.Create(&movements).Error; err != nil {
+		return gormerrors.GormErrorCast(err)
+	}
+
+	return nil
+}
+
+func (c *GormPostgresController) GetStockMovements(productID *uint64, warehouseID *int, start uint64, count uint64) ([]models.StockMovement, uint64, errors.PCCError) {
+	var (
+		movements  []DbStockMovement
+		totalCount int64
+	)
+
+	query := c.db.Model(&DbStockMovement{})
+
+	if productID != nil {
+		query = query.Where("product_id = ?", *productID)
+	}
+
+	if warehouseID != nil {
+		query = query.Where("(from_warehouse_id = ? OR to_warehouse_id = ?)", *warehouseID, *warehouseID)
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	err := query.
+		Order("id DESC").
+		Limit(int(count)).
+		Offset(int(start)).
+		Find(&movements).Error
+
+	if err != nil {
+		return nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	return DbStockMovementsIntoMovements(movements), uint64(totalCount), nil
+}
+
+// GetProductAvailability returns the stock of the product at the active locations which have it
+func (c *GormPostgresController) GetProductAvailability(productID uint64) ([]models.LocationStock, errors.PCCError) {
+	availability := make([]models.LocationStock, 0)
+
+	err := c.db.Model(&DbWarehouseStock{}).
+		Select("warehouses.id AS warehouse_id, warehouses.name, warehouses.kind, warehouses.address, warehousestock.quantity").
+		Joins("JOIN warehouses ON warehouses.id = warehousestock.warehouse_id").
+		Where("warehousestock.product_id = ? AND warehousestock.quantity > 0 AND warehouses.active", productID).
+		Order("warehouses.kind DESC, warehouses.name").
+		Scan(&availability).Error
+
+	if err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	return availability, nil
+}
@@ -66,6 +66,7 @@ func (c *GormPostgresController) AddKeyBoard(keyboard *inputs.AddKeyBoardInput)
 	product := DbProduct{
 		Name: keyboard.Name,
 		Price: keyboard.Price,
+		Currency: keyboard.Price.Currency,
 		Selled: 0,
 		Stock: keyboard.Stock,
 		CharsTableName: database.KeyboardCharsTable,
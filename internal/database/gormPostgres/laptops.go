@@ -55,6 +55,7 @@ func (c *GormPostgresController) AddLaptop(laptop *inputs.AddLaptopInput) (*mode
 	product := DbProduct{
 		Name:           laptop.Name,
 		Price:          laptop.Price,
+		Currency:       laptop.Price.Currency,
 		Selled:         0,
 		Stock:          laptop.Stock,
 		CharsTableName: database.LaptopCharsTable,
@@ -0,0 +1,106 @@
+package gormpostgres
+
+import (
+	"time"
+
+	gormerrors "github.com/PC-Core/pc-core-backend/internal/database/gormPostgres/gormErrors"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"gorm.io/gorm"
+)
+
+// SetUserTotpSecret saves the pending secret. The MFA stays disabled
+// until the first code is confirmed
+func (c *GormPostgresController) SetUserTotpSecret(userID int, secret string) errors.PCCError {
+	err := c.db.Model(&DbUser{}).
+		Where("id = ? AND mfa_enabled = false", userID).
+		Update("totp_secret", secret).Error
+
+	if err != nil {
+		return gormerrors.GormErrorCast(err)
+	}
+
+	return nil
+}
+
+func (c *GormPostgresController) EnableUserMfa(userID int, recoveryHashes []string) errors.PCCError {
+	return c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		err := tx.Model(&DbUser{}).
+			Where("id = ?", userID).
+			Update("mfa_enabled", true).Error
+
+		if err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		return c.replaceRecoveryCodes(tx, userID, recoveryHashes)
+	})
+}
+
+func (c *GormPostgresController) DisableUserMfa(userID int) errors.PCCError {
+	return c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		err := tx.Model(&DbUser{}).
+			Where("id = ?", userID).
+			Updates(map[string]interface{}{"mfa_enabled": false, "totp_secret": nil}).Error
+
+		if err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		return c.replaceRecoveryCodes(tx, userID, nil)
+	})
+}
+
+func (c *GormPostgresController) ReplaceRecoveryCodes(userID int, recoveryHashes []string) errors.PCCError {
+	return c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		return c.replaceRecoveryCodes(tx, userID, recoveryHashes)
+	})
+}
+
+// UseRecoveryCode marks the code as used. Returns false if there is no unused code with such hash
+func (c *GormPostgresController) UseRecoveryCode(userID int, codeHash string) (bool, errors.PCCError) {
+	res := c.db.Model(&DbRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+
+	if res.Error != nil {
+		return false, gormerrors.GormErrorCast(res.Error)
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+// UseTotpStep saves the time step of the accepted TOTP code. Returns false if the code
+// of this or the later step was already accepted, so the code is replayed
+func (c *GormPostgresController) UseTotpStep(userID int, step int64) (bool, errors.PCCError) {
+	res := c.db.Model(&DbUser{}).
+		Where("id = ? AND (totp_last_step IS NULL OR totp_last_step < ?)", userID, step).
+		Update("totp_last_step", step)
+
+	if res.Error != nil {
+		return false, gormerrors.GormErrorCast(res.Error)
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+func (c *GormPostgresController) replaceRecoveryCodes(tx *gorm.DB, userID int, recoveryHashes []string) errors.PCCError {
+	if err := tx.Where("user_id = ?", userID).Delete(&DbRecoveryCode{}).Error; err != nil {
+		return gormerrors.GormErrorCast(err)
+	}
+
+	if len(recoveryHashes) == 0 {
+		return nil
+	}
+
+	codes := make([]DbRecoveryCode, 0, len(recoveryHashes))
+
+	for _, hash := range recoveryHashes {
+		codes = append(codes, DbRecoveryCode{UserID: userID, CodeHash: hash})
+	}
+
+	if err := tx.Create(&codes).Error; err != nil {
+		return gormerrors.GormErrorCast(err)
+	}
+
+	return nil
+}
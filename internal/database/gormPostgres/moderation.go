@@ -0,0 +1,235 @@
+package gormpostgres
+
+import (
+	"time"
+
+	gormerrors "github.com/PC-Core/pc-core-backend/internal/database/gormPostgres/gormErrors"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// ReportComment saves the report of the visible comment. The user can report the comment once
+func (c *GormPostgresController) ReportComment(userID int, commentID int64, input *inputs.ReportCommentInput) errors.PCCError {
+	return c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		var count int64
+
+		err := tx.Model(&DbComment{}).
+			Where("id = ? AND NOT is_deleted AND moderation_status = ?", commentID, models.ModerationVisible).
+			Count(&count).Error
+
+		if err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		if count == 0 {
+			return gormerrors.GormErrorCast(gorm.ErrRecordNotFound)
+		}
+
+		report := DbCommentReport{
+			CommentID:  commentID,
+			ReporterID: userID,
+			Reason:     input.Reason,
+			Note:       input.Note,
+		}
+
+		if err := tx.Create(&report).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		return nil
+	})
+}
+
+type moderationQueueRow struct {
+	ID               int64                   `gorm:"column:id"`
+	ProductID        int64                   `gorm:"column:product_id"`
+	UserID           int                     `gorm:"column:user_id"`
+	CommentText      string                  `gorm:"column:comment_text"`
+	Rating           *int16                  `gorm:"column:rating"`
+	ModerationStatus models.ModerationStatus `gorm:"column:moderation_status"`
+	CreatedAt        time.Time               `gorm:"column:created_at"`
+	Reports          uint64                  `gorm:"column:reports"`
+	Reasons          pq.StringArray          `gorm:"column:reasons;type:text[]"`
+	LastReportedAt   *time.Time              `gorm:"column:last_reported_at"`
+	FilterFlags      pq.StringArray          `gorm:"column:filter_flags;type:text[]"`
+}
+
+const moderationQueueFrom = `
+FROM comments c
+LEFT JOIN commentreports r ON r.comment_id = c.id AND r.resolved_at IS NULL
+WHERE NOT c.is_deleted AND (c.moderation_status = 'pending' OR (c.moderation_status = 'visible' AND r.id IS NOT NULL))`
+
+// GetModerationQueue returns the pending comments and the visible comments with the open reports.
+// The most reported go first
+func (c *GormPostgresController) GetModerationQueue(start uint64, count uint64) ([]models.ModerationQueueItem, uint64, errors.PCCError) {
+	var (
+		rows       []moderationQueueRow
+		totalCount int64
+	)
+
+	if err := c.db.Raw("SELECT COUNT(DISTINCT c.id)" + moderationQueueFrom).Scan(&totalCount).Error; err != nil {
+		return nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	err := c.db.Raw(`
+		SELECT
+			c.id, c.product_id, c.user_id, c.comment_text, c.rating, c.moderation_status, c.created_at, c.filter_flags,
+			COUNT(r.id) AS reports,
+			COALESCE(array_agg(DISTINCT r.reason) FILTER (WHERE r.id IS NOT NULL), '{}') AS reasons,
+			MAX(r.created_at) AS last_reported_at`+moderationQueueFrom+`
+		GROUP BY c.id
+		ORDER BY COUNT(r.id) DESC, c.created_at
+		LIMIT ? OFFSET ?`, count, start,
+	).Scan(&rows).Error
+
+	if err != nil {
+		return nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	authorIDs := make(map[int]bool, len(rows))
+
+	for _, row := range rows {
+		authorIDs[row.UserID] = true
+	}
+
+	var authors []DbUser
+
+	if err := c.db.Where("id IN ?", mapKeys(authorIDs)).Find(&authors).Error; err != nil {
+		return nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	byID := make(map[int]*models.User, len(authors))
+
+	for i := range authors {
+		byID[authors[i].ID] = authors[i].IntoUser()
+	}
+
+	items := make([]models.ModerationQueueItem, 0, len(rows))
+
+	for _, row := range rows {
+		reasons := make([]models.ReportReason, 0, len(row.Reasons))
+
+		for _, reason := range row.Reasons {
+			reasons = append(reasons, models.ReportReason(reason))
+		}
+
+		items = append(items, *models.NewModerationQueueItem(row.ID, row.ProductID, byID[row.UserID], row.CommentText, row.Rating, row.ModerationStatus, row.CreatedAt, row.Reports, reasons, row.LastReportedAt, row.FilterFlags))
+	}
+
+	return items, uint64(totalCount), nil
+}
+
+func moderationDetails(commentID int64, reason *string) map[string]any {
+	details := map[string]any{"comment_id": commentID}
+
+	if reason != nil {
+		details["reason"] = *reason
+	}
+
+	return details
+}
+
+// setCommentModeration changes the status of the comment, resolves its open reports
+// and records the action in the audit log
+func setCommentModeration(tx *gorm.DB, actorID int, commentID int64, status models.ModerationStatus, reason *string) (*DbComment, errors.PCCError) {
+	var comment DbComment
+
+	if err := tx.Where("id = ? AND NOT is_deleted", commentID).First(&comment).Error; err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	err := tx.Model(&DbComment{}).Where("id = ?", commentID).Update("moderation_status", status).Error
+
+	if err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	err = tx.Model(&DbCommentReport{}).
+		Where("comment_id = ? AND resolved_at IS NULL", commentID).
+		Updates(map[string]any{
+			"resolved_at": gorm.Expr("now()"),
+			"resolved_by": actorID,
+		}).Error
+
+	if err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	action := models.AuditCommentRestored
+
+	if status == models.ModerationHidden {
+		action = models.AuditCommentHidden
+	}
+
+	authorID := int(comment.UserID)
+
+	if err := addAuditRecord(tx, actorID, action, &authorID, moderationDetails(commentID, reason)); err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+func (c *GormPostgresController) HideComment(actorID int, commentID int64, reason *string) errors.PCCError {
+	return c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		_, err := setCommentModeration(tx, actorID, commentID, models.ModerationHidden, reason)
+		return err
+	})
+}
+
+// RestoreComment shows the hidden comment, approves the pending one or dismisses the reports
+func (c *GormPostgresController) RestoreComment(actorID int, commentID int64, reason *string) errors.PCCError {
+	return c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		_, err := setCommentModeration(tx, actorID, commentID, models.ModerationVisible, reason)
+		return err
+	})
+}
+
+// GetCommentAuthorID returns the ID of the author of the comment
+func (c *GormPostgresController) GetCommentAuthorID(commentID int64) (int, errors.PCCError) {
+	var comment DbComment
+
+	if err := c.db.Select("id", "user_id").Where("id = ?", commentID).First(&comment).Error; err != nil {
+		return 0, gormerrors.GormErrorCast(err)
+	}
+
+	return int(comment.UserID), nil
+}
+
+// BanCommentAuthor hides the comment and bans its author from commenting
+func (c *GormPostgresController) BanCommentAuthor(actorID int, commentID int64, reason *string) errors.PCCError {
+	return c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		comment, err := setCommentModeration(tx, actorID, commentID, models.ModerationHidden, reason)
+
+		if err != nil {
+			return err
+		}
+
+		authorID := int(comment.UserID)
+
+		if err := updateUser(tx, authorID, "comments_banned", true); err != nil {
+			return err
+		}
+
+		return addAuditRecord(tx, actorID, models.AuditUserCommentsBanned, &authorID, moderationDetails(commentID, reason))
+	})
+}
+
+func (c *GormPostgresController) UnbanCommentAuthor(actorID int, userID int, reason *string) errors.PCCError {
+	return c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		if err := updateUser(tx, userID, "comments_banned", false); err != nil {
+			return err
+		}
+
+		var details map[string]any
+
+		if reason != nil {
+			details = map[string]any{"reason": *reason}
+		}
+
+		return addAuditRecord(tx, actorID, models.AuditUserCommentsUnbanned, &userID, details)
+	})
+}
@@ -66,6 +66,7 @@ func (c *GormPostgresController) AddMouse(mouse *inputs.AddMouseInput) (*models.
 	product := DbProduct{
 		Name: mouse.Name,
 		Price: mouse.Price,
+		Currency: mouse.Price.Currency,
 		Selled: 0,
 		Stock: mouse.Stock,
 		CharsTableName: database.MouseCharsTable,
@@ -0,0 +1,360 @@
+package gormpostgres
+
+import (
+	"time"
+
+	gormerrors "github.com/PC-Core/pc-core-backend/internal/database/gormPostgres/gormErrors"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// releaseOrders moves the pending orders matching the condition to the status and deletes
+// their reservations. The trigger from the migration 0023 returns the reserved stock
+func releaseOrders(tx *gorm.DB, status models.OrderStatus, condition string, args ...any) (int64, errors.PCCError) {
+	values := append([]any{status, models.OrderPending}, args...)
+
+	res := tx.Exec(`
+		WITH released AS (
+			UPDATE orders SET status = ? WHERE status = ? AND `+condition+` RETURNING id
+		)
+		DELETE FROM stockreservations WHERE order_id IN (SELECT id FROM released)`,
+		values...,
+	)
+
+	if res.Error != nil {
+		return 0, gormerrors.GormErrorCast(res.Error)
+	}
+
+	return res.RowsAffected, nil
+}
+
+// StartCheckout creates the pending order from the priced cart and reserves its items until
+// the ttl passes. The previous checkout of the user is cancelled. The product rows are locked,
+// so the concurrent checkouts can't reserve the same items
+func (c *GormPostgresController) StartCheckout(userID int, cart *models.Cart, ttl time.Duration) (*models.Order, errors.PCCError) {
+	var order DbOrder
+
+	err := c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		if _, err := releaseOrders(tx, models.OrderCancelled, "user_id = ?", userID); err != nil {
+			return err
+		}
+
+		productIDs := make([]uint64, 0, len(cart.Items))
+
+		for _, item := range cart.Items {
+			productIDs = append(productIDs, item.Product.ID)
+		}
+
+		var products []DbProduct
+
+		// The rows are locked in the same order by every checkout, so they can't deadlock
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", productIDs).
+			Order("id").
+			Find(&products).Error
+
+		if err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		available := make(map[uint64]uint64, len(products))
+
+		for _, p := range products {
+			available[p.ID] = max(p.Stock, p.Reserved) - p.Reserved
+		}
+
+		shortages := make([]models.StockShortage, 0)
+		items := make([]DbOrderItem, 0, len(cart.Items))
+		reservations := make([]DbStockReservation, 0, len(cart.Items))
+
+		for _, item := range cart.Items {
+			if uint64(item.Quantity) > available[item.Product.ID] {
+				shortages = append(shortages, models.StockShortage{
+					ProductID: item.Product.ID,
+					Requested: item.Quantity,
+					Available: available[item.Product.ID],
+				})
+				continue
+			}
+
+			items = append(items, DbOrderItem{
+				ProductID: item.Product.ID,
+				Name:      item.Product.Name,
+				Quantity:  item.Quantity,
+				Price:     item.Product.Price,
+				Discount:  item.Discount,
+			})
+
+			reservations = append(reservations, DbStockReservation{ProductID: item.Product.ID, Quantity: item.Quantity})
+		}
+
+		if len(shortages) != 0 {
+			return gormerrors.NewStockUnavailableError(shortages)
+		}
+
+		order = DbOrder{
+			UserID:    userID,
+			Status:    models.OrderPending,
+			Subtotal:  cart.Subtotal,
+			Discount:  cart.Discount,
+			Total:     cart.Total,
+			Currency:  cart.Subtotal.Currency,
+			ExpiresAt: time.Now().Add(ttl),
+			Items:     items,
+		}
+
+		if err := tx.Create(&order).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		for i := range reservations {
+			reservations[i].OrderID = order.ID
+		}
+
+		if err := tx.Create(&reservations).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		promotions := make([]DbOrderPromotion, 0, len(cart.Promotions))
+
+		for _, p := range cart.Promotions {
+			if p.Applied {
+				promotions = append(promotions, DbOrderPromotion{OrderID: order.ID, PromotionID: p.ID, Discount: p.Discount})
+			}
+		}
+
+		if len(promotions) == 0 {
+			return nil
+		}
+
+		if err := tx.Create(&promotions).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return order.IntoOrder(), nil
+}
+
+// CompleteCheckout converts the reservations of the pending order into the sales taken from
+// the warehouses, redeems the applied promotions and removes the ordered items from the cart
+func (c *GormPostgresController) CompleteCheckout(userID int, orderID int64) (*models.Order, errors.PCCError) {
+	err := c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		var order DbOrder
+
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", orderID, userID).
+			First(&order).Error
+
+		if err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		// The expired order may be not released by the sweeper yet
+		if order.Status != models.OrderPending || !order.ExpiresAt.After(time.Now()) {
+			return gormerrors.NewOrderNotPendingError()
+		}
+
+		var reservations []DbStockReservation
+
+		if err := tx.Where("order_id = ?", orderID).Find(&reservations).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		if err := tx.Where("order_id = ?", orderID).Delete(&DbStockReservation{}).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		for _, r := range reservations {
+			if err := recordSale(tx, orderID, r.ProductID, uint64(r.Quantity)); err != nil {
+				return err
+			}
+
+			err := tx.Model(&DbProduct{}).
+				Where("id = ?", r.ProductID).
+				Update("selled", gorm.Expr("selled + ?", r.Quantity)).Error
+
+			if err != nil {
+				return gormerrors.GormErrorCast(err)
+			}
+		}
+
+		err = tx.Exec(`
+			INSERT INTO promotionredemptions (promotion_id, user_id, order_id)
+			SELECT promotion_id, ?, order_id FROM orderpromotions WHERE order_id = ?`,
+			userID, orderID,
+		).Error
+
+		if err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		err = tx.
+			Where("user_id = ? AND product_id IN (?)", userID, tx.Model(&DbOrderItem{}).Select("product_id").Where("order_id = ?", orderID)).
+			Delete(&DbCart{}).Error
+
+		if err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&DbCartPromotion{}).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		err = tx.Model(&order).
+			Updates(map[string]any{"status": models.OrderCompleted, "completed_at": gorm.Expr("now()")}).Error
+
+		if err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetUserOrder(userID, orderID)
+}
+
+// CancelCheckout cancels the pending order of the user and releases its reservations
+func (c *GormPostgresController) CancelCheckout(userID int, orderID int64) errors.PCCError {
+	released, err := releaseOrders(c.db, models.OrderCancelled, "id = ? AND user_id = ?", orderID, userID)
+
+	if err != nil {
+		return err
+	}
+
+	if released == 0 {
+		var order DbOrder
+
+		if err := c.db.Where("id = ? AND user_id = ?", orderID, userID).First(&order).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		if order.Status != models.OrderPending {
+			return gormerrors.NewOrderNotPendingError()
+		}
+	}
+
+	return nil
+}
+
+// ReleaseExpiredReservations expires the pending orders whose time has passed and returns
+// the amount of the released reservations
+func (c *GormPostgresController) ReleaseExpiredReservations() (int64, errors.PCCError) {
+	return releaseOrders(c.db, models.OrderExpired, "expires_at <= now()")
+}
+
+func (c *GormPostgresController) GetUserOrders(userID int, start uint64, count uint64) ([]models.Order, uint64, errors.PCCError) {
+	return c.loadOrders(c.db.Model(&DbOrder{}).Where("user_id = ?", userID), start, count)
+}
+
+// GetOrders returns the orders of all the users, the newest go first
+func (c *GormPostgresController) GetOrders(input *inputs.GetAdminOrdersInput) ([]models.Order, uint64, errors.PCCError) {
+	query := c.db.Model(&DbOrder{})
+
+	if input.UserID != nil {
+		query = query.Where("user_id = ?", *input.UserID)
+	}
+
+	if input.Status != nil {
+		query = query.Where("status = ?", *input.Status)
+	}
+
+	return c.loadOrders(query, (input.Page*input.Count)-input.Count, input.Count)
+}
+
+// loadOrders loads the page of the orders matching the query with their items
+func (c *GormPostgresController) loadOrders(query *gorm.DB, start uint64, count uint64) ([]models.Order, uint64, errors.PCCError) {
+	var (
+		dborders   []DbOrder
+		totalCount int64
+	)
+
+	if err := query.Session(&gorm.Session{}).Count(&totalCount).Error; err != nil {
+		return nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	err := query.
+		Preload("Items").
+		Order("id DESC").
+		Limit(int(count)).
+		Offset(int(start)).
+		Find(&dborders).Error
+
+	if err != nil {
+		return nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	orders := make([]models.Order, 0, len(dborders))
+
+	for i := range dborders {
+		orders = append(orders, *dborders[i].IntoOrder())
+	}
+
+	return orders, uint64(totalCount), nil
+}
+
+func (c *GormPostgresController) GetUserOrder(userID int, orderID int64) (*models.Order, errors.PCCError) {
+	var order DbOrder
+
+	err := c.db.
+		Preload("Items").
+		Where("id = ? AND user_id = ?", orderID, userID).
+		First(&order).Error
+
+	if err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	return order.IntoOrder(), nil
+}
+
+func (c *GormPostgresController) GetOrder(orderID int64) (*models.Order, errors.PCCError) {
+	var order DbOrder
+
+	if err := c.db.Preload("Items").Where("id = ?", orderID).First(&order).Error; err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	return order.IntoOrder(), nil
+}
+
+// CancelOrder cancels the pending order of any user on behalf of the staff and releases its
+// reservations. The cancellation is written to the audit log in the same transaction
+func (c *GormPostgresController) CancelOrder(actorID int, orderID int64) errors.PCCError {
+	return c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		var order DbOrder
+
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", orderID).
+			First(&order).Error
+
+		if err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		if order.Status != models.OrderPending {
+			return gormerrors.NewOrderNotPendingError()
+		}
+
+		if _, err := releaseOrders(tx, models.OrderCancelled, "id = ?", orderID); err != nil {
+			return err
+		}
+
+		return addAuditRecord(tx, actorID, models.AuditOrderCancelled, &order.UserID, map[string]any{"order_id": orderID})
+	})
+}
@@ -0,0 +1,237 @@
+package gormpostgres
+
+import (
+	"strconv"
+	"time"
+
+	gormerrors "github.com/PC-Core/pc-core-backend/internal/database/gormPostgres/gormErrors"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/pricing/prerrors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"gorm.io/gorm"
+)
+
+// setPriceChangeAuthor sets the author of the price changes made in the transaction.
+// The trigger from the migration 0025 writes it to the price history
+func setPriceChangeAuthor(tx *gorm.DB, actorID *int, scheduledChangeID *int64) errors.PCCError {
+	var actor, scheduled string
+
+	if actorID != nil {
+		actor = strconv.Itoa(*actorID)
+	}
+
+	if scheduledChangeID != nil {
+		scheduled = strconv.FormatInt(*scheduledChangeID, 10)
+	}
+
+	err := tx.Exec(
+		"SELECT set_config('pccore.price_changed_by', ?, true), set_config('pccore.price_scheduled_change', ?, true)",
+		actor, scheduled,
+	).Error
+
+	if err != nil {
+		return gormerrors.GormErrorCast(err)
+	}
+
+	return nil
+}
+
+// GetPriceHistory returns the prices of the product in the period, the oldest go first.
+// The zero bounds are not applied
+func (c *GormPostgresController) GetPriceHistory(productID uint64, from time.Time, to time.Time) ([]models.PriceHistoryEntry, errors.PCCError) {
+	var history []DbPriceHistory
+
+	query := c.db.Where("product_id = ?", productID)
+
+	if !from.IsZero() {
+		query = query.Where("changed_at >= ?", from)
+	}
+
+	if !to.IsZero() {
+		query = query.Where("changed_at < ?", to)
+	}
+
+	if err := query.Order("changed_at, id").Find(&history).Error; err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	return DbPriceHistoryIntoEntries(history), nil
+}
+
+// GetLowestPrice returns the lowest price of the product in its current currency during the period
+// before the latest price change, the current price is not counted. It is nil if the product
+// was never repriced
+func (c *GormPostgresController) GetLowestPrice(productID uint64, period time.Duration) (*models.Money, errors.PCCError) {
+	var lowest struct {
+		Price    *models.Money   `gorm:"column:price"`
+		Currency models.Currency `gorm:"column:currency"`
+	}
+
+	res := c.db.Model(&DbProduct{}).
+		Select("lowest_prior_price(id, currency, ? * interval '1 second') AS price, currency", int64(period.Seconds())).
+		Where("id = ?", productID).
+		Scan(&lowest)
+
+	if res.Error != nil {
+		return nil, gormerrors.GormErrorCast(res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return nil, gormerrors.GormErrorCast(gorm.ErrRecordNotFound)
+	}
+
+	if lowest.Price == nil {
+		return nil, nil
+	}
+
+	price := lowest.Price.In(lowest.Currency)
+
+	return &price, nil
+}
+
+func scheduledPriceChangesQuery(tx *gorm.DB) *gorm.DB {
+	return tx.Model(&DbScheduledPriceChange{}).
+		Select("scheduledpricechanges.*, products.currency").
+		Joins("JOIN products ON products.id = scheduledpricechanges.product_id")
+}
+
+func (c *GormPostgresController) GetScheduledPriceChanges(productID *uint64, status *models.ScheduledPriceChangeStatus, start uint64, count uint64) ([]models.ScheduledPriceChange, uint64, errors.PCCError) {
+	var (
+		changes    []DbScheduledPriceChange
+		totalCount int64
+	)
+
+	query := c.db.Model(&DbScheduledPriceChange{})
+
+	if productID != nil {
+		query = query.Where("scheduledpricechanges.product_id = ?", *productID)
+	}
+
+	if status != nil {
+		query = query.Where("scheduledpricechanges.status = ?", *status)
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	err := scheduledPriceChangesQuery(query).
+		Order("scheduledpricechanges.apply_at DESC, scheduledpricechanges.id DESC").
+		Limit(int(count)).
+		Offset(int(start)).
+		Find(&changes).Error
+
+	if err != nil {
+		return nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	return DbScheduledPriceChangesIntoChanges(changes), uint64(totalCount), nil
+}
+
+func (c *GormPostgresController) AddScheduledPriceChange(actorID int, input *inputs.ScheduledPriceChangeInput) (*models.ScheduledPriceChange, errors.PCCError) {
+	var change DbScheduledPriceChange
+
+	err := c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		var product DbProduct
+
+		if err := tx.Where("id = ?", input.ProductID).First(&product).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		if input.Price.Currency != product.Currency {
+			return prerrors.NewMoneyCurrencyMismatchError()
+		}
+
+		change = DbScheduledPriceChange{
+			ProductID: input.ProductID,
+			Price:     *input.Price,
+			ApplyAt:   input.ApplyAt,
+			Note:      input.Note,
+			CreatedBy: &actorID,
+		}
+
+		if err := tx.Create(&change).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		change.Currency = product.Currency
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return change.IntoScheduledPriceChange(), nil
+}
+
+// CancelScheduledPriceChange cancels the change which was not applied yet
+func (c *GormPostgresController) CancelScheduledPriceChange(id int64) errors.PCCError {
+	res := c.db.Model(&DbScheduledPriceChange{}).
+		Where("id = ? AND status = ?", id, models.ScheduledPriceChangePending).
+		Update("status", models.ScheduledPriceChangeCancelled)
+
+	if res.Error != nil {
+		return gormerrors.GormErrorCast(res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return gormerrors.GormErrorCast(gorm.ErrRecordNotFound)
+	}
+
+	return nil
+}
+
+// ApplyScheduledPriceChanges applies the due changes in the order of their time and
+// returns the amount of the applied changes. The changes locked by another scheduler are skipped
+func (c *GormPostgresController) ApplyScheduledPriceChanges() (int64, errors.PCCError) {
+	var applied int64
+
+	err := c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		var due []DbScheduledPriceChange
+
+		err := tx.Raw(`
+			SELECT * FROM scheduledpricechanges
+			WHERE status = ? AND apply_at <= now()
+			ORDER BY apply_at, id
+			FOR UPDATE SKIP LOCKED`, models.ScheduledPriceChangePending,
+		).Scan(&due).Error
+
+		if err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		for _, change := range due {
+			if err := setPriceChangeAuthor(tx, change.CreatedBy, &change.ID); err != nil {
+				return err
+			}
+
+			if err := tx.Model(&DbProduct{}).Where("id = ?", change.ProductID).Update("price", change.Price).Error; err != nil {
+				return gormerrors.GormErrorCast(err)
+			}
+
+			err := tx.Model(&DbScheduledPriceChange{}).
+				Where("id = ?", change.ID).
+				Updates(map[string]any{
+					"status":     models.ScheduledPriceChangeApplied,
+					"applied_at": gorm.Expr("now()"),
+				}).Error
+
+			if err != nil {
+				return gormerrors.GormErrorCast(err)
+			}
+		}
+
+		applied = int64(len(due))
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return applied, nil
+}
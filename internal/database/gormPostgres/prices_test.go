@@ -0,0 +1,66 @@
+package gormpostgres
+
+import (
+	"testing"
+	"time"
+)
+
+const testLowestPriceWindow = 30 * 24 * time.Hour
+
+func TestGetLowestPrice(t *testing.T) {
+	tx := newTestTx(t)
+	c := NewGormPostgresControllerFromDB(tx)
+
+	var productID uint64
+
+	err := tx.Raw(`
+		INSERT INTO Products (name, price, currency, chars_table_name, chars_id)
+		VALUES ('Lowest price test', 100, 'RUB', 'laptopchars', 0)
+		RETURNING id`,
+	).Scan(&productID).Error
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lowest, perr := c.GetLowestPrice(productID, testLowestPriceWindow)
+
+	if perr != nil {
+		t.Fatal(perr)
+	}
+
+	if lowest != nil {
+		t.Fatalf("the product which was never repriced has the lowest price %s", lowest)
+	}
+
+	// The days are counted from the initial price. The window of the latest change on the
+	// 61st day starts on the 31st day, when the price of 80 from the 10th day was in effect
+	history := []struct {
+		day   int
+		price string
+	}{
+		{10, "80"},
+		{50, "120"},
+		{60, "90"},
+		{61, "70"},
+	}
+
+	for _, h := range history {
+		err := tx.Exec(`
+			INSERT INTO PriceHistory (product_id, price, currency, changed_at)
+			VALUES (?, ?, 'RUB', now() + ? * interval '1 day')`, productID, h.price, h.day,
+		).Error
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if lowest, perr = c.GetLowestPrice(productID, testLowestPriceWindow); perr != nil {
+		t.Fatal(perr)
+	}
+
+	if lowest == nil || lowest.String() != "80.00" {
+		t.Fatalf("the lowest prior price is %v, expected 80.00", lowest)
+	}
+}
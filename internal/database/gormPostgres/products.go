@@ -6,23 +6,54 @@ import (
 	"github.com/PC-Core/pc-core-backend/internal/database"
 	gormerrors "github.com/PC-Core/pc-core-backend/internal/database/gormPostgres/gormErrors"
 	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/pricing/prerrors"
 	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"gorm.io/gorm"
 )
 
-func (c *GormPostgresController) GetProducts(start uint64, count uint64) ([]models.Product, uint64, errors.PCCError) {
+// productOrders are the orders of the sort modes. The ID keeps the order stable for the pagination
+var productOrders = map[models.ProductSort]string{
+	models.ProductSortID:         "products.id",
+	models.ProductSortRatingHigh: "(CASE WHEN productratings.rating_count > 0 THEN productratings.average END) DESC NULLS LAST, products.id",
+	models.ProductSortRatingLow:  "(CASE WHEN productratings.rating_count > 0 THEN productratings.average END) ASC NULLS LAST, products.id",
+	models.ProductSortMostRated:  "COALESCE(productratings.rating_count, 0) DESC, products.id",
+}
+
+// productsQuery selects the products joined with their ratings, the rated not below the minimal rating
+func (c *GormPostgresController) productsQuery(input *inputs.GetProductsInput) *gorm.DB {
+	query := c.db.Model(&DbProductWithMedias{}).
+		Joins("LEFT JOIN productratings ON productratings.product_id = products.id")
+
+	if input.MinRating != nil {
+		query = query.Where("productratings.rating_count > 0 AND productratings.average >= ?", *input.MinRating)
+	}
+
+	return query
+}
+
+func (c *GormPostgresController) GetProducts(start uint64, input *inputs.GetProductsInput) ([]models.Product, uint64, errors.PCCError) {
 	var (
 		dbproducts []DbProductWithMedias
 		totalCount int64
 	)
 
-	if err := c.db.Model(&DbProductWithMedias{}).Count(&totalCount).Error; err != nil {
+	sort := input.Sort
+
+	if _, ok := productOrders[sort]; !ok {
+		sort = models.ProductSortID
+	}
+
+	if err := c.productsQuery(input).Count(&totalCount).Error; err != nil {
 		return nil, 0, gormerrors.GormErrorCast(err)
 	}
 
-	err := c.db.
+	err := c.productsQuery(input).
+		Select("products.*").
 		Preload("Medias").
-		Order("id").
-		Limit(int(count)).
+		Preload("Rating").
+		Order(productOrders[sort]).
+		Limit(int(input.Count)).
 		Offset(int(start)).
 		Find(&dbproducts).Error
 
@@ -44,6 +75,7 @@ func (c *GormPostgresController) GetProductById(id uint64) (*models.Product, err
 
 	err := c.db.
 		Preload("Medias").
+		Preload("Rating").
 		Where("id = ?", id).
 		First(&dbproduct).
 		Error
@@ -55,6 +87,48 @@ func (c *GormPostgresController) GetProductById(id uint64) (*models.Product, err
 	return dbproduct.IntoProduct(), nil
 }
 
+// UpdateProduct changes the price of the product. The price history entry and the
+// notifications of the subscribers are written by the database triggers in the same transaction
+func (c *GormPostgresController) UpdateProduct(actorID int, id uint64, input *inputs.UpdateProductInput) (*models.Product, errors.PCCError) {
+	err := c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		var product DbProduct
+
+		if err := tx.Where("id = ?", id).First(&product).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		updates := make(map[string]any, 1)
+
+		if input.Price != nil {
+			if input.Price.Currency != product.Currency {
+				return prerrors.NewMoneyCurrencyMismatchError()
+			}
+
+			updates["price"] = *input.Price
+		}
+
+		if len(updates) == 0 {
+			return nil
+		}
+
+		if err := setPriceChangeAuthor(tx, &actorID, nil); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&product).Updates(updates).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetProductById(id)
+}
+
 func (c *GormPostgresController) GetProductCharsByProductID(productId uint64) (database.ProductChars, errors.PCCError) {
 	p, err := c.GetProductById(productId)
 
@@ -87,6 +161,7 @@ func (c *GormPostgresController) LoadProductsRangeAsCartItem(tempCart []models.T
 
 	err := c.db.
 		Preload("Medias").
+		Preload("Rating").
 		Where("id IN ?", productIDs).
 		Find(&products).Error
 
@@ -106,3 +181,41 @@ func (c *GormPostgresController) LoadProductsRangeAsCartItem(tempCart []models.T
 
 	return cartItems, nil
 }
+
+// LoadProductsRangeAsWishlistItem loads the products of the temporary wishlist keeping its order.
+// The missing products are skipped
+func (c *GormPostgresController) LoadProductsRangeAsWishlistItem(tempWishlist []models.TempWishlistItem) ([]models.WishlistItem, errors.PCCError) {
+	productIDs := make([]uint64, len(tempWishlist))
+
+	for i, item := range tempWishlist {
+		productIDs[i] = item.ProductID
+	}
+
+	var products []DbProductWithMedias
+
+	err := c.db.
+		Preload("Medias").
+		Preload("Rating").
+		Where("id IN ?", productIDs).
+		Find(&products).Error
+
+	if err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	byID := make(map[uint64]*DbProductWithMedias, len(products))
+
+	for i := range products {
+		byID[products[i].ID] = &products[i]
+	}
+
+	items := make([]models.WishlistItem, 0, len(products))
+
+	for _, item := range tempWishlist {
+		if p, ok := byID[item.ProductID]; ok {
+			items = append(items, *models.NewWishlistItem(*p.IntoProduct(), item.AddedAt))
+		}
+	}
+
+	return items, nil
+}
@@ -0,0 +1,141 @@
+package gormpostgres
+
+import (
+	"time"
+
+	gormerrors "github.com/PC-Core/pc-core-backend/internal/database/gormPostgres/gormErrors"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"gorm.io/gorm"
+)
+
+func (c *GormPostgresController) SetUserName(userID int, name string) errors.PCCError {
+	return updateUser(c.db, userID, "name", name)
+}
+
+func (c *GormPostgresController) SetUserPassword(userID int, passwordHash string) errors.PCCError {
+	return updateUser(c.db, userID, "passwordhash", passwordHash)
+}
+
+func (c *GormPostgresController) SetUserAvatar(userID int, avatarURL string) errors.PCCError {
+	return updateUser(c.db, userID, "avatar_url", avatarURL)
+}
+
+// CreateEmailVerification replaces the previous pending email of the user
+func (c *GormPostgresController) CreateEmailVerification(userID int, email string, tokenHash string, expiresAt time.Time) errors.PCCError {
+	return c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		return createEmailVerification(tx, userID, email, tokenHash, expiresAt)
+	})
+}
+
+// UpdateUserProfile changes the name and the password and saves the pending email at once.
+// The nil fields are kept. The email taken by another user is rejected before the
+// verification is sent
+func (c *GormPostgresController) UpdateUserProfile(userID int, name *string, passwordHash *string, email *string, tokenHash string, expiresAt time.Time) errors.PCCError {
+	return c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		if name != nil {
+			if err := updateUser(tx, userID, "name", *name); err != nil {
+				return err
+			}
+		}
+
+		if passwordHash != nil {
+			if err := updateUser(tx, userID, "passwordhash", *passwordHash); err != nil {
+				return err
+			}
+		}
+
+		if email == nil {
+			return nil
+		}
+
+		var taken int64
+
+		if err := tx.Model(&DbUser{}).Where("email = ? AND id <> ?", *email, userID).Count(&taken).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		if taken != 0 {
+			return gormerrors.NewUniqueFailError("email")
+		}
+
+		return createEmailVerification(tx, userID, *email, tokenHash, expiresAt)
+	})
+}
+
+func createEmailVerification(tx *gorm.DB, userID int, email string, tokenHash string, expiresAt time.Time) errors.PCCError {
+	if err := tx.Where("user_id = ?", userID).Delete(&DbEmailVerification{}).Error; err != nil {
+		return gormerrors.GormErrorCast(err)
+	}
+
+	verification := DbEmailVerification{
+		UserID:    userID,
+		Email:     email,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+	}
+
+	if err := tx.Create(&verification).Error; err != nil {
+		return gormerrors.GormErrorCast(err)
+	}
+
+	return nil
+}
+
+// GetPendingEmail returns nil if the user has no unexpired email verification
+func (c *GormPostgresController) GetPendingEmail(userID int) (*string, errors.PCCError) {
+	var verifications []DbEmailVerification
+
+	err := c.db.
+		Where("user_id = ? AND expires_at > now()", userID).
+		Limit(1).
+		Find(&verifications).Error
+
+	if err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	if len(verifications) == 0 {
+		return nil, nil
+	}
+
+	return &verifications[0].Email, nil
+}
+
+// ConfirmEmailVerification sets the pending email as the email of the user.
+// Returns false if there is no unexpired verification with such token
+func (c *GormPostgresController) ConfirmEmailVerification(tokenHash string) (bool, errors.PCCError) {
+	confirmed := false
+
+	err := c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		var verifications []DbEmailVerification
+
+		err := tx.
+			Where("token_hash = ? AND expires_at > now()", tokenHash).
+			Limit(1).
+			Find(&verifications).Error
+
+		if err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		if len(verifications) == 0 {
+			return nil
+		}
+
+		verification := verifications[0]
+
+		if err := updateUser(tx, verification.UserID, "email", verification.Email); err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", verification.UserID).Delete(&DbEmailVerification{}).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		confirmed = true
+
+		return nil
+	})
+
+	return confirmed, err
+}
@@ -0,0 +1,271 @@
+package gormpostgres
+
+import (
+	"strings"
+	"time"
+
+	"github.com/PC-Core/pc-core-backend/internal/database"
+	gormerrors "github.com/PC-Core/pc-core-backend/internal/database/gormPostgres/gormErrors"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// promotionsQuery selects the promotions with the amount of their redemptions.
+// used_by_user counts the redemptions of the user with userID
+func promotionsQuery(tx *gorm.DB, userID int) *gorm.DB {
+	return tx.Model(&DbPromotion{}).Select(
+		"promotions.*, "+
+			"(SELECT count(*) FROM promotionredemptions r WHERE r.promotion_id = promotions.id) AS used, "+
+			"(SELECT count(*) FROM promotionredemptions r WHERE r.promotion_id = promotions.id AND r.user_id = ?) AS used_by_user",
+		userID,
+	)
+}
+
+// intoPromotions converts the promotions resolving their categories into the chars tables
+func (c *GormPostgresController) intoPromotions(dbpromotions []DbPromotion) ([]models.Promotion, errors.PCCError) {
+	categoryIDs := make([]int64, 0)
+
+	for _, p := range dbpromotions {
+		categoryIDs = append(categoryIDs, p.CategoryIDs...)
+	}
+
+	tables := make(map[int64]string)
+
+	if len(categoryIDs) != 0 {
+		var categories []DbCategories
+
+		if err := c.db.Where("id IN ?", categoryIDs).Find(&categories).Error; err != nil {
+			return nil, gormerrors.GormErrorCast(err)
+		}
+
+		for _, category := range categories {
+			if table, ok := database.CategoryCharsTables[category.Slug]; ok {
+				tables[int64(category.ID)] = table
+			}
+		}
+	}
+
+	promotions := make([]models.Promotion, 0, len(dbpromotions))
+
+	for _, p := range dbpromotions {
+		categoryTables := make([]string, 0, len(p.CategoryIDs))
+
+		for _, id := range p.CategoryIDs {
+			if table, ok := tables[id]; ok {
+				categoryTables = append(categoryTables, table)
+			}
+		}
+
+		promotions = append(promotions, *p.IntoPromotion(categoryTables))
+	}
+
+	return promotions, nil
+}
+
+func (c *GormPostgresController) getPromotion(query *gorm.DB) (*models.Promotion, errors.PCCError) {
+	var dbpromotion DbPromotion
+
+	if err := query.First(&dbpromotion).Error; err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	promotions, err := c.intoPromotions([]DbPromotion{dbpromotion})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &promotions[0], nil
+}
+
+func (c *GormPostgresController) GetPromotions(start uint64, count uint64) ([]models.Promotion, uint64, errors.PCCError) {
+	var (
+		dbpromotions []DbPromotion
+		totalCount   int64
+	)
+
+	if err := c.db.Model(&DbPromotion{}).Count(&totalCount).Error; err != nil {
+		return nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	err := promotionsQuery(c.db, 0).
+		Order("promotions.id DESC").
+		Limit(int(count)).
+		Offset(int(start)).
+		Find(&dbpromotions).Error
+
+	if err != nil {
+		return nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	promotions, perr := c.intoPromotions(dbpromotions)
+
+	if perr != nil {
+		return nil, 0, perr
+	}
+
+	return promotions, uint64(totalCount), nil
+}
+
+func (c *GormPostgresController) GetPromotionByID(id int64) (*models.Promotion, errors.PCCError) {
+	return c.getPromotion(promotionsQuery(c.db, 0).Where("promotions.id = ?", id))
+}
+
+// GetPromotionByCode finds the promotion by the case-insensitive code. UsedByUser is
+// counted for the user with userID
+func (c *GormPostgresController) GetPromotionByCode(code string, userID int) (*models.Promotion, errors.PCCError) {
+	return c.getPromotion(promotionsQuery(c.db, userID).Where("promotions.code = ?", normalizePromoCode(code)))
+}
+
+func (c *GormPostgresController) AddPromotion(input *inputs.PromotionInput) (*models.Promotion, errors.PCCError) {
+	promotion := promotionFromInput(input)
+
+	if err := c.db.Create(&promotion).Error; err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	return c.GetPromotionByID(promotion.ID)
+}
+
+func (c *GormPostgresController) UpdatePromotion(id int64, input *inputs.PromotionInput) (*models.Promotion, errors.PCCError) {
+	promotion := promotionFromInput(input)
+
+	res := c.db.Model(&DbPromotion{}).
+		Where("id = ?", id).
+		Select("*").
+		Omit("id", "created_at").
+		Updates(&promotion)
+
+	if res.Error != nil {
+		return nil, gormerrors.GormErrorCast(res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return nil, gormerrors.GormErrorCast(gorm.ErrRecordNotFound)
+	}
+
+	return c.GetPromotionByID(id)
+}
+
+// DeletePromotion deletes the promotion which was never redeemed. The redeemed
+// promotions should be deactivated instead
+func (c *GormPostgresController) DeletePromotion(id int64) errors.PCCError {
+	res := c.db.Where("id = ?", id).Delete(&DbPromotion{})
+
+	if res.Error != nil {
+		return gormerrors.GormErrorCast(res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return gormerrors.GormErrorCast(gorm.ErrRecordNotFound)
+	}
+
+	return nil
+}
+
+// GetCartPromotions returns the promotions applied to the cart of the user in the order of application
+func (c *GormPostgresController) GetCartPromotions(userID int) ([]models.Promotion, errors.PCCError) {
+	var dbpromotions []DbPromotion
+
+	err := promotionsQuery(c.db, userID).
+		Joins("JOIN cartpromotions cp ON cp.promotion_id = promotions.id AND cp.user_id = ?", userID).
+		Order("promotions.priority, promotions.id").
+		Find(&dbpromotions).Error
+
+	if err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	return c.intoPromotions(dbpromotions)
+}
+
+func (c *GormPostgresController) AddCartPromotion(userID int, promotionID int64) errors.PCCError {
+	err := c.db.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&DbCartPromotion{UserID: userID, PromotionID: promotionID}).Error
+
+	if err != nil {
+		return gormerrors.GormErrorCast(err)
+	}
+
+	return nil
+}
+
+func (c *GormPostgresController) RemoveCartPromotion(userID int, code string) errors.PCCError {
+	res := c.db.
+		Where("user_id = ? AND promotion_id IN (SELECT id FROM promotions WHERE code = ?)", userID, normalizePromoCode(code)).
+		Delete(&DbCartPromotion{})
+
+	if res.Error != nil {
+		return gormerrors.GormErrorCast(res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return gormerrors.GormErrorCast(gorm.ErrRecordNotFound)
+	}
+
+	return nil
+}
+
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func promotionFromInput(input *inputs.PromotionInput) DbPromotion {
+	currency := models.DefaultCurrency
+
+	if input.Amount != nil {
+		currency = input.Amount.Currency
+	} else if input.MinCart != nil {
+		currency = input.MinCart.Currency
+	}
+
+	startsAt := time.Now()
+
+	if input.StartsAt != nil {
+		startsAt = *input.StartsAt
+	}
+
+	productIDs := make(pq.Int64Array, 0, len(input.ProductIDs))
+
+	for _, id := range input.ProductIDs {
+		productIDs = append(productIDs, int64(id))
+	}
+
+	categoryIDs := make(pq.Int64Array, 0, len(input.CategoryIDs))
+
+	for _, id := range input.CategoryIDs {
+		categoryIDs = append(categoryIDs, int64(id))
+	}
+
+	charsTables := pq.StringArray(input.CharsTables)
+
+	if charsTables == nil {
+		charsTables = pq.StringArray{}
+	}
+
+	return DbPromotion{
+		Code:         normalizePromoCode(input.Code),
+		Title:        input.Title,
+		Kind:         input.Kind,
+		Percent:      input.Percent,
+		Amount:       input.Amount,
+		Currency:     currency,
+		Scope:        input.Scope,
+		ProductIDs:   productIDs,
+		CategoryIDs:  categoryIDs,
+		CharsTables:  charsTables,
+		MinCart:      input.MinCart,
+		UsageLimit:   input.UsageLimit,
+		PerUserLimit: input.PerUserLimit,
+		StartsAt:     startsAt,
+		EndsAt:       input.EndsAt,
+		Stackable:    input.Stackable,
+		Priority:     input.Priority,
+		Active:       input.Active,
+	}
+}
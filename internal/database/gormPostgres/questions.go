@@ -0,0 +1,377 @@
+package gormpostgres
+
+import (
+	"time"
+
+	"github.com/PC-Core/pc-core-backend/internal/contentfilter"
+	gormerrors "github.com/PC-Core/pc-core-backend/internal/database/gormPostgres/gormErrors"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// questionAnswersOrder puts the accepted answer first, the most upvoted go next
+const questionAnswersOrder = "questionanswers.id = productquestions.accepted_answer_id DESC NULLS LAST, questionanswers.upvotes DESC, " +
+	"questionanswers.staff DESC, questionanswers.verified_purchase DESC, questionanswers.created_at, questionanswers.id"
+
+// filterQuestionText runs the content filter on the question or the answer. The questions
+// are not pre-moderated, so the held text is rejected as well
+func (c *GormPostgresController) filterQuestionText(userID int64, productID int64, text string) (string, errors.PCCError) {
+	filtered, perr := c.filterComment(userID, productID, text, nil)
+
+	if perr != nil {
+		return "", perr
+	}
+
+	if filtered.Verdict == contentfilter.Hold {
+		return "", filtered.Rejection()
+	}
+
+	return filtered.Text, nil
+}
+
+// loadAcceptedAnswers loads the accepted answers of all the questions in one query
+func (c *GormPostgresController) loadAcceptedAnswers(questions []DbProductQuestion) (map[int64]*models.QuestionAnswer, errors.PCCError) {
+	ids := make([]int64, 0, len(questions))
+
+	for _, question := range questions {
+		if question.AcceptedAnswerID != nil {
+			ids = append(ids, *question.AcceptedAnswerID)
+		}
+	}
+
+	result := make(map[int64]*models.QuestionAnswer, len(ids))
+
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	var answers []DbQuestionAnswer
+
+	if err := c.db.Preload("User").Where("id IN ? AND NOT is_deleted", ids).Find(&answers).Error; err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	for i := range answers {
+		result[answers[i].QuestionID] = answers[i].IntoQuestionAnswer(true, false)
+	}
+
+	return result, nil
+}
+
+// productQuestionsQuery selects the questions of the product which are not deleted
+func (c *GormPostgresController) productQuestionsQuery(productID int64, unanswered bool) *gorm.DB {
+	query := c.db.Model(&DbProductQuestion{}).Where("product_id = ? AND NOT is_deleted", productID)
+
+	if unanswered {
+		query = query.Where("answers_count = 0")
+	}
+
+	return query
+}
+
+// GetProductQuestions returns the page of the questions about the product with their accepted
+// answers, the newest go first. The reviews are not included
+func (c *GormPostgresController) GetProductQuestions(productID int64, unanswered bool, start uint64, count uint64) ([]models.Question, uint64, errors.PCCError) {
+	var (
+		questions  []DbProductQuestion
+		totalCount int64
+	)
+
+	if err := c.productQuestionsQuery(productID, unanswered).Count(&totalCount).Error; err != nil {
+		return nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	err := c.productQuestionsQuery(productID, unanswered).
+		Preload("User").
+		Order("created_at DESC, id DESC").
+		Limit(int(count)).
+		Offset(int(start)).
+		Find(&questions).Error
+
+	if err != nil {
+		return nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	accepted, perr := c.loadAcceptedAnswers(questions)
+
+	if perr != nil {
+		return nil, 0, perr
+	}
+
+	result := make([]models.Question, 0, len(questions))
+
+	for i := range questions {
+		result = append(result, *questions[i].IntoQuestion(accepted[questions[i].ID]))
+	}
+
+	return result, uint64(totalCount), nil
+}
+
+// GetQuestionAnswers returns the question and the page of its answers. The accepted answer
+// goes first, the others are ordered by the upvotes
+func (c *GormPostgresController) GetQuestionAnswers(questionID int64, userID *int64, start uint64, count uint64) (*models.Question, []models.QuestionAnswer, uint64, errors.PCCError) {
+	var (
+		question   DbProductQuestion
+		answers    []DbQuestionAnswer
+		totalCount int64
+	)
+
+	if err := c.db.Preload("User").Where("id = ? AND NOT is_deleted", questionID).First(&question).Error; err != nil {
+		return nil, nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	accepted, perr := c.loadAcceptedAnswers([]DbProductQuestion{question})
+
+	if perr != nil {
+		return nil, nil, 0, perr
+	}
+
+	err := c.db.Model(&DbQuestionAnswer{}).
+		Where("question_id = ? AND NOT is_deleted", questionID).
+		Count(&totalCount).Error
+
+	if err != nil {
+		return nil, nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	err = c.db.Preload("User").
+		Joins("JOIN productquestions ON productquestions.id = questionanswers.question_id").
+		Where("questionanswers.question_id = ? AND NOT questionanswers.is_deleted", questionID).
+		Order(questionAnswersOrder).
+		Limit(int(count)).
+		Offset(int(start)).
+		Find(&answers).Error
+
+	if err != nil {
+		return nil, nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	upvoted := make(map[int64]bool)
+
+	if userID != nil && len(answers) != 0 {
+		ids := make([]int64, 0, len(answers))
+
+		for _, answer := range answers {
+			ids = append(ids, answer.ID)
+		}
+
+		var upvotes []DbAnswerUpvote
+
+		if err := c.db.Where("answer_id IN ? AND user_id = ?", ids, *userID).Find(&upvotes).Error; err != nil {
+			return nil, nil, 0, gormerrors.GormErrorCast(err)
+		}
+
+		for _, upvote := range upvotes {
+			upvoted[upvote.AnswerID] = true
+		}
+	}
+
+	result := make([]models.QuestionAnswer, 0, len(answers))
+
+	for i := range answers {
+		isAccepted := question.AcceptedAnswerID != nil && *question.AcceptedAnswerID == answers[i].ID
+		result = append(result, *answers[i].IntoQuestionAnswer(isAccepted, upvoted[answers[i].ID]))
+	}
+
+	return question.IntoQuestion(accepted[question.ID]), result, uint64(totalCount), nil
+}
+
+// AddQuestion saves the question about the product. The users banned from commenting can't ask
+func (c *GormPostgresController) AddQuestion(input *inputs.AddQuestionInput, userID int64, productID int64) (int64, errors.PCCError) {
+	if _, perr := checkCommentAuthor(c.db, userID); perr != nil {
+		return -1, perr
+	}
+
+	text, perr := c.filterQuestionText(userID, productID, input.Text)
+
+	if perr != nil {
+		return -1, perr
+	}
+
+	channel := input.NotifyBy
+
+	if channel == "" {
+		channel = models.NotificationInApp
+	}
+
+	question := DbProductQuestion{
+		UserID:        int(userID),
+		ProductID:     productID,
+		QuestionText:  text,
+		NotifyChannel: channel,
+		CreatedAt:     time.Now(),
+	}
+
+	if err := c.db.Create(&question).Error; err != nil {
+		return -1, gormerrors.GormErrorCast(err)
+	}
+
+	return question.ID, nil
+}
+
+// AddQuestionAnswer saves the answer on the question. The asker is notified by the trigger
+func (c *GormPostgresController) AddQuestionAnswer(input *inputs.AddQuestionAnswerInput, questionID int64, userID int64) (int64, errors.PCCError) {
+	if _, perr := checkCommentAuthor(c.db, userID); perr != nil {
+		return -1, perr
+	}
+
+	var question DbProductQuestion
+
+	if err := c.db.Where("id = ? AND NOT is_deleted", questionID).First(&question).Error; err != nil {
+		return -1, gormerrors.GormErrorCast(err)
+	}
+
+	text, perr := c.filterQuestionText(userID, question.ProductID, input.Text)
+
+	if perr != nil {
+		return -1, perr
+	}
+
+	answer := DbQuestionAnswer{
+		QuestionID: questionID,
+		UserID:     int(userID),
+		AnswerText: text,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := c.db.Create(&answer).Error; err != nil {
+		return -1, gormerrors.GormErrorCast(err)
+	}
+
+	return answer.ID, nil
+}
+
+// SetAcceptedAnswer marks the answer as accepted or removes the mark if answerID is nil.
+// Only the asker can do it unless moderator is set
+func (c *GormPostgresController) SetAcceptedAnswer(questionID int64, answerID *int64, userID int64, moderator bool) errors.PCCError {
+	return c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		var question DbProductQuestion
+
+		query := tx.Where("id = ? AND NOT is_deleted", questionID)
+
+		if !moderator {
+			query = query.Where("user_id = ?", userID)
+		}
+
+		if err := query.First(&question).Error; err != nil {
+			return gormerrors.GormErrorCastUserOwn(err)
+		}
+
+		if answerID != nil {
+			var answer DbQuestionAnswer
+
+			err := tx.Where("id = ? AND question_id = ? AND NOT is_deleted", *answerID, questionID).First(&answer).Error
+
+			if err != nil {
+				return gormerrors.GormErrorCast(err)
+			}
+		}
+
+		if err := tx.Model(&question).Update("accepted_answer_id", answerID).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		return nil
+	})
+}
+
+// UpvoteAnswer adds the upvote of the user or removes it if the answer is already upvoted.
+// It returns the answer with the updated upvotes
+func (c *GormPostgresController) UpvoteAnswer(answerID int64, userID int64) (*models.QuestionAnswer, errors.PCCError) {
+	var (
+		answer  DbQuestionAnswer
+		upvoted bool
+	)
+
+	perr := c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		if err := tx.Where("id = ? AND NOT is_deleted", answerID).First(&answer).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		// The concurrent toggles of the same user don't fail on the primary key, the one which
+		// didn't insert the upvote removes it
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&DbAnswerUpvote{AnswerID: answerID, UserID: int(userID), CreatedAt: time.Now()})
+
+		if res.Error != nil {
+			return gormerrors.GormErrorCast(res.Error)
+		}
+
+		upvoted = res.RowsAffected != 0
+
+		if !upvoted {
+			err := tx.Where("answer_id = ? AND user_id = ?", answerID, userID).Delete(&DbAnswerUpvote{}).Error
+
+			if err != nil {
+				return gormerrors.GormErrorCast(err)
+			}
+		}
+
+		// The upvotes are updated by the trigger
+		if err := tx.Preload("User").First(&answer, answerID).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		return nil
+	})
+
+	if perr != nil {
+		return nil, perr
+	}
+
+	var question DbProductQuestion
+
+	if err := c.db.Select("id, accepted_answer_id").First(&question, answer.QuestionID).Error; err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	accepted := question.AcceptedAnswerID != nil && *question.AcceptedAnswerID == answerID
+
+	return answer.IntoQuestionAnswer(accepted, upvoted), nil
+}
+
+// DeleteQuestion deletes the question of the user or any question if moderator is set
+func (c *GormPostgresController) DeleteQuestion(questionID int64, userID int64, moderator bool) errors.PCCError {
+	query := c.db.Model(&DbProductQuestion{}).Where("id = ? AND NOT is_deleted", questionID)
+
+	if !moderator {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	res := query.Updates(map[string]any{"is_deleted": true, "accepted_answer_id": nil})
+
+	if res.Error != nil {
+		return gormerrors.GormErrorCast(res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return gormerrors.GormErrorCastUserOwn(gorm.ErrRecordNotFound)
+	}
+
+	return nil
+}
+
+// DeleteQuestionAnswer deletes the answer of the user or any answer if moderator is set.
+// The deleted answer stops being accepted
+func (c *GormPostgresController) DeleteQuestionAnswer(answerID int64, userID int64, moderator bool) errors.PCCError {
+	query := c.db.Model(&DbQuestionAnswer{}).Where("id = ? AND NOT is_deleted", answerID)
+
+	if !moderator {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	res := query.Update("is_deleted", true)
+
+	if res.Error != nil {
+		return gormerrors.GormErrorCast(res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return gormerrors.GormErrorCastUserOwn(gorm.ErrRecordNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package gormpostgres
+
+import (
+	"time"
+
+	gormerrors "github.com/PC-Core/pc-core-backend/internal/database/gormPostgres/gormErrors"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetReactionTypes returns the reactions ordered by the position. The inactive ones are
+// returned only if all is set
+func (c *GormPostgresController) GetReactionTypes(all bool) ([]models.ReactionTypeInfo, errors.PCCError) {
+	var types []DbReactionType
+
+	query := c.db.Order("position, name")
+
+	if !all {
+		query = query.Where("active")
+	}
+
+	if err := query.Find(&types).Error; err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	result := make([]models.ReactionTypeInfo, 0, len(types))
+
+	for i := range types {
+		result = append(result, *types[i].IntoReactionTypeInfo())
+	}
+
+	return result, nil
+}
+
+// SetReactionType adds the reaction or changes the existing one. The reactions are never
+// deleted, the deactivated ones stay on the comments
+func (c *GormPostgresController) SetReactionType(name models.ReactionType, input *inputs.SetReactionTypeInput) (*models.ReactionTypeInfo, errors.PCCError) {
+	reactionType := DbReactionType{
+		Name:     name,
+		Emoji:    input.Emoji,
+		Position: input.Position,
+		Active:   input.Active,
+	}
+
+	err := c.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"emoji", "position", "active"}),
+	}).Create(&reactionType).Error
+
+	if err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	return reactionType.IntoReactionTypeInfo(), nil
+}
+
+type reactionRow struct {
+	UserID    int                 `gorm:"column:user_id"`
+	UserName  string              `gorm:"column:name"`
+	AvatarURL *string             `gorm:"column:avatar_url"`
+	Type      models.ReactionType `gorm:"column:ty"`
+	AddedAt   time.Time           `gorm:"column:added_at"`
+}
+
+// reactionsQuery selects the reactions of the comment of the type if it is set
+func (c *GormPostgresController) reactionsQuery(commentID int64, ty *models.ReactionType) *gorm.DB {
+	query := c.db.Model(&DbCommentReaction{}).Where("commentreactions.comment_id = ?", commentID)
+
+	if ty != nil {
+		query = query.Where("commentreactions.ty = ?", *ty)
+	}
+
+	return query
+}
+
+// GetReactions returns the page of the reactions of the visible comment, the latest go first
+func (c *GormPostgresController) GetReactions(commentID int64, ty *models.ReactionType, start uint64, count uint64) ([]models.Reaction, uint64, errors.PCCError) {
+	var (
+		comment    DbComment
+		rows       []reactionRow
+		totalCount int64
+	)
+
+	err := c.db.Select("id").Where("moderation_status = ?", models.ModerationVisible).First(&comment, commentID).Error
+
+	if err != nil {
+		return nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	if err := c.reactionsQuery(commentID, ty).Count(&totalCount).Error; err != nil {
+		return nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	err = c.reactionsQuery(commentID, ty).
+		Select("commentreactions.user_id, users.name, users.avatar_url, commentreactions.ty, commentreactions.added_at").
+		Joins("JOIN users ON users.id = commentreactions.user_id").
+		Order("commentreactions.added_at DESC, commentreactions.user_id").
+		Limit(int(count)).
+		Offset(int(start)).
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	result := make([]models.Reaction, 0, len(rows))
+
+	for _, row := range rows {
+		result = append(result, *models.NewReaction(row.UserID, row.UserName, row.AvatarURL, row.Type, row.AddedAt))
+	}
+
+	return result, uint64(totalCount), nil
+}
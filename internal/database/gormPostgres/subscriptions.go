@@ -0,0 +1,262 @@
+package gormpostgres
+
+import (
+	"time"
+
+	gormerrors "github.com/PC-Core/pc-core-backend/internal/database/gormPostgres/gormErrors"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/pricing/prerrors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/PC-Core/pc-core-backend/pkg/models/inputs"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func subscriptionsQuery(tx *gorm.DB) *gorm.DB {
+	return tx.Model(&DbProductSubscription{}).
+		Select("productsubscriptions.*, products.currency").
+		Joins("JOIN products ON products.id = productsubscriptions.product_id")
+}
+
+// AddProductSubscription subscribes the user to the product. Subscribing to the same kind
+// again replaces the threshold and the channel but keeps the unsubscribe token
+func (c *GormPostgresController) AddProductSubscription(userID int, productID uint64, input *inputs.SubscribeInput, token string) (*models.ProductSubscription, errors.PCCError) {
+	var subscription DbProductSubscription
+
+	err := c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		var product DbProduct
+
+		if err := tx.Where("id = ?", productID).First(&product).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		var threshold *models.Money
+
+		if input.Kind == models.SubscriptionPriceDrop {
+			if input.PriceThreshold.Currency != product.Currency {
+				return prerrors.NewMoneyCurrencyMismatchError()
+			}
+
+			threshold = input.PriceThreshold
+		}
+
+		var webhookURL *string
+
+		if input.Channel == models.NotificationWebhook {
+			webhookURL = input.WebhookURL
+		}
+
+		err := tx.
+			Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "product_id"}, {Name: "kind"}},
+				DoUpdates: clause.AssignmentColumns([]string{"price_threshold", "channel", "webhook_url"}),
+			}).
+			Create(&DbProductSubscription{
+				UserID:           userID,
+				ProductID:        productID,
+				Kind:             input.Kind,
+				PriceThreshold:   threshold,
+				Channel:          input.Channel,
+				WebhookURL:       webhookURL,
+				UnsubscribeToken: token,
+			}).Error
+
+		if err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		err = subscriptionsQuery(tx).
+			Where("productsubscriptions.user_id = ? AND productsubscriptions.product_id = ? AND productsubscriptions.kind = ?", userID, productID, input.Kind).
+			First(&subscription).Error
+
+		if err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription.IntoProductSubscription(), nil
+}
+
+func (c *GormPostgresController) GetUserSubscriptions(userID int) ([]models.ProductSubscription, errors.PCCError) {
+	var subscriptions []DbProductSubscription
+
+	err := subscriptionsQuery(c.db).
+		Where("productsubscriptions.user_id = ?", userID).
+		Order("productsubscriptions.created_at DESC").
+		Find(&subscriptions).Error
+
+	if err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	result := make([]models.ProductSubscription, 0, len(subscriptions))
+
+	for i := range subscriptions {
+		result = append(result, *subscriptions[i].IntoProductSubscription())
+	}
+
+	return result, nil
+}
+
+// deleteSubscriptions deletes the subscriptions with their undelivered notifications.
+// The delivered in-app notifications are kept in the history of the user
+func deleteSubscriptions(tx *gorm.DB, query string, args ...any) errors.PCCError {
+	var ids []int64
+
+	if err := tx.Model(&DbProductSubscription{}).Where(query, args...).Pluck("id", &ids).Error; err != nil {
+		return gormerrors.GormErrorCast(err)
+	}
+
+	if len(ids) == 0 {
+		return gormerrors.GormErrorCast(gorm.ErrRecordNotFound)
+	}
+
+	err := tx.
+		Where("subscription_id IN ? AND status <> ?", ids, models.NotificationSent).
+		Delete(&DbNotification{}).Error
+
+	if err != nil {
+		return gormerrors.GormErrorCast(err)
+	}
+
+	if err := tx.Where("id IN ?", ids).Delete(&DbProductSubscription{}).Error; err != nil {
+		return gormerrors.GormErrorCast(err)
+	}
+
+	return nil
+}
+
+func (c *GormPostgresController) DeleteUserSubscription(userID int, subscriptionID int64) errors.PCCError {
+	return c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		return deleteSubscriptions(tx, "id = ? AND user_id = ?", subscriptionID, userID)
+	})
+}
+
+// DeleteSubscriptionByToken deletes the subscription by the token from the unsubscribe link
+func (c *GormPostgresController) DeleteSubscriptionByToken(token string) errors.PCCError {
+	return c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		return deleteSubscriptions(tx, "unsubscribe_token = ?", token)
+	})
+}
+
+// ClaimNotifications marks up to limit pending notifications as being sent and returns them with
+// the delivery data. The notifications which stay claimed longer than staleAfter are claimed again,
+// so the notifications of the crashed dispatcher are not lost. The claimed rows are skipped by the
+// other dispatchers
+func (c *GormPostgresController) ClaimNotifications(limit int, staleAfter time.Duration) ([]models.Notification, errors.PCCError) {
+	var notifications []DbNotification
+
+	err := c.db.Raw(`
+		WITH claimed AS (
+			UPDATE notifications SET status = ?, claimed_at = now(), attempts = notifications.attempts + 1
+			WHERE notifications.id IN (
+				SELECT id FROM notifications
+				WHERE status = ? OR (status = ? AND claimed_at < now() - make_interval(secs => ?))
+				ORDER BY id
+				LIMIT ?
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING notifications.*
+		)
+		SELECT claimed.*, users.email AS user_email, s.webhook_url, s.unsubscribe_token
+		FROM claimed
+		JOIN users ON users.id = claimed.user_id
+		LEFT JOIN productsubscriptions s ON s.id = claimed.subscription_id
+		ORDER BY claimed.id`,
+		models.NotificationSending, models.NotificationPending, models.NotificationSending, staleAfter.Seconds(), limit,
+	).Scan(&notifications).Error
+
+	if err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	return DbNotificationsIntoNotifications(notifications), nil
+}
+
+func (c *GormPostgresController) MarkNotificationSent(notificationID int64) errors.PCCError {
+	return c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		err := tx.Model(&DbNotification{}).
+			Where("id = ?", notificationID).
+			Updates(map[string]any{"status": models.NotificationSent, "sent_at": gorm.Expr("now()"), "last_error": nil}).Error
+
+		if err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		err = tx.Model(&DbProductSubscription{}).
+			Where("id = (?)", tx.Model(&DbNotification{}).Select("subscription_id").Where("id = ?", notificationID)).
+			Update("last_notified_at", gorm.Expr("now()")).Error
+
+		if err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		return nil
+	})
+}
+
+// MarkNotificationFailed returns the notification to the queue or, if it was attempted
+// maxAttempts times, marks it as failed
+func (c *GormPostgresController) MarkNotificationFailed(notificationID int64, reason string, maxAttempts int) errors.PCCError {
+	err := c.db.Model(&DbNotification{}).
+		Where("id = ?", notificationID).
+		Updates(map[string]any{
+			"status":     gorm.Expr("CASE WHEN attempts >= ? THEN ? ELSE ? END", maxAttempts, models.NotificationFailed, models.NotificationPending),
+			"last_error": reason,
+		}).Error
+
+	if err != nil {
+		return gormerrors.GormErrorCast(err)
+	}
+
+	return nil
+}
+
+// GetUserNotifications returns the delivered in-app notifications of the user, the newest go first
+func (c *GormPostgresController) GetUserNotifications(userID int, start uint64, count uint64) ([]models.Notification, uint64, errors.PCCError) {
+	var (
+		notifications []DbNotification
+		totalCount    int64
+	)
+
+	query := c.db.Model(&DbNotification{}).
+		Where("user_id = ? AND channel = ? AND status = ?", userID, models.NotificationInApp, models.NotificationSent)
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	err := query.
+		Order("id DESC").
+		Limit(int(count)).
+		Offset(int(start)).
+		Find(&notifications).Error
+
+	if err != nil {
+		return nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	return DbNotificationsIntoNotifications(notifications), uint64(totalCount), nil
+}
+
+func (c *GormPostgresController) MarkNotificationRead(userID int, notificationID int64) errors.PCCError {
+	res := c.db.Model(&DbNotification{}).
+		Where("id = ? AND user_id = ? AND channel = ?", notificationID, userID, models.NotificationInApp).
+		Update("read_at", gorm.Expr("COALESCE(read_at, now())"))
+
+	if res.Error != nil {
+		return gormerrors.GormErrorCast(res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return gormerrors.GormErrorCast(gorm.ErrRecordNotFound)
+	}
+
+	return nil
+}
@@ -32,6 +32,7 @@ func (c *GormPostgresController) LoginUser(login *inputs.LoginUserInput) (*model
 	var user DbUser
 
 	err := c.db.
+		Preload("Permissions").
 		Where("email = ? AND passwordhash = ?", login.Email, passwordHash).
 		First(&user).
 		Error
@@ -47,6 +48,7 @@ func (c *GormPostgresController) GetUserByID(id int) (*models.User, errors.PCCEr
 	var user DbUser
 
 	err := c.db.
+		Preload("Permissions").
 		Where("id = ?", id).
 		First(&user).
 		Error
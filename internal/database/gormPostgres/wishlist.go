@@ -0,0 +1,202 @@
+package gormpostgres
+
+import (
+	gormerrors "github.com/PC-Core/pc-core-backend/internal/database/gormPostgres/gormErrors"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetWishlist returns the wishlist of the user, the recently added go first
+func (c *GormPostgresController) GetWishlist(userID int) ([]models.WishlistItem, errors.PCCError) {
+	var wishlist []DbWishlist
+
+	err := c.db.
+		Where("user_id = ?", userID).
+		Preload("Product.Medias").
+		Preload("Product.Rating").
+		Order("added_at DESC").
+		Find(&wishlist).Error
+
+	if err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	return DbWishlistIntoItems(wishlist), nil
+}
+
+// AddToWishlist saves the product. Adding the saved product again keeps its original time
+func (c *GormPostgresController) AddToWishlist(userID int, productID uint64) errors.PCCError {
+	return c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		if err := tx.Where("id = ?", productID).First(&DbProduct{}).Error; err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		err := tx.
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&DbWishlist{UserID: userID, ProductID: productID}).Error
+
+		if err != nil {
+			return gormerrors.GormErrorCast(err)
+		}
+
+		return nil
+	})
+}
+
+func (c *GormPostgresController) RemoveFromWishlist(userID int, productID uint64) errors.PCCError {
+	return removeFromWishlist(c.db, userID, productID)
+}
+
+func removeFromWishlist(tx *gorm.DB, userID int, productID uint64) errors.PCCError {
+	res := tx.Where("user_id = ? AND product_id = ?", userID, productID).Delete(&DbWishlist{})
+
+	if res.Error != nil {
+		return gormerrors.GormErrorCast(res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return gormerrors.GormErrorCast(gorm.ErrRecordNotFound)
+	}
+
+	return nil
+}
+
+// MoveWishlistItemToCart removes the product from the wishlist and adds one item of it to the cart
+func (c *GormPostgresController) MoveWishlistItemToCart(userID int, productID uint64) (uint64, errors.PCCError) {
+	err := c.withTransaction(func(tx *gorm.DB) errors.PCCError {
+		if err := removeFromWishlist(tx, userID, productID); err != nil {
+			return err
+		}
+
+		return upsertCartItem(tx, productID, uint64(userID), 1, clause.Assignments(map[string]interface{}{
+			"quantity": gorm.Expr("cart.quantity + ?", 1),
+		}))
+	})
+
+	return productID, err
+}
+
+// MergeWishlist adds the products of the temporary wishlist which are not saved yet and
+// returns the amount of the added ones. The missing products are skipped
+func (c *GormPostgresController) MergeWishlist(userID int, items []models.TempWishlistItem) (int, errors.PCCError) {
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	productIDs := make([]uint64, 0, len(items))
+
+	for _, item := range items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	var existing []uint64
+
+	if err := c.db.Model(&DbProduct{}).Where("id IN ?", productIDs).Pluck("id", &existing).Error; err != nil {
+		return 0, gormerrors.GormErrorCast(err)
+	}
+
+	exists := make(map[uint64]bool, len(existing))
+
+	for _, id := range existing {
+		exists[id] = true
+	}
+
+	wishlist := make([]DbWishlist, 0, len(items))
+
+	for _, item := range items {
+		if exists[item.ProductID] {
+			wishlist = append(wishlist, DbWishlist{UserID: userID, ProductID: item.ProductID, AddedAt: item.AddedAt})
+		}
+	}
+
+	if len(wishlist) == 0 {
+		return 0, nil
+	}
+
+	res := c.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&wishlist)
+
+	if res.Error != nil {
+		return 0, gormerrors.GormErrorCast(res.Error)
+	}
+
+	return int(res.RowsAffected), nil
+}
+
+// SetWishlistShareToken saves the token of the public link replacing the previous one
+func (c *GormPostgresController) SetWishlistShareToken(userID int, token string) errors.PCCError {
+	err := c.db.
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"token": token, "created_at": gorm.Expr("now()")}),
+		}).
+		Create(&DbWishlistShare{UserID: userID, Token: token}).Error
+
+	if err != nil {
+		return gormerrors.GormErrorCast(err)
+	}
+
+	return nil
+}
+
+func (c *GormPostgresController) GetWishlistShareToken(userID int) (*string, errors.PCCError) {
+	var shares []DbWishlistShare
+
+	if err := c.db.Where("user_id = ?", userID).Limit(1).Find(&shares).Error; err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	if len(shares) == 0 {
+		return nil, nil
+	}
+
+	return &shares[0].Token, nil
+}
+
+func (c *GormPostgresController) DeleteWishlistShare(userID int) errors.PCCError {
+	if err := c.db.Where("user_id = ?", userID).Delete(&DbWishlistShare{}).Error; err != nil {
+		return gormerrors.GormErrorCast(err)
+	}
+
+	return nil
+}
+
+// GetSharedWishlist returns the wishlist by the token of the public link
+func (c *GormPostgresController) GetSharedWishlist(token string) ([]models.WishlistItem, errors.PCCError) {
+	var share DbWishlistShare
+
+	if err := c.db.Where("token = ?", token).First(&share).Error; err != nil {
+		return nil, gormerrors.GormErrorCast(err)
+	}
+
+	return c.GetWishlist(share.UserID)
+}
+
+// GetWishlistCounts returns the products ordered by the amount of the wishlists they are saved in.
+// The wishlists of the temporary users are not counted
+func (c *GormPostgresController) GetWishlistCounts(start uint64, count uint64) ([]models.WishlistProductCount, uint64, errors.PCCError) {
+	var (
+		counts     []models.WishlistProductCount
+		totalCount int64
+	)
+
+	if err := c.db.Model(&DbWishlist{}).Distinct("product_id").Count(&totalCount).Error; err != nil {
+		return nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	err := c.db.Model(&DbWishlist{}).
+		Select("wishlist.product_id, products.name, count(*) AS count").
+		Joins("JOIN products ON products.id = wishlist.product_id").
+		Group("wishlist.product_id, products.name").
+		Order("count DESC, wishlist.product_id").
+		Limit(int(count)).
+		Offset(int(start)).
+		Scan(&counts).Error
+
+	if err != nil {
+		return nil, 0, gormerrors.GormErrorCast(err)
+	}
+
+	return counts, uint64(totalCount), nil
+}
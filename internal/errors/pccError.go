@@ -28,6 +28,16 @@ const (
 	EK_COOKIE ErrorKind = "cookie"
 	// Error occired in minio
 	EK_MINIO ErrorKind = "minio"
+	// Error occured while working with the multi-factor authentication
+	EK_MFA ErrorKind = "mfa"
+	// Error occured while sending the email
+	EK_MAIL ErrorKind = "mail"
+	// Error occured while working with the money values
+	EK_MONEY ErrorKind = "money"
+	// Error occured while delivering the notification
+	EK_NOTIFICATIONS ErrorKind = "notifications"
+	// Error occured while checking the user content by the content filter
+	EK_CONTENT_FILTER ErrorKind = "content_filter"
 )
 
 const (
@@ -98,6 +108,76 @@ const (
 	EC_DB_CART_QUANTITY_ERROR
 	// Error code means you're trying to perform operations with others comment
 	EC_NOT_YOUR_COMMENT
+	// Error code means that the provided TOTP or recovery code is invalid
+	EC_MFA_INVALID_CODE
+	// Error code means that the user has not started the MFA enrollment
+	EC_MFA_NOT_ENROLLED
+	// Error code means that the MFA is already enabled for the user
+	EC_MFA_ALREADY_ENABLED
+	// Error code means that the route requires the user to have the MFA enabled
+	EC_MFA_REQUIRED
+	// Error code means that the role of the user does not grant the required permission
+	EC_PERMISSION_MISSING
+	// Error code means that the jwt token was issued before the tokens of the user were revoked
+	EC_JWT_TOKEN_REVOKED
+	// Error code means that the user account is disabled
+	EC_USER_DISABLED
+	// Error code means that the impersonation session tried to change the data
+	EC_IMPERSONATION_READ_ONLY
+	// Error code means that the admin tried to apply the action to his own account
+	EC_ADMIN_SELF_ACTION
+	// Error code means that the action is not available for the temporary users
+	EC_TEMPORARY_USER_FORBIDDEN
+	// Error code means that the provided current password is wrong
+	EC_WRONG_PASSWORD
+	// Error code means that the email was not sent
+	EC_MAIL_SEND_FAILED
+	// Error code means that the email verification token is invalid or expired
+	EC_EMAIL_VERIFICATION_INVALID
+	// Error code means that the uploaded avatar is not an image or is too large
+	EC_INVALID_AVATAR
+	// Error code means that the temporary user record has expired in redis
+	EC_REDIS_TEMP_USER_EXPIRED
+	// Error code means that the money amount is not a decimal with at most two digits after the point
+	// or the currency is not supported
+	EC_MONEY_INVALID
+	// Error code means that the money values of different currencies were combined
+	EC_MONEY_CURRENCY_MISMATCH
+	// Error code means that the money value is too large
+	EC_MONEY_OVERFLOW
+	// Error code means that the promotion can't be applied to the cart, details contain the reason
+	EC_PROMO_UNAVAILABLE
+	// Error code means that the promotion can't be combined with the promotions applied to the cart
+	EC_PROMO_NOT_STACKABLE
+	// Error code means that the notification was not delivered through its channel
+	EC_NOTIFICATION_DELIVERY_FAILED
+	// Error code means that the checkout was started with the empty cart
+	EC_CHECKOUT_EMPTY_CART
+	// Error code means that the available stock is less than the ordered quantity, details contain the products
+	EC_DB_STOCK_UNAVAILABLE
+	// Error code means that the order is not pending anymore, e.g. its reservations have expired
+	EC_DB_ORDER_NOT_PENDING
+	// Error code means that the user has already reviewed the product. The review should be edited instead
+	EC_DB_REVIEW_EXISTS
+	// Error code means that the rating was set on the answer. Only the root comments are the reviews
+	EC_DB_RATING_ON_ANSWER
+	// Error code means that the user is banned from commenting by the moderators
+	EC_DB_COMMENTS_BANNED
+	// Error code means that the content filter rejected the text
+	EC_CONTENT_REJECTED
+	// Error code means that the uploaded comment media is not a supported image or is too large
+	EC_INVALID_COMMENT_MEDIA
+	// Error code means that the media is not uploaded by the user or is attached to another comment
+	EC_DB_COMMENT_MEDIA_UNAVAILABLE
+	// Error code means that the review can't be edited anymore
+	EC_DB_REVIEW_EDIT_EXPIRED
+	// Error code means that the reaction type doesn't exist or is not active
+	EC_DB_REACTION_UNAVAILABLE
+	// Error code means that the MFA challenge was already used or had too many failed attempts
+	EC_MFA_CHALLENGE_SPENT
+	// Error code means that the cart has the items priced in a currency other than the currency of the cart,
+	// details contain the products
+	EC_CHECKOUT_CURRENCY_MISMATCH
 )
 
 // PCCError - minimal error interface used in the PC Core project
@@ -0,0 +1,17 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// RandomToken returns n random bytes encoded as hex
+func RandomToken(n int) (string, error) {
+	buf := make([]byte, n)
+
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
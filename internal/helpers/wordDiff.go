@@ -0,0 +1,75 @@
+package helpers
+
+import (
+	"strings"
+
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+)
+
+// wordDiffLimit bounds the size of the LCS table to 4 MB, about a thousand words in both
+// texts. The longer texts are shown as fully replaced
+const wordDiffLimit = 1_000_000
+
+// WordDiff finds the words removed from and added to the old text. The whitespace is not compared
+func WordDiff(old string, new string) []models.DiffChunk {
+	a, b := strings.Fields(old), strings.Fields(new)
+
+	if len(a)*len(b) > wordDiffLimit {
+		return appendDiff(appendDiff(nil, models.DiffDelete, a...), models.DiffInsert, b...)
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int32, len(a)+1)
+
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff []models.DiffChunk
+	i, j := 0, 0
+
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = appendDiff(diff, models.DiffEqual, a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = appendDiff(diff, models.DiffDelete, a[i])
+			i++
+		default:
+			diff = appendDiff(diff, models.DiffInsert, b[j])
+			j++
+		}
+	}
+
+	diff = appendDiff(diff, models.DiffDelete, a[i:]...)
+
+	return appendDiff(diff, models.DiffInsert, b[j:]...)
+}
+
+// appendDiff joins the words with the chunk before if it has the same operation
+func appendDiff(diff []models.DiffChunk, op models.DiffOp, words ...string) []models.DiffChunk {
+	if len(words) == 0 {
+		return diff
+	}
+
+	text := strings.Join(words, " ")
+
+	if last := len(diff) - 1; last >= 0 && diff[last].Op == op {
+		diff[last].Text += " " + text
+		return diff
+	}
+
+	return append(diff, models.DiffChunk{Op: op, Text: text})
+}
@@ -0,0 +1,40 @@
+package helpers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+)
+
+func TestWordDiff(t *testing.T) {
+	diff := WordDiff("the laptop is  very good", "the laptop is good and quiet")
+
+	expected := []models.DiffChunk{
+		{Op: models.DiffEqual, Text: "the laptop is"},
+		{Op: models.DiffDelete, Text: "very"},
+		{Op: models.DiffEqual, Text: "good"},
+		{Op: models.DiffInsert, Text: "and quiet"},
+	}
+
+	if !reflect.DeepEqual(diff, expected) {
+		t.Fatalf("WordDiff = %+v, expected %+v", diff, expected)
+	}
+
+	if diff := WordDiff("", ""); len(diff) != 0 {
+		t.Fatalf("the diff of the empty texts is %+v", diff)
+	}
+}
+
+// TestWordDiffLimit checks that the texts above the limit are shown as fully replaced
+func TestWordDiffLimit(t *testing.T) {
+	old := strings.Repeat("word ", 1500)
+	new := old + "more"
+
+	diff := WordDiff(old, new)
+
+	if len(diff) != 2 || diff[0].Op != models.DiffDelete || diff[1].Op != models.DiffInsert {
+		t.Fatalf("the long texts are diffed by words: %d chunks", len(diff))
+	}
+}
@@ -0,0 +1,21 @@
+package mailer
+
+import (
+	"log"
+
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+)
+
+// LogMailer writes the emails to the log instead of sending them.
+// It is used when the SMTP server is not configured
+type LogMailer struct{}
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (m *LogMailer) Send(to string, subject string, body string) errors.PCCError {
+	log.Printf("mail to %s: %s\n%s", to, subject, body)
+
+	return nil
+}
@@ -0,0 +1,9 @@
+package mailer
+
+import (
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+)
+
+type Mailer interface {
+	Send(to string, subject string, body string) errors.PCCError
+}
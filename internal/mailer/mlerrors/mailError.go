@@ -0,0 +1,35 @@
+package mlerrors
+
+import (
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+)
+
+const ML_SEND_MESSAGE = "Failed to send the email"
+
+type MailError struct {
+	Inner error
+	Code  errors.ErrorCode
+	Kind  errors.ErrorKind
+}
+
+func NewSendError(inner error) *MailError {
+	return &MailError{
+		inner, errors.EC_MAIL_SEND_FAILED, errors.EK_MAIL,
+	}
+}
+
+func (e *MailError) Error() string {
+	return ML_SEND_MESSAGE
+}
+
+func (e *MailError) GetErrorCode() errors.ErrorCode {
+	return e.Code
+}
+
+func (e *MailError) GetErrorKind() errors.ErrorKind {
+	return e.Kind
+}
+
+func (e *MailError) IntoPublic() *errors.PublicPCCError {
+	return errors.NewPublicPCCError(e.Code, e.Kind, nil, ML_SEND_MESSAGE)
+}
@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"fmt"
+	"net/smtp"
+	"strings"
+
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/mailer/mlerrors"
+)
+
+type SmtpMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewSmtpMailer(host string, port int, from string, username string, password string) *SmtpMailer {
+	var auth smtp.Auth
+
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SmtpMailer{
+		fmt.Sprintf("%s:%d", host, port), from, auth,
+	}
+}
+
+func (m *SmtpMailer) Send(to string, subject string, body string) errors.PCCError {
+	var msg strings.Builder
+
+	fmt.Fprintf(&msg, "From: %s\r\n", m.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n\r\n")
+	msg.WriteString(body)
+
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{to}, []byte(msg.String())); err != nil {
+		return mlerrors.NewSendError(err)
+	}
+
+	return nil
+}
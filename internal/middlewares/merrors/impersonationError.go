@@ -0,0 +1,48 @@
+package merrors
+
+import (
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+)
+
+const (
+	IE_SAFE_MESSAGE = "The impersonation session is read-only"
+)
+
+// ImpersonationReadOnlyError is returned when the admin viewing
+// as the user tries to change the data of the user
+type ImpersonationReadOnlyError struct {
+	Code         errors.ErrorCode
+	Kind         errors.ErrorKind
+	Impersonator int
+}
+
+func NewImpersonationReadOnlyError(impersonator int) *ImpersonationReadOnlyError {
+	return &ImpersonationReadOnlyError{
+		errors.EC_IMPERSONATION_READ_ONLY,
+		errors.EK_ROLES,
+		impersonator,
+	}
+}
+
+func (i *ImpersonationReadOnlyError) Error() string {
+	return IE_SAFE_MESSAGE
+}
+
+func (i *ImpersonationReadOnlyError) GetErrorCode() errors.ErrorCode {
+	return i.Code
+}
+
+func (i *ImpersonationReadOnlyError) GetErrorKind() errors.ErrorKind {
+	return i.Kind
+}
+
+func (i *ImpersonationReadOnlyError) IntoPublic() *errors.PublicPCCError {
+	return errors.NewPublicPCCError(
+		i.Code,
+		i.Kind,
+		map[string]int{
+			"impersonator": i.Impersonator,
+		},
+		IE_SAFE_MESSAGE,
+	)
+}
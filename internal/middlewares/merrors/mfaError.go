@@ -0,0 +1,49 @@
+package merrors
+
+import (
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+)
+
+const (
+	ME_SAFE_MESSAGE = "The MFA must be enabled to access this resource"
+)
+
+// MfaRequiredError is returned when the route requires the MFA
+// but the session was created without the second factor check
+type MfaRequiredError struct {
+	Code          errors.ErrorCode
+	Kind          errors.ErrorKind
+	PresentedRole models.UserRole
+}
+
+func NewMfaRequiredError(presented models.UserRole) *MfaRequiredError {
+	return &MfaRequiredError{
+		errors.EC_MFA_REQUIRED,
+		errors.EK_MFA,
+		presented,
+	}
+}
+
+func (m *MfaRequiredError) Error() string {
+	return ME_SAFE_MESSAGE
+}
+
+func (m *MfaRequiredError) GetErrorCode() errors.ErrorCode {
+	return m.Code
+}
+
+func (m *MfaRequiredError) GetErrorKind() errors.ErrorKind {
+	return m.Kind
+}
+
+func (m *MfaRequiredError) IntoPublic() *errors.PublicPCCError {
+	return errors.NewPublicPCCError(
+		m.Code,
+		m.Kind,
+		map[string]models.UserRole{
+			"presented": m.PresentedRole,
+		},
+		ME_SAFE_MESSAGE,
+	)
+}
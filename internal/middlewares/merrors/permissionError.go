@@ -0,0 +1,53 @@
+package merrors
+
+import (
+	"fmt"
+
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+)
+
+const (
+	PE_ERROR_FORMAT = "Permission error with code: %d"
+	PE_SAFE_MESSAGE = "The role does not grant the required permission"
+)
+
+type PermissionError struct {
+	Code          errors.ErrorCode
+	Kind          errors.ErrorKind
+	Required      models.Permission
+	PresentedRole models.UserRole
+}
+
+func NewMissingPermissionError(required models.Permission, presented models.UserRole) *PermissionError {
+	return &PermissionError{
+		errors.EC_PERMISSION_MISSING,
+		errors.EK_ROLES,
+		required,
+		presented,
+	}
+}
+
+func (p *PermissionError) Error() string {
+	return fmt.Sprintf(PE_ERROR_FORMAT, p.Code)
+}
+
+func (p *PermissionError) GetErrorCode() errors.ErrorCode {
+	return p.Code
+}
+
+func (p *PermissionError) GetErrorKind() errors.ErrorKind {
+	return p.Kind
+}
+
+func (p *PermissionError) IntoPublic() *errors.PublicPCCError {
+	return errors.NewPublicPCCError(
+		p.Code,
+		p.Kind,
+		map[string]string{
+			"required":  string(p.Required),
+			"presented": string(p.PresentedRole),
+		},
+		PE_SAFE_MESSAGE,
+	)
+}
@@ -4,7 +4,6 @@ import (
 	"net/http"
 
 	"github.com/PC-Core/pc-core-backend/internal/auth"
-	"github.com/PC-Core/pc-core-backend/internal/database"
 	"github.com/PC-Core/pc-core-backend/internal/errors"
 	"github.com/PC-Core/pc-core-backend/internal/helpers"
 	"github.com/PC-Core/pc-core-backend/internal/middlewares/merrors"
@@ -14,6 +13,16 @@ import (
 
 type AuthMiddleware func(auth auth.Auth) gin.HandlerFunc
 
+// privilegedMfaRequired is set on startup from the config
+var privilegedMfaRequired = false
+
+// RequirePrivilegedMfa enables or disables the MFA requirement on the routes
+// protected by RequirePermission. It applies to every role granted the
+// permission, not only to the Admin one
+func RequirePrivilegedMfa(required bool) {
+	privilegedMfaRequired = required
+}
+
 func checkJWTRefresh(ctx *gin.Context) {
 	// tk, err := ctx.Request.Cookie(helpers.REFRESH_COOKIE_NAME)
 
@@ -40,12 +49,33 @@ func JWTAuthorize(auth auth.Auth) gin.HandlerFunc {
 			return
 		}
 
+		if impersonator := getImpersonator(data); impersonator != nil && !isReadOnlyMethod(ctx.Request.Method) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": merrors.NewImpersonationReadOnlyError(*impersonator).IntoPublic()})
+			ctx.Abort()
+			return
+		}
+
 		ctx.Set(helpers.UserDataKey, data)
 
 		ctx.Next()
 	}
 }
 
+func getImpersonator(data interface{}) *int {
+	imp, ok := data.(auth.Impersonated)
+
+	if !ok {
+		return nil
+	}
+
+	return imp.GetImpersonator()
+}
+
+// isReadOnlyMethod returns true for the methods allowed in the impersonation session
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
 // If user is authorized, save his data in gin context
 //
 // Else just go next
@@ -70,7 +100,15 @@ func JWTNotRequired(auth auth.Auth) gin.HandlerFunc {
 	}
 }
 
-func RoleCheck(required models.UserRole, db database.DbController, caster helpers.RoleCastFunc) gin.HandlerFunc {
+// HasPermission reports whether the role of the user grants the permission. The MFA is
+// required the same way as by RequirePermission, so the handlers which show more to the
+// privileged users don't grant more than the protected routes
+func HasPermission(user *models.PublicUser, required models.Permission) bool {
+	return user.Permissions.Has(required) && (!privilegedMfaRequired || user.Mfa)
+}
+
+// RequirePermission allows the request only if the role of the user grants the permission
+func RequirePermission(required models.Permission, pucaster helpers.PublicUserCaster) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		data, exists := ctx.Get(helpers.UserDataKey)
 
@@ -80,16 +118,22 @@ func RoleCheck(required models.UserRole, db database.DbController, caster helper
 			return
 		}
 
-		role, err := caster(data)
+		user, err := pucaster(data)
 
 		if err != nil {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": errors.NewInternalSecretError()})
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.IntoPublic()})
+			ctx.Abort()
+			return
+		}
+
+		if !user.Permissions.Has(required) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": merrors.NewMissingPermissionError(required, user.Role).IntoPublic()})
 			ctx.Abort()
 			return
 		}
 
-		if role != required {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": merrors.NewLowerRoleError(required, role)})
+		if privilegedMfaRequired && !user.Mfa {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": merrors.NewMfaRequiredError(user.Role).IntoPublic()})
 			ctx.Abort()
 			return
 		}
@@ -0,0 +1,42 @@
+package notifications
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+)
+
+// Channel delivers the notifications of one models.NotificationChannel
+type Channel interface {
+	Name() models.NotificationChannel
+	Send(notification *models.Notification) errors.PCCError
+}
+
+// Message returns the subject and the text of the notification
+func Message(notification *models.Notification) (string, string) {
+	payload := &notification.Payload
+
+	switch notification.Kind {
+	case models.SubscriptionQuestionAnswered:
+		return fmt.Sprintf("Your question about %s was answered", payload.ProductName),
+			fmt.Sprintf("Your question about %s has got a new answer.", payload.ProductName)
+	case models.SubscriptionPriceDrop:
+		return fmt.Sprintf("The price of %s has dropped", payload.ProductName),
+			fmt.Sprintf("The price of %s has dropped from %s to %s %s.", payload.ProductName, payload.OldPrice, payload.Price, payload.Price.Currency)
+	default:
+		return fmt.Sprintf("%s is back in stock", payload.ProductName),
+			fmt.Sprintf("%s is back in stock: %d available for %s %s.", payload.ProductName, payload.Stock, payload.Price, payload.Price.Currency)
+	}
+}
+
+// UnsubscribeLink returns the link to the unsubscribe page or the empty string
+// if the subscription was already deleted
+func UnsubscribeLink(base string, notification *models.Notification) string {
+	if base == "" || notification.UnsubscribeToken == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s?token=%s", base, url.QueryEscape(*notification.UnsubscribeToken))
+}
@@ -0,0 +1,121 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/PC-Core/pc-core-backend/internal/database"
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/pkg/config"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+)
+
+const (
+	DefaultPollInterval = 10 * time.Second
+	DefaultBatchSize    = 50
+	DefaultMaxAttempts  = 5
+	// ClaimTimeout is the time after which the claimed notification is considered
+	// lost by the crashed dispatcher and is claimed again
+	ClaimTimeout = 10 * time.Minute
+)
+
+// Dispatcher delivers the notifications enqueued into the outbox by the database
+type Dispatcher struct {
+	db          database.DbController
+	channels    map[models.NotificationChannel]Channel
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+}
+
+func NewDispatcher(db database.DbController, cfg *config.NotificationsConfig, channels ...Channel) *Dispatcher {
+	d := &Dispatcher{
+		db, make(map[models.NotificationChannel]Channel, len(channels)), cfg.PollInterval, cfg.BatchSize, cfg.MaxAttempts,
+	}
+
+	if d.interval <= 0 {
+		d.interval = DefaultPollInterval
+	}
+
+	if d.batchSize <= 0 {
+		d.batchSize = DefaultBatchSize
+	}
+
+	if d.maxAttempts <= 0 {
+		d.maxAttempts = DefaultMaxAttempts
+	}
+
+	for _, channel := range channels {
+		d.channels[channel.Name()] = channel
+	}
+
+	return d
+}
+
+// Run dispatches the notifications until the context is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			sent, err := d.DispatchOnce()
+
+			if err != nil {
+				log.Printf("notifications: %s", err.Error())
+			}
+
+			// The full batch means there may be more notifications waiting
+			if err != nil || sent < d.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchOnce delivers one batch of the notifications and returns its size
+func (d *Dispatcher) DispatchOnce() (int, errors.PCCError) {
+	notifications, err := d.db.ClaimNotifications(d.batchSize, ClaimTimeout)
+
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range notifications {
+		notification := &notifications[i]
+
+		if serr := d.send(notification); serr != nil {
+			err = d.db.MarkNotificationFailed(notification.ID, serr.Error(), d.maxAttempts)
+		} else {
+			err = d.db.MarkNotificationSent(notification.ID)
+		}
+
+		if err != nil {
+			log.Printf("notifications: %d: %s", notification.ID, err.Error())
+		}
+	}
+
+	return len(notifications), nil
+}
+
+func (d *Dispatcher) send(notification *models.Notification) error {
+	channel, ok := d.channels[notification.Channel]
+
+	if !ok {
+		return fmt.Errorf("the channel %s is not configured", notification.Channel)
+	}
+
+	if err := channel.Send(notification); err != nil {
+		return err
+	}
+
+	return nil
+}
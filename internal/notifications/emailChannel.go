@@ -0,0 +1,34 @@
+package notifications
+
+import (
+	"fmt"
+
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/mailer"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+)
+
+type EmailChannel struct {
+	mailer         mailer.Mailer
+	unsubscribeURL string
+}
+
+func NewEmailChannel(mailer mailer.Mailer, unsubscribeURL string) *EmailChannel {
+	return &EmailChannel{
+		mailer, unsubscribeURL,
+	}
+}
+
+func (c *EmailChannel) Name() models.NotificationChannel {
+	return models.NotificationEmail
+}
+
+func (c *EmailChannel) Send(notification *models.Notification) errors.PCCError {
+	subject, body := Message(notification)
+
+	if link := UnsubscribeLink(c.unsubscribeURL, notification); link != "" {
+		body = fmt.Sprintf("%s\n\nUnsubscribe: %s\n", body, link)
+	}
+
+	return c.mailer.Send(notification.UserEmail, subject, body)
+}
@@ -0,0 +1,22 @@
+package notifications
+
+import (
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+)
+
+// InAppChannel has nothing to send. The sent in-app notifications are shown to the user
+// by the GET /notifications route
+type InAppChannel struct{}
+
+func NewInAppChannel() *InAppChannel {
+	return &InAppChannel{}
+}
+
+func (c *InAppChannel) Name() models.NotificationChannel {
+	return models.NotificationInApp
+}
+
+func (c *InAppChannel) Send(notification *models.Notification) errors.PCCError {
+	return nil
+}
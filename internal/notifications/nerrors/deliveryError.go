@@ -0,0 +1,40 @@
+package nerrors
+
+import (
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+)
+
+const NE_DELIVERY_MESSAGE = "Failed to deliver the notification"
+
+type DeliveryError struct {
+	Inner error
+	Code  errors.ErrorCode
+	Kind  errors.ErrorKind
+}
+
+func NewDeliveryError(inner error) *DeliveryError {
+	return &DeliveryError{
+		inner, errors.EC_NOTIFICATION_DELIVERY_FAILED, errors.EK_NOTIFICATIONS,
+	}
+}
+
+// Error contains the inner error, because it is saved as the last error of the notification
+func (e *DeliveryError) Error() string {
+	if e.Inner == nil {
+		return NE_DELIVERY_MESSAGE
+	}
+
+	return NE_DELIVERY_MESSAGE + ": " + e.Inner.Error()
+}
+
+func (e *DeliveryError) GetErrorCode() errors.ErrorCode {
+	return e.Code
+}
+
+func (e *DeliveryError) GetErrorKind() errors.ErrorKind {
+	return e.Kind
+}
+
+func (e *DeliveryError) IntoPublic() *errors.PublicPCCError {
+	return errors.NewPublicPCCError(e.Code, e.Kind, nil, NE_DELIVERY_MESSAGE)
+}
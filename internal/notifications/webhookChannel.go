@@ -0,0 +1,159 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/notifications/nerrors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+)
+
+const DefaultWebhookTimeout = 5 * time.Second
+
+var (
+	ErrWebhookScheme  = goerrors.New("the webhook url must use https")
+	ErrWebhookAddress = goerrors.New("the webhook address is not public")
+)
+
+// webhookBody is sent as JSON to the webhook of the user
+type webhookBody struct {
+	ID             int64                      `json:"id"`
+	Kind           models.SubscriptionKind    `json:"kind"`
+	ProductID      uint64                     `json:"product_id"`
+	Payload        models.NotificationPayload `json:"payload"`
+	CreatedAt      time.Time                  `json:"created_at"`
+	UnsubscribeURL string                     `json:"unsubscribe_url,omitempty"`
+}
+
+type WebhookChannel struct {
+	client         *http.Client
+	unsubscribeURL string
+}
+
+// NewWebhookChannel creates the channel which posts the notifications to the HTTPS urls
+// provided by the users. The connections to the private and the loopback addresses are refused
+func NewWebhookChannel(timeout time.Duration, unsubscribeURL string) *WebhookChannel {
+	if timeout <= 0 {
+		timeout = DefaultWebhookTimeout
+	}
+
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+
+			if err != nil {
+				return err
+			}
+
+			if ip := net.ParseIP(host); ip == nil || !IsPublicIP(ip) {
+				return ErrWebhookAddress
+			}
+
+			return nil
+		},
+	}
+
+	transport := &http.Transport{
+		Proxy:       nil,
+		DialContext: dialer.DialContext,
+	}
+
+	return &WebhookChannel{
+		&http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		unsubscribeURL,
+	}
+}
+
+// IsPublicIP reports whether the webhook may be delivered to the address
+func IsPublicIP(ip net.IP) bool {
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !ip.IsLoopback() && !ip.IsLinkLocalUnicast()
+}
+
+// CheckWebhookURL checks the url provided by the user
+func CheckWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+
+	if err != nil {
+		return err
+	}
+
+	if u.Scheme != "https" || u.Host == "" {
+		return ErrWebhookScheme
+	}
+
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !IsPublicIP(ip) {
+		return ErrWebhookAddress
+	}
+
+	return nil
+}
+
+func (c *WebhookChannel) Name() models.NotificationChannel {
+	return models.NotificationWebhook
+}
+
+func (c *WebhookChannel) Send(notification *models.Notification) errors.PCCError {
+	if notification.WebhookURL == nil {
+		return nerrors.NewDeliveryError(fmt.Errorf("the subscription of the notification was deleted"))
+	}
+
+	if err := CheckWebhookURL(*notification.WebhookURL); err != nil {
+		return nerrors.NewDeliveryError(err)
+	}
+
+	body, err := json.Marshal(&webhookBody{
+		notification.ID,
+		notification.Kind,
+		notification.ProductID,
+		notification.Payload,
+		notification.CreatedAt,
+		UnsubscribeLink(c.unsubscribeURL, notification),
+	})
+
+	if err != nil {
+		return nerrors.NewDeliveryError(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.client.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, *notification.WebhookURL, bytes.NewReader(body))
+
+	if err != nil {
+		return nerrors.NewDeliveryError(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	// The receiver may use the key to drop the repeated deliveries
+	req.Header.Set("Idempotency-Key", fmt.Sprintf("notification-%d", notification.ID))
+
+	resp, err := c.client.Do(req)
+
+	if err != nil {
+		return nerrors.NewDeliveryError(err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nerrors.NewDeliveryError(fmt.Errorf("the webhook responded with %d", resp.StatusCode))
+	}
+
+	return nil
+}
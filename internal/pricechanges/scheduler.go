@@ -0,0 +1,50 @@
+package pricechanges
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/PC-Core/pc-core-backend/internal/database"
+)
+
+// DefaultApplyInterval is used if the price changes config doesn't set the interval
+const DefaultApplyInterval = time.Minute
+
+// Scheduler applies the scheduled price changes when their time comes
+type Scheduler struct {
+	db       database.DbController
+	interval time.Duration
+}
+
+func NewScheduler(db database.DbController, interval time.Duration) *Scheduler {
+	if interval <= 0 {
+		interval = DefaultApplyInterval
+	}
+
+	return &Scheduler{
+		db, interval,
+	}
+}
+
+// Run applies the due price changes until the context is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		applied, err := s.db.ApplyScheduledPriceChanges()
+
+		if err != nil {
+			log.Printf("price changes: %s", err.Error())
+		} else if applied != 0 {
+			log.Printf("price changes: applied %d scheduled changes", applied)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package pricing
+
+import (
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/pricing/prerrors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+)
+
+// SummarizeCart computes the line totals, the subtotal and the item count and
+// marks the items which are unavailable, lack the stock or changed the price since
+// they were added. The stock reserved by the checkouts is not counted as available.
+// The currency of the cart is the currency of the first available item, the items priced
+// in other currencies are marked. The unavailable and the marked items are not included in the subtotal.
+// The discounts are reset, so ApplyPromotions should be called after it
+func SummarizeCart(cart *models.Cart) errors.PCCError {
+	currency := cartCurrency(cart)
+
+	subtotal := models.NewMoney(0, currency)
+	cart.ItemsCount = 0
+	cart.HasIssues = false
+
+	for i := range cart.Items {
+		item := &cart.Items[i]
+		price := item.Product.Price
+
+		total, err := price.Mul(int64(item.Quantity))
+
+		if err != nil {
+			return prerrors.MoneyErrorCast(err)
+		}
+
+		item.LineTotal = total
+		item.Discount = models.NewMoney(0, price.Currency)
+		item.Adjustments = []models.PriceAdjustment{}
+		item.Unavailable = item.Product.Available == 0
+		item.InsufficientStock = !item.Unavailable && uint64(item.Quantity) > item.Product.Available
+		item.PriceChanged = item.PriceAtAdd != nil && !item.PriceAtAdd.Equal(price)
+		item.CurrencyMismatch = price.Currency != currency
+
+		if item.Unavailable || item.InsufficientStock || item.PriceChanged || item.CurrencyMismatch {
+			cart.HasIssues = true
+		}
+
+		if !item.Unavailable && !item.CurrencyMismatch {
+			if subtotal, err = subtotal.Add(total); err != nil {
+				return prerrors.MoneyErrorCast(err)
+			}
+
+			cart.ItemsCount += item.Quantity
+		}
+	}
+
+	cart.Subtotal = subtotal
+	cart.Currency = currency
+	cart.Discount = models.NewMoney(0, currency)
+	cart.Total = subtotal
+	cart.Promotions = []models.AppliedPromotion{}
+
+	return nil
+}
+
+func cartCurrency(cart *models.Cart) models.Currency {
+	for _, item := range cart.Items {
+		if item.Product.Available != 0 {
+			return item.Product.Price.Currency
+		}
+	}
+
+	if len(cart.Items) != 0 {
+		return cart.Items[0].Product.Price.Currency
+	}
+
+	return models.DefaultCurrency
+}
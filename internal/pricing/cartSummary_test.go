@@ -0,0 +1,85 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+)
+
+func newTestItem(id uint64, price int64, currency models.Currency, quantity uint, available uint64) models.CartItem {
+	product := models.Product{ID: id, Price: models.NewMoney(price, currency), Currency: currency, Available: available}
+
+	return *models.NewCartItem(product, quantity, time.Time{})
+}
+
+func newTestCart(items ...models.CartItem) *models.Cart {
+	return models.NewCart(1, items)
+}
+
+// TestSummarizeMixedCurrencyCart checks that the items priced in another currency are
+// marked and left out of the totals instead of failing the whole cart
+func TestSummarizeMixedCurrencyCart(t *testing.T) {
+	cart := newTestCart(
+		newTestItem(1, 10000, models.CurrencyRUB, 2, 10),
+		newTestItem(2, 500, models.CurrencyUSD, 1, 10),
+		newTestItem(3, 2550, models.CurrencyRUB, 1, 10),
+	)
+
+	if err := SummarizeCart(cart); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := models.NewMoney(22550, models.CurrencyRUB); !cart.Subtotal.Equal(expected) || !cart.Total.Equal(expected) {
+		t.Fatalf("subtotal %v and total %v, expected %v", cart.Subtotal, cart.Total, expected)
+	}
+
+	if cart.Currency != models.CurrencyRUB || cart.ItemsCount != 3 || !cart.HasIssues {
+		t.Fatalf("currency %s, %d items, issues %t", cart.Currency, cart.ItemsCount, cart.HasIssues)
+	}
+
+	for _, item := range cart.Items {
+		if mismatch := item.Product.ID == 2; item.CurrencyMismatch != mismatch {
+			t.Errorf("product %d: currency mismatch %t, expected %t", item.Product.ID, item.CurrencyMismatch, mismatch)
+		}
+	}
+
+	if expected := models.NewMoney(500, models.CurrencyUSD); !cart.Items[1].LineTotal.Equal(expected) {
+		t.Errorf("line total %v, expected %v", cart.Items[1].LineTotal, expected)
+	}
+
+	promotion := models.Promotion{ID: 1, Kind: models.PromotionPercent, Percent: 10, Scope: models.PromotionScopeCart, Active: true}
+
+	if err := ApplyPromotions(cart, []models.Promotion{promotion}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := models.NewMoney(2255, models.CurrencyRUB); !cart.Discount.Equal(expected) {
+		t.Fatalf("discount %v, expected %v", cart.Discount, expected)
+	}
+
+	if !cart.Items[1].Discount.IsZero() {
+		t.Fatalf("the item in another currency is discounted by %v", cart.Items[1].Discount)
+	}
+}
+
+// TestSummarizeCartCurrency checks that the currency of the cart is taken from the
+// first available item
+func TestSummarizeCartCurrency(t *testing.T) {
+	cart := newTestCart(
+		newTestItem(1, 500, models.CurrencyUSD, 1, 0),
+		newTestItem(2, 10000, models.CurrencyRUB, 1, 10),
+	)
+
+	if err := SummarizeCart(cart); err != nil {
+		t.Fatal(err)
+	}
+
+	if cart.Currency != models.CurrencyRUB || !cart.Subtotal.Equal(models.NewMoney(10000, models.CurrencyRUB)) {
+		t.Fatalf("currency %s, subtotal %v", cart.Currency, cart.Subtotal)
+	}
+
+	if !cart.Items[0].Unavailable || !cart.Items[0].CurrencyMismatch {
+		t.Fatalf("the unavailable item is not marked: %+v", cart.Items[0])
+	}
+}
@@ -0,0 +1,65 @@
+package prerrors
+
+import (
+	goerrors "errors"
+
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+)
+
+const (
+	PR_INVALID_MESSAGE  = "The amount must be a decimal with at most two digits after the point in the supported currency"
+	PR_MISMATCH_MESSAGE = "The values of different currencies can't be combined"
+	PR_OVERFLOW_MESSAGE = "The amount is too large"
+)
+
+type MoneyError struct {
+	Code    errors.ErrorCode
+	Kind    errors.ErrorKind
+	Message string
+}
+
+func NewMoneyInvalidError() *MoneyError {
+	return &MoneyError{errors.EC_MONEY_INVALID, errors.EK_MONEY, PR_INVALID_MESSAGE}
+}
+
+func NewMoneyCurrencyMismatchError() *MoneyError {
+	return &MoneyError{errors.EC_MONEY_CURRENCY_MISMATCH, errors.EK_MONEY, PR_MISMATCH_MESSAGE}
+}
+
+func NewMoneyOverflowError() *MoneyError {
+	return &MoneyError{errors.EC_MONEY_OVERFLOW, errors.EK_MONEY, PR_OVERFLOW_MESSAGE}
+}
+
+// MoneyErrorCast translates the errors of the models.Money operations.
+// It returns nil if the error is not related to the money
+func MoneyErrorCast(err error) errors.PCCError {
+	switch {
+	case err == nil:
+		return nil
+	case goerrors.Is(err, models.ErrInvalidMoney):
+		return NewMoneyInvalidError()
+	case goerrors.Is(err, models.ErrCurrencyMismatch):
+		return NewMoneyCurrencyMismatchError()
+	case goerrors.Is(err, models.ErrMoneyOverflow):
+		return NewMoneyOverflowError()
+	default:
+		return nil
+	}
+}
+
+func (e *MoneyError) Error() string {
+	return e.Message
+}
+
+func (e *MoneyError) GetErrorCode() errors.ErrorCode {
+	return e.Code
+}
+
+func (e *MoneyError) GetErrorKind() errors.ErrorKind {
+	return e.Kind
+}
+
+func (e *MoneyError) IntoPublic() *errors.PublicPCCError {
+	return errors.NewPublicPCCError(e.Code, e.Kind, nil, e.Message)
+}
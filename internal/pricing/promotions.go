@@ -0,0 +1,274 @@
+package pricing
+
+import (
+	"math/big"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/pricing/prerrors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+)
+
+// CheckPromotion returns the reason the promotion can't be applied to the summarized
+// cart at the moment or the empty string if it can
+func CheckPromotion(promotion *models.Promotion, cart *models.Cart, now time.Time) models.PromotionRejection {
+	switch {
+	case !promotion.Active:
+		return models.PromotionInactive
+	case now.Before(promotion.StartsAt):
+		return models.PromotionNotStarted
+	case promotion.EndsAt != nil && !now.Before(*promotion.EndsAt):
+		return models.PromotionExpired
+	case promotion.UsageLimit != nil && promotion.Used >= *promotion.UsageLimit:
+		return models.PromotionUsageLimit
+	case promotion.PerUserLimit != nil && promotion.UsedByUser >= *promotion.PerUserLimit:
+		return models.PromotionUserLimit
+	}
+
+	if promotion.Amount != nil && promotion.Amount.Currency != cart.Subtotal.Currency {
+		return models.PromotionCurrency
+	}
+
+	if promotion.MinCart != nil {
+		if promotion.MinCart.Currency != cart.Subtotal.Currency {
+			return models.PromotionCurrency
+		}
+
+		if cart.Subtotal.Minor < promotion.MinCart.Minor {
+			return models.PromotionMinCart
+		}
+	}
+
+	if len(matchingLines(promotion, cart)) == 0 {
+		return models.PromotionNoMatchingItems
+	}
+
+	return ""
+}
+
+// CanStack reports whether the promotion can be added to the already applied ones
+func CanStack(promotion *models.Promotion, applied []models.Promotion) bool {
+	if len(applied) == 0 {
+		return true
+	}
+
+	if !promotion.Stackable {
+		return false
+	}
+
+	for _, p := range applied {
+		if !p.Stackable {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ApplyPromotions applies the promotions to the cart summarized by SummarizeCart in
+// the order of their priority. Each promotion is applied to the line prices left by
+// the previous ones and its discount is recorded in the adjustments of the lines.
+// The promotions which can't be applied are listed in the cart with the reason
+func ApplyPromotions(cart *models.Cart, promotions []models.Promotion, now time.Time) errors.PCCError {
+	ordered := slices.Clone(promotions)
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Priority != ordered[j].Priority {
+			return ordered[i].Priority < ordered[j].Priority
+		}
+
+		return ordered[i].ID < ordered[j].ID
+	})
+
+	discount := models.NewMoney(0, cart.Subtotal.Currency)
+	cart.Promotions = make([]models.AppliedPromotion, 0, len(ordered))
+	applied := make([]models.Promotion, 0, len(ordered))
+
+	for i := range ordered {
+		promotion := &ordered[i]
+		reason := CheckPromotion(promotion, cart, now)
+
+		if reason == "" && !CanStack(promotion, applied) {
+			reason = models.PromotionNotStackable
+		}
+
+		if reason != "" {
+			cart.Promotions = append(cart.Promotions, *models.NewAppliedPromotion(promotion, models.NewMoney(0, discount.Currency), reason))
+			continue
+		}
+
+		total, err := applyPromotion(promotion, cart)
+
+		if err != nil {
+			return err
+		}
+
+		if discount, err = addMoney(discount, total); err != nil {
+			return err
+		}
+
+		applied = append(applied, *promotion)
+		cart.Promotions = append(cart.Promotions, *models.NewAppliedPromotion(promotion, total, ""))
+	}
+
+	cart.Discount = discount
+
+	total, merr := cart.Subtotal.Sub(discount)
+
+	if merr != nil {
+		return prerrors.MoneyErrorCast(merr)
+	}
+
+	cart.Total = total
+
+	return nil
+}
+
+func addMoney(a, b models.Money) (models.Money, errors.PCCError) {
+	sum, err := a.Add(b)
+
+	if err != nil {
+		return models.Money{}, prerrors.MoneyErrorCast(err)
+	}
+
+	return sum, nil
+}
+
+// applyPromotion computes the discount for every matching line and returns the total discount
+func applyPromotion(promotion *models.Promotion, cart *models.Cart) (models.Money, errors.PCCError) {
+	lines := matchingLines(promotion, cart)
+	remaining := make([]*big.Int, len(lines))
+	sum := new(big.Int)
+
+	for i, idx := range lines {
+		item := &cart.Items[idx]
+		remaining[i] = big.NewInt(item.LineTotal.Minor - item.Discount.Minor)
+		sum.Add(sum, remaining[i])
+	}
+
+	discounts := make([]*big.Int, len(lines))
+
+	switch promotion.Kind {
+	case models.PromotionPercent:
+		percent := big.NewInt(int64(promotion.Percent))
+
+		for i, rem := range remaining {
+			discounts[i] = divRoundHalfUp(new(big.Int).Mul(rem, percent), big.NewInt(100))
+		}
+	case models.PromotionFixed:
+		amount := big.NewInt(promotion.Amount.Minor)
+
+		if amount.Cmp(sum) > 0 {
+			amount.Set(sum)
+		}
+
+		distribute(amount, remaining, sum, discounts)
+	}
+
+	total := models.NewMoney(0, cart.Subtotal.Currency)
+
+	for i, idx := range lines {
+		if discounts[i].Sign() == 0 {
+			continue
+		}
+
+		item := &cart.Items[idx]
+		adjustment := models.NewMoney(discounts[i].Int64(), item.Discount.Currency)
+
+		var err errors.PCCError
+
+		if item.Discount, err = addMoney(item.Discount, adjustment); err != nil {
+			return models.Money{}, err
+		}
+
+		if total, err = addMoney(total, adjustment); err != nil {
+			return models.Money{}, err
+		}
+
+		item.Adjustments = append(item.Adjustments, *models.NewPriceAdjustment(promotion.ID, promotion.Code, adjustment))
+	}
+
+	return total, nil
+}
+
+// distribute splits the amount between the lines proportionally to their remaining
+// prices. The kopecks left after the rounding down go to the first lines which still
+// have the price to discount, so the parts always sum up to the amount
+func distribute(amount *big.Int, remaining []*big.Int, sum *big.Int, out []*big.Int) {
+	left := new(big.Int).Set(amount)
+
+	for i, rem := range remaining {
+		out[i] = new(big.Int)
+
+		if sum.Sign() != 0 {
+			out[i].Div(new(big.Int).Mul(amount, rem), sum)
+		}
+
+		left.Sub(left, out[i])
+	}
+
+	for i, rem := range remaining {
+		if left.Sign() == 0 {
+			break
+		}
+
+		capacity := new(big.Int).Sub(rem, out[i])
+
+		if capacity.Cmp(left) > 0 {
+			capacity.Set(left)
+		}
+
+		out[i].Add(out[i], capacity)
+		left.Sub(left, capacity)
+	}
+}
+
+func divRoundHalfUp(x, y *big.Int) *big.Int {
+	quo, rem := new(big.Int).QuoRem(x, y, new(big.Int))
+
+	if new(big.Int).Mul(rem, big.NewInt(2)).Cmp(y) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+
+	return quo
+}
+
+// matchingLines returns the indexes of the available cart lines in the scope of the promotion
+func matchingLines(promotion *models.Promotion, cart *models.Cart) []int {
+	lines := make([]int, 0, len(cart.Items))
+
+	for i := range cart.Items {
+		item := &cart.Items[i]
+
+		if item.Unavailable || item.CurrencyMismatch {
+			continue
+		}
+
+		if item.LineTotal.Minor-item.Discount.Minor <= 0 {
+			continue
+		}
+
+		if inScope(promotion, &item.Product) {
+			lines = append(lines, i)
+		}
+	}
+
+	return lines
+}
+
+func inScope(promotion *models.Promotion, product *models.Product) bool {
+	switch promotion.Scope {
+	case models.PromotionScopeCart:
+		return true
+	case models.PromotionScopeProducts:
+		return slices.Contains(promotion.ProductIDs, product.ID)
+	case models.PromotionScopeCategories:
+		return slices.Contains(promotion.CategoryCharsTables, product.CharTableName)
+	case models.PromotionScopeCharsTables:
+		return slices.Contains(promotion.CharsTables, product.CharTableName)
+	default:
+		return false
+	}
+}
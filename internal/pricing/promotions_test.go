@@ -0,0 +1,263 @@
+package pricing
+
+import (
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+)
+
+func bigInts(values ...int64) []*big.Int {
+	out := make([]*big.Int, len(values))
+
+	for i, v := range values {
+		out[i] = big.NewInt(v)
+	}
+
+	return out
+}
+
+func TestDivRoundHalfUp(t *testing.T) {
+	cases := []struct {
+		x, y, quo int64
+	}{
+		{0, 100, 0},
+		{4, 3, 1},
+		{5, 3, 2},
+		{1, 2, 1},
+		{5, 2, 3},
+		{149, 100, 1},
+		{150, 100, 2},
+		{199999, 100, 2000},
+	}
+
+	for _, c := range cases {
+		if quo := divRoundHalfUp(big.NewInt(c.x), big.NewInt(c.y)); quo.Int64() != c.quo {
+			t.Errorf("divRoundHalfUp(%d, %d) = %d, expected %d", c.x, c.y, quo, c.quo)
+		}
+	}
+}
+
+func TestDistribute(t *testing.T) {
+	cases := []struct {
+		name      string
+		amount    int64
+		remaining []int64
+		parts     []int64
+	}{
+		{"proportional", 10, []int64{3, 7}, []int64{3, 7}},
+		{"remainder to the first line", 100, []int64{100, 100, 100}, []int64{34, 33, 33}},
+		{"all remainders to the first line", 200, []int64{100, 100, 100}, []int64{68, 66, 66}},
+		{"remainder skips the full line", 5, []int64{1, 100}, []int64{1, 4}},
+		{"the whole remaining price", 101, []int64{1, 100}, []int64{1, 100}},
+		{"nothing to discount", 0, []int64{0, 0}, []int64{0, 0}},
+	}
+
+	for _, c := range cases {
+		remaining := bigInts(c.remaining...)
+		sum := new(big.Int)
+
+		for _, rem := range remaining {
+			sum.Add(sum, rem)
+		}
+
+		out := make([]*big.Int, len(remaining))
+		distribute(big.NewInt(c.amount), remaining, sum, out)
+
+		parts := make([]int64, len(out))
+
+		for i, part := range out {
+			parts[i] = part.Int64()
+		}
+
+		if !slices.Equal(parts, c.parts) {
+			t.Errorf("%s: parts %v, expected %v", c.name, parts, c.parts)
+		}
+	}
+}
+
+func rub(minor int64) *models.Money {
+	money := models.NewMoney(minor, models.CurrencyRUB)
+	return &money
+}
+
+func limit(value uint) *uint {
+	return &value
+}
+
+func percentPromotion(id int64, percent uint, priority int) models.Promotion {
+	return models.Promotion{
+		ID: id, Kind: models.PromotionPercent, Percent: percent, Scope: models.PromotionScopeCart,
+		Stackable: true, Priority: priority, Active: true,
+	}
+}
+
+func fixedPromotion(id int64, amount int64, priority int) models.Promotion {
+	return models.Promotion{
+		ID: id, Kind: models.PromotionFixed, Amount: rub(amount), Currency: models.CurrencyRUB,
+		Scope: models.PromotionScopeCart, Stackable: true, Priority: priority, Active: true,
+	}
+}
+
+func TestApplyPromotions(t *testing.T) {
+	cases := []struct {
+		name       string
+		items      []models.CartItem
+		promotions []models.Promotion
+		discount   int64
+		lines      []int64
+	}{
+		{
+			"percent before fixed",
+			[]models.CartItem{newTestItem(1, 10000, models.CurrencyRUB, 1, 10)},
+			[]models.Promotion{fixedPromotion(2, 1000, 1), percentPromotion(1, 10, 0)},
+			2000,
+			[]int64{2000},
+		},
+		{
+			"fixed before percent",
+			[]models.CartItem{newTestItem(1, 10000, models.CurrencyRUB, 1, 10)},
+			[]models.Promotion{fixedPromotion(2, 1000, 0), percentPromotion(1, 10, 1)},
+			1900,
+			[]int64{1900},
+		},
+		{
+			"same priority by ID",
+			[]models.CartItem{newTestItem(1, 10000, models.CurrencyRUB, 1, 10)},
+			[]models.Promotion{percentPromotion(2, 10, 0), fixedPromotion(1, 1000, 0)},
+			1900,
+			[]int64{1900},
+		},
+		{
+			"fixed larger than the subtotal",
+			[]models.CartItem{newTestItem(1, 100, models.CurrencyRUB, 1, 10), newTestItem(2, 200, models.CurrencyRUB, 1, 10)},
+			[]models.Promotion{fixedPromotion(1, 500, 0)},
+			300,
+			[]int64{100, 200},
+		},
+		{
+			"fixed after the whole subtotal is discounted",
+			[]models.CartItem{newTestItem(1, 300, models.CurrencyRUB, 1, 10)},
+			[]models.Promotion{fixedPromotion(1, 500, 0), fixedPromotion(2, 100, 1)},
+			300,
+			[]int64{300},
+		},
+		{
+			"fixed split with the remainder",
+			[]models.CartItem{
+				newTestItem(1, 100, models.CurrencyRUB, 1, 10),
+				newTestItem(2, 100, models.CurrencyRUB, 1, 10),
+				newTestItem(3, 100, models.CurrencyRUB, 1, 10),
+			},
+			[]models.Promotion{fixedPromotion(1, 100, 0)},
+			100,
+			[]int64{34, 33, 33},
+		},
+		{
+			"percent rounded per line",
+			[]models.CartItem{newTestItem(1, 5, models.CurrencyRUB, 1, 10), newTestItem(2, 15, models.CurrencyRUB, 1, 10)},
+			[]models.Promotion{percentPromotion(1, 10, 0)},
+			3,
+			[]int64{1, 2},
+		},
+	}
+
+	for _, c := range cases {
+		cart := newTestCart(c.items...)
+
+		if err := SummarizeCart(cart); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+
+		if err := ApplyPromotions(cart, c.promotions, time.Now()); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+
+		if cart.Discount.Minor != c.discount || cart.Total.Minor != cart.Subtotal.Minor-c.discount {
+			t.Errorf("%s: discount %v and total %v of %v, expected the discount %d", c.name, cart.Discount, cart.Total, cart.Subtotal, c.discount)
+			continue
+		}
+
+		lines := make([]int64, len(cart.Items))
+
+		for i, item := range cart.Items {
+			lines[i] = item.Discount.Minor
+		}
+
+		if !slices.Equal(lines, c.lines) {
+			t.Errorf("%s: line discounts %v, expected %v", c.name, lines, c.lines)
+		}
+	}
+}
+
+func TestCheckPromotion(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+
+	cases := []struct {
+		name   string
+		modify func(p *models.Promotion)
+		reason models.PromotionRejection
+	}{
+		{"applicable", func(p *models.Promotion) {}, ""},
+		{"inactive", func(p *models.Promotion) { p.Active = false }, models.PromotionInactive},
+		{"not started", func(p *models.Promotion) { p.StartsAt = later }, models.PromotionNotStarted},
+		{"expired", func(p *models.Promotion) { p.EndsAt = &now }, models.PromotionExpired},
+		{"usage limit left", func(p *models.Promotion) { p.UsageLimit, p.Used = limit(3), 2 }, ""},
+		{"usage limit exhausted", func(p *models.Promotion) { p.UsageLimit, p.Used = limit(3), 3 }, models.PromotionUsageLimit},
+		{"user limit left", func(p *models.Promotion) { p.PerUserLimit, p.UsedByUser = limit(1), 0 }, ""},
+		{"user limit exhausted", func(p *models.Promotion) { p.PerUserLimit, p.UsedByUser = limit(1), 1 }, models.PromotionUserLimit},
+		{"min subtotal reached", func(p *models.Promotion) { p.MinCart = rub(10000) }, ""},
+		{"min subtotal not reached", func(p *models.Promotion) { p.MinCart = rub(10001) }, models.PromotionMinCart},
+		{
+			"min subtotal in another currency",
+			func(p *models.Promotion) {
+				usd := models.NewMoney(100, models.CurrencyUSD)
+				p.MinCart = &usd
+			},
+			models.PromotionCurrency,
+		},
+		{
+			"no matching items",
+			func(p *models.Promotion) { p.Scope, p.ProductIDs = models.PromotionScopeProducts, []uint64{2} },
+			models.PromotionNoMatchingItems,
+		},
+	}
+
+	for _, c := range cases {
+		cart := newTestCart(newTestItem(1, 10000, models.CurrencyRUB, 1, 10))
+
+		if err := SummarizeCart(cart); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+
+		promotion := percentPromotion(1, 10, 0)
+		promotion.StartsAt = now.Add(-time.Hour)
+		c.modify(&promotion)
+
+		if reason := CheckPromotion(&promotion, cart, now); reason != c.reason {
+			t.Errorf("%s: reason %q, expected %q", c.name, reason, c.reason)
+		}
+	}
+}
+
+func TestApplyPromotionsNotStackable(t *testing.T) {
+	cart := newTestCart(newTestItem(1, 10000, models.CurrencyRUB, 1, 10))
+
+	if err := SummarizeCart(cart); err != nil {
+		t.Fatal(err)
+	}
+
+	alone := percentPromotion(2, 20, 1)
+	alone.Stackable = false
+
+	if err := ApplyPromotions(cart, []models.Promotion{percentPromotion(1, 10, 0), alone}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	if cart.Discount.Minor != 1000 || cart.Promotions[1].Reason != models.PromotionNotStackable {
+		t.Fatalf("discount %v, promotions %+v", cart.Discount, cart.Promotions)
+	}
+}
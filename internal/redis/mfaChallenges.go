@@ -0,0 +1,46 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/redis/rerrors"
+	"github.com/redis/go-redis/v9"
+)
+
+func mfaChallengeKey(challengeID string) string {
+	return fmt.Sprintf("mfa_challenge:%s", challengeID)
+}
+
+// AddMfaChallengeAttempt counts the second factor check for the challenge and
+// returns the amount of the checks made including this one. The counter lives
+// as long as the challenge itself
+func (c *RedisController) AddMfaChallengeAttempt(challengeID string, ttl time.Duration) (int64, errors.PCCError) {
+	var incr *redis.IntCmd
+
+	_, err := c.client.TxPipelined(context.Background(), func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(context.Background(), mfaChallengeKey(challengeID))
+		pipe.Expire(context.Background(), mfaChallengeKey(challengeID), ttl)
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, rerrors.RedisErrorCaster(err)
+	}
+
+	return incr.Val(), nil
+}
+
+// SpendMfaChallenge makes all the next attempts for the challenge fail
+func (c *RedisController) SpendMfaChallenge(challengeID string, maxAttempts int64, ttl time.Duration) errors.PCCError {
+	err := c.client.Set(context.Background(), mfaChallengeKey(challengeID), maxAttempts, ttl).Err()
+
+	if err != nil {
+		return rerrors.RedisErrorCaster(err)
+	}
+
+	return nil
+}
@@ -10,6 +10,8 @@ import (
 const (
 	RE_DEFAULT_MESSAGE_FORMAT = "Redis error with code: %d"
 	RE_MESSAGE_WRONG_VALUE    = "Redis returned the wrong value"
+	RE_MESSAGE_USER_EXPIRED   = "The temporary user has expired"
+	RE_MESSAGE_NOT_FOUND      = "Not found"
 
 	RE_SAFE_MESSAGE = "Redis error occured"
 )
@@ -35,6 +37,18 @@ func NewRedisErrorWrongValue() *RedisError {
 	}
 }
 
+func NewRedisErrorTempUserExpired() *RedisError {
+	return &RedisError{
+		nil, errors.EC_REDIS_TEMP_USER_EXPIRED, errors.EK_REDIS, RE_MESSAGE_USER_EXPIRED,
+	}
+}
+
+func NewRedisErrorNotFound() *RedisError {
+	return &RedisError{
+		nil, errors.EC_REDIS_NIL, errors.EK_REDIS, RE_MESSAGE_NOT_FOUND,
+	}
+}
+
 func (r *RedisError) Error() string {
 	return r.Message
 }
@@ -0,0 +1,50 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/redis/rerrors"
+	"github.com/redis/go-redis/v9"
+)
+
+func revocationKey(userID int) string {
+	return fmt.Sprintf("revoked:%d", userID)
+}
+
+func (c *RedisController) SetUserTokensRevokedAt(userID int, at time.Time, ttl time.Duration) errors.PCCError {
+	err := c.client.Set(context.Background(), revocationKey(userID), at.Unix(), ttl).Err()
+
+	if err != nil {
+		return rerrors.RedisErrorCaster(err)
+	}
+
+	return nil
+}
+
+func (c *RedisController) GetUserTokensRevokedAt(userID int) (*time.Time, errors.PCCError) {
+	res := c.client.Get(context.Background(), revocationKey(userID))
+
+	err := res.Err()
+
+	if err == redis.Nil {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, rerrors.RedisErrorCaster(err)
+	}
+
+	unix, perr := strconv.ParseInt(res.Val(), 10, 64)
+
+	if perr != nil {
+		return nil, rerrors.NewRedisErrorWrongValue()
+	}
+
+	at := time.Unix(unix, 0)
+
+	return &at, nil
+}
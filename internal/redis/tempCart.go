@@ -0,0 +1,218 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/redis/rerrors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// The scripts return the sentinel if the temporary user record is expired
+const tempUserExpired = -1
+
+// tempCartPrelude is the common beginning of the cart scripts.
+// KEYS[1] is the temporary user record, KEYS[2] is the cart hash.
+// The carts stored as JSON strings by the older versions are converted to hashes
+const tempCartPrelude = `
+local ttl = redis.call('PTTL', KEYS[1])
+
+if ttl == -2 then
+	return -1
+end
+
+if redis.call('TYPE', KEYS[2]).ok == 'string' then
+	local old = cjson.decode(redis.call('GET', KEYS[2]))
+	redis.call('DEL', KEYS[2])
+
+	for _, item in ipairs(old) do
+		redis.call('HINCRBY', KEYS[2], string.format('%d', item.ProductID), item.Quantity)
+	end
+end
+`
+
+// tempDataTouch makes the cart or the wishlist expire together with the temporary user
+const tempDataTouch = `
+if ttl > 0 then
+	redis.call('PEXPIRE', KEYS[2], ttl)
+end
+`
+
+var (
+	getTempCartScript = redis.NewScript(tempCartPrelude + `
+return redis.call('HGETALL', KEYS[2])
+`)
+
+	// The cart is deleted in the same script, so only one caller gets its items
+	takeTempCartScript = redis.NewScript(tempCartPrelude + `
+local items = redis.call('HGETALL', KEYS[2])
+redis.call('DEL', KEYS[2])
+
+return items
+`)
+
+	// ARGV[1] is the product ID, ARGV[2] is the quantity to add
+	addToTempCartScript = redis.NewScript(tempCartPrelude + `
+local quantity = redis.call('HINCRBY', KEYS[2], ARGV[1], ARGV[2])
+` + tempDataTouch + `
+return quantity
+`)
+
+	// ARGV[1] is the product ID, ARGV[2] is the new quantity
+	setToTempCartScript = redis.NewScript(tempCartPrelude + `
+redis.call('HSET', KEYS[2], ARGV[1], ARGV[2])
+` + tempDataTouch + `
+return tonumber(ARGV[2])
+`)
+
+	// ARGV[1] is the product ID, ARGV[2] is the signed delta. The quantity never
+	// goes below 1, the missing product is not added
+	changeTempCartQuantityScript = redis.NewScript(tempCartPrelude + `
+local current = redis.call('HGET', KEYS[2], ARGV[1])
+
+if not current then
+	return 0
+end
+
+local quantity = math.max(tonumber(current) + tonumber(ARGV[2]), 1)
+redis.call('HSET', KEYS[2], ARGV[1], quantity)
+` + tempDataTouch + `
+return quantity
+`)
+
+	// ARGV[1] is the product ID
+	removeFromTempCartScript = redis.NewScript(tempCartPrelude + `
+local removed = redis.call('HDEL', KEYS[2], ARGV[1])
+` + tempDataTouch + `
+return removed
+`)
+)
+
+func tempUserKey(user_id uint64) string {
+	return fmt.Sprintf("user:%d", user_id)
+}
+
+func tempCartKey(user_id uint64) string {
+	return fmt.Sprintf("cart:%d", user_id)
+}
+
+func (c *RedisController) runCartScript(script *redis.Script, user_id uint64, args ...interface{}) (interface{}, errors.PCCError) {
+	res, err := script.Run(context.Background(), c.client, []string{tempUserKey(user_id), tempCartKey(user_id)}, args...).Result()
+
+	if err != nil {
+		return nil, rerrors.RedisErrorCaster(err)
+	}
+
+	if code, ok := res.(int64); ok && code == tempUserExpired {
+		return nil, rerrors.NewRedisErrorTempUserExpired()
+	}
+
+	return res, nil
+}
+
+// GetCart returns the items of the temporary cart ordered by the product ID
+func (c *RedisController) GetCart(user_id uint64) ([]models.TempCartItem, errors.PCCError) {
+	res, err := c.runCartScript(getTempCartScript, user_id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTempCart(res)
+}
+
+// TakeCart returns the items of the temporary cart and deletes it at once, so the
+// cart is never merged twice by the concurrent logins
+func (c *RedisController) TakeCart(user_id uint64) ([]models.TempCartItem, errors.PCCError) {
+	res, err := c.runCartScript(takeTempCartScript, user_id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTempCart(res)
+}
+
+// RestoreCart puts the taken items back if they were not merged. The quantities
+// are added to the items put into the cart in the meantime
+func (c *RedisController) RestoreCart(user_id uint64, items []models.TempCartItem) errors.PCCError {
+	for _, item := range items {
+		if _, err := c.runCartScript(addToTempCartScript, user_id, item.ProductID, item.Quantity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseTempCart converts the HGETALL reply into the items ordered by the product ID
+func parseTempCart(res interface{}) ([]models.TempCartItem, errors.PCCError) {
+	pairs, ok := res.([]interface{})
+
+	if !ok || len(pairs)%2 != 0 {
+		return nil, rerrors.NewRedisErrorWrongValue()
+	}
+
+	cart := make([]models.TempCartItem, 0, len(pairs)/2)
+
+	for i := 0; i < len(pairs); i += 2 {
+		field, _ := pairs[i].(string)
+		value, _ := pairs[i+1].(string)
+
+		product_id, perr := strconv.ParseUint(field, 10, 64)
+
+		if perr != nil {
+			return nil, rerrors.NewRedisErrorWrongValue()
+		}
+
+		quantity, perr := strconv.ParseUint(value, 10, 64)
+
+		if perr != nil {
+			return nil, rerrors.NewRedisErrorWrongValue()
+		}
+
+		cart = append(cart, *models.NewTempCartItem(product_id, uint(quantity)))
+	}
+
+	sort.Slice(cart, func(i, j int) bool {
+		return cart[i].ProductID < cart[j].ProductID
+	})
+
+	return cart, nil
+}
+
+func (c *RedisController) AddToCart(user_id uint64, product_id uint64, quantity uint) (uint64, errors.PCCError) {
+	if _, err := c.runCartScript(addToTempCartScript, user_id, product_id, quantity); err != nil {
+		return IntErrorCode, err
+	}
+
+	return product_id, nil
+}
+
+func (c *RedisController) SetToCart(user_id uint64, product_id uint64, quantity uint) (uint64, errors.PCCError) {
+	if _, err := c.runCartScript(setToTempCartScript, user_id, product_id, quantity); err != nil {
+		return IntErrorCode, err
+	}
+
+	return product_id, nil
+}
+
+func (c *RedisController) ChangeQuantity(user_id uint64, product_id uint64, val int64) (uint64, errors.PCCError) {
+	if _, err := c.runCartScript(changeTempCartQuantityScript, user_id, product_id, val); err != nil {
+		return IntErrorCode, err
+	}
+
+	return product_id, nil
+}
+
+func (c *RedisController) RemoveFromCart(user_id uint64, product_id uint64) (uint64, errors.PCCError) {
+	if _, err := c.runCartScript(removeFromTempCartScript, user_id, product_id); err != nil {
+		return IntErrorCode, err
+	}
+
+	return product_id, nil
+}
@@ -0,0 +1,326 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	gormpostgres "github.com/PC-Core/pc-core-backend/internal/database/gormPostgres"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	// envTestRedis is the address of the local Redis the tests run against. Without it
+	// the tests use the in-memory server
+	envTestRedis = "PCCORE_REDIS_ADDR"
+	// envTestPostgres is the connection string of the database migrated to the latest version
+	envTestPostgres = "PCCORE_POSTGRES_CONN"
+
+	testProductID = 7
+	// testOtherProductID is added and removed by the concurrent workers
+	testOtherProductID = 8
+)
+
+func newTestClient(t *testing.T) *redis.Client {
+	t.Helper()
+
+	addr := os.Getenv(envTestRedis)
+
+	if addr == "" {
+		addr = miniredis.RunT(t).Addr()
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: addr, Password: os.Getenv("PCCORE_REDIS_PASSWORD")})
+
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+// newTestTempUser creates the temporary user with the random ID, so the tests don't touch
+// the data of the real temporary users of the local Redis. The user is deleted after the test
+func newTestTempUser(t *testing.T, c *RedisController) uint64 {
+	t.Helper()
+
+	id := 1<<40 + rand.Uint64N(1<<40)
+
+	if err := c.client.Set(context.Background(), tempUserKey(id), "{}", time.Hour).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { c.DeleteTempUser(id) })
+
+	return id
+}
+
+func newTestController(t *testing.T) (*RedisController, uint64) {
+	c := NewRedisController(newTestClient(t))
+
+	return c, newTestTempUser(t, c)
+}
+
+// TestTempCartConcurrentUpdates runs the adds, the removes and the takes of the same
+// temporary cart at once. Every added item must be either taken exactly once or left
+// in the cart
+func TestTempCartConcurrentUpdates(t *testing.T) {
+	c, userID := newTestController(t)
+
+	const (
+		adders   = 20
+		addsEach = 25
+		removers = 5
+		takers   = 5
+	)
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		taken uint64
+		done  = make(chan struct{})
+	)
+
+	for i := 0; i < adders; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < addsEach; j++ {
+				if _, err := c.AddToCart(userID, testProductID, 1); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < removers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < addsEach; j++ {
+				if _, err := c.AddToCart(userID, testOtherProductID, 1); err != nil {
+					t.Error(err)
+					return
+				}
+
+				if _, err := c.RemoveFromCart(userID, testOtherProductID); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+
+	var takersWg sync.WaitGroup
+
+	for i := 0; i < takers; i++ {
+		takersWg.Add(1)
+
+		go func() {
+			defer takersWg.Done()
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				items, err := c.TakeCart(userID)
+
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				mu.Lock()
+
+				for _, item := range items {
+					if item.ProductID == testProductID {
+						taken += uint64(item.Quantity)
+					}
+				}
+
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(done)
+	takersWg.Wait()
+
+	items, err := c.GetCart(userID)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var left uint64
+
+	for _, item := range items {
+		if item.ProductID == testProductID {
+			left += uint64(item.Quantity)
+		}
+	}
+
+	if added := uint64(adders * addsEach); taken+left != added {
+		t.Fatalf("taken %d and left %d items, %d were added", taken, left, added)
+	}
+}
+
+// TestTempCartConcurrentMerge runs the merge path of the login: the temporary carts are
+// taken by the script and merged by MergeCart into the same account, every cart by two
+// logins at once. The account must get every cart once, clamped to the available stock.
+// It needs the database, the local Redis is used if it is configured
+func TestTempCartConcurrentMerge(t *testing.T) {
+	conn := os.Getenv(envTestPostgres)
+
+	if conn == "" {
+		t.Skipf("%s is not set", envTestPostgres)
+	}
+
+	const (
+		tempUsers  = 10
+		itemsEach  = 15
+		stock      = 100
+		reserved   = 10
+		available  = stock - reserved
+		loginsEach = 2
+	)
+
+	db, err := gorm.Open(postgres.Open(conn), &gorm.Config{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dbctrl, err := gormpostgres.NewGormPostgresController(conn)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var userID, productID uint64
+
+	name := fmt.Sprintf("merge-test-%x", rand.Uint32())
+
+	err = db.Raw(`
+		INSERT INTO Users (name, email, passwordhash) VALUES (?, ? || '@example.com', '')
+		RETURNING id`, name, name,
+	).Scan(&userID).Error
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { db.Exec("DELETE FROM Users WHERE id = ?", userID) })
+
+	// The product is created without the stock, so it has no stock movements and can be deleted
+	err = db.Raw(`
+		INSERT INTO Products (name, price, currency, chars_table_name, chars_id)
+		VALUES (?, 100, 'RUB', 'laptopchars', 0)
+		RETURNING id`, name,
+	).Scan(&productID).Error
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM Cart WHERE product_id = ?", productID)
+		db.Exec("DELETE FROM Products WHERE id = ?", productID)
+	})
+
+	if err := db.Exec("UPDATE Products SET stock = ?, reserved = ? WHERE id = ?", stock, reserved, productID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewRedisController(newTestClient(t))
+	temps := make([]uint64, 0, tempUsers)
+
+	for i := 0; i < tempUsers; i++ {
+		temp := newTestTempUser(t, c)
+
+		if _, err := c.AddToCart(temp, productID, itemsEach); err != nil {
+			t.Fatal(err)
+		}
+
+		temps = append(temps, temp)
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		takes  int
+		merged uint64
+	)
+
+	for _, temp := range temps {
+		for i := 0; i < loginsEach; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				items, err := c.TakeCart(temp)
+
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if len(items) == 0 {
+					return
+				}
+
+				conflicts, err := dbctrl.MergeCart(userID, items)
+
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				takes++
+				merged += uint64(items[0].Quantity)
+
+				for _, conflict := range conflicts {
+					merged -= uint64(conflict.Requested - conflict.Merged)
+				}
+			}()
+		}
+	}
+
+	wg.Wait()
+
+	if takes != tempUsers {
+		t.Fatalf("%d carts are taken, expected %d", takes, tempUsers)
+	}
+
+	var quantity uint64
+
+	if err := db.Raw("SELECT quantity FROM Cart WHERE user_id = ? AND product_id = ?", userID, productID).Scan(&quantity).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if quantity != available {
+		t.Fatalf("the account cart has %d items, expected the available %d", quantity, available)
+	}
+
+	if merged != quantity {
+		t.Fatalf("the merges report %d items, the account cart has %d", merged, quantity)
+	}
+}
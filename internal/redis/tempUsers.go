@@ -3,7 +3,6 @@ package redis
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"time"
 
 	"github.com/PC-Core/pc-core-backend/internal/auth"
@@ -70,7 +69,7 @@ func (c *RedisController) CreateTempUser(auth auth.Auth) (interface{}, errors.PC
 		return nil, err
 	}
 
-	rerr := c.client.Set(context.Background(), fmt.Sprintf("user:%d", id), b, dur).Err()
+	rerr := c.client.Set(context.Background(), tempUserKey(id), b, dur).Err()
 
 	if rerr != nil {
 		return nil, rerrors.RedisErrorCaster(rerr)
@@ -79,116 +78,16 @@ func (c *RedisController) CreateTempUser(auth auth.Auth) (interface{}, errors.PC
 	return auth.AuthentificateWithDur(tu, dur, dur)
 }
 
-func (c *RedisController) GetCart(user_id uint64) ([]models.TempCartItem, errors.PCCError) {
-	cartobj := c.client.Get(context.Background(), fmt.Sprintf("cart:%d", user_id))
-
-	err := cartobj.Err()
-
-	if err == redis.Nil {
-		return nil, nil
-	}
-
-	if err != nil {
-		return nil, rerrors.RedisErrorCaster(err)
-	}
-
-	cart := make([]models.TempCartItem, 0)
-
-	err = json.Unmarshal([]byte(cartobj.Val()), &cart)
-
-	if err != nil {
-		return nil, errors.NewJsonUnmarshalError()
-	}
-
-	return cart, nil
-}
-
-func (c *RedisController) CreateCartAndPut(user_id uint64, product_id uint64, quantity uint) (uint64, errors.PCCError) {
-	cart := []models.TempCartItem{
-		*models.NewTempCartItem(product_id, quantity),
-	}
-
-	json_cart, jerr := json.Marshal(cart)
-
-	if jerr != nil {
-		return IntErrorCode, errors.NewJsonMarshalError()
-	}
-
-	ttl, err := c.getUserIDTTL()
+// DeleteTempUser removes the temporary user, his cart and his wishlist. It is called
+// after they were merged into the account of the registered user
+func (c *RedisController) DeleteTempUser(user_id uint64) errors.PCCError {
+	err := c.client.Del(context.Background(), tempUserKey(user_id), tempCartKey(user_id), tempWishlistKey(user_id)).Err()
 
 	if err != nil {
-		return IntErrorCode, err
+		return rerrors.RedisErrorCaster(err)
 	}
 
-	res := c.client.Set(context.Background(), fmt.Sprintf("cart:%d", user_id), string(json_cart), ttl)
-
-	if err := res.Err(); err != nil {
-		return IntErrorCode, nil
-	}
-
-	return product_id, nil
-}
-
-func (c *RedisController) AddToCart(user_id uint64, product_id uint64, quantity uint) (uint64, errors.PCCError) {
-	record := fmt.Sprintf("cart:%d", user_id)
-
-	tu := c.client.Get(context.Background(), record)
-
-	err := tu.Err()
-
-	if err == redis.Nil {
-		return c.CreateCartAndPut(user_id, product_id, quantity)
-	}
-
-	if err != nil {
-		return IntErrorCode, rerrors.RedisErrorCaster(err)
-	}
-
-	var cart []models.TempCartItem
-
-	err = json.Unmarshal([]byte(tu.Val()), &cart)
-
-	if err != nil {
-		return IntErrorCode, errors.NewJsonUnmarshalError()
-	}
-
-	ttl, terr := c.getUserIDTTL()
-
-	if terr != nil {
-		return IntErrorCode, terr
-	}
-
-	if !c.checkCartForCollisionsAndAppend(cart, product_id, quantity) {
-		cart = append(cart, *models.NewTempCartItem(product_id, quantity))
-	}
-
-	newcart, err := json.Marshal(cart)
-
-	if err != nil {
-		return IntErrorCode, errors.NewJsonMarshalError()
-	}
-
-	err = c.client.Set(context.Background(), record, newcart, ttl).Err()
-
-	if err != nil {
-		return IntErrorCode, rerrors.RedisErrorCaster(err)
-	}
-
-	return product_id, nil
-}
-
-func (c *RedisController) appendToCart(item *models.TempCartItem, quantity uint) bool {
-	item.Quantity += quantity
-	return true
-}
-
-func (c *RedisController) checkCartForCollisionsAndAppend(cart []models.TempCartItem, product_id uint64, quantity uint) bool {
-	for i := range cart {
-		if cart[i].ProductID == product_id {
-			return c.appendToCart(&cart[i], quantity)
-		}
-	}
-	return false
+	return nil
 }
 
 func (c *RedisController) getUserIDTTL() (time.Duration, errors.PCCError) {
@@ -0,0 +1,170 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+	"github.com/PC-Core/pc-core-backend/internal/redis/rerrors"
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// tempWishlistPrelude is the common beginning of the wishlist scripts.
+// KEYS[1] is the temporary user record, KEYS[2] is the wishlist hash of
+// the product IDs to the unix time they were added at
+const tempWishlistPrelude = `
+local ttl = redis.call('PTTL', KEYS[1])
+
+if ttl == -2 then
+	return -1
+end
+`
+
+var (
+	getTempWishlistScript = redis.NewScript(tempWishlistPrelude + `
+return redis.call('HGETALL', KEYS[2])
+`)
+
+	// ARGV[1] is the product ID, ARGV[2] is the current unix time. The time of
+	// the product which is already in the wishlist is not changed
+	addToTempWishlistScript = redis.NewScript(tempWishlistPrelude + `
+local added = redis.call('HSETNX', KEYS[2], ARGV[1], ARGV[2])
+` + tempDataTouch + `
+return added
+`)
+
+	// ARGV[1] is the product ID
+	removeFromTempWishlistScript = redis.NewScript(tempWishlistPrelude + `
+local removed = redis.call('HDEL', KEYS[2], ARGV[1])
+` + tempDataTouch + `
+return removed
+`)
+
+	// KEYS[2] is the cart hash, KEYS[3] is the wishlist hash, ARGV[1] is the product ID.
+	// The product is removed from the wishlist and added to the cart in one step, so
+	// it is never lost between them
+	moveTempWishlistItemScript = redis.NewScript(tempCartPrelude + `
+if redis.call('HDEL', KEYS[3], ARGV[1]) == 0 then
+	return 0
+end
+
+redis.call('HINCRBY', KEYS[2], ARGV[1], 1)
+` + tempDataTouch + `
+return 1
+`)
+)
+
+func tempWishlistKey(user_id uint64) string {
+	return fmt.Sprintf("wishlist:%d", user_id)
+}
+
+func (c *RedisController) runWishlistScript(script *redis.Script, user_id uint64, args ...interface{}) (interface{}, errors.PCCError) {
+	res, err := script.Run(context.Background(), c.client, []string{tempUserKey(user_id), tempWishlistKey(user_id)}, args...).Result()
+
+	if err != nil {
+		return nil, rerrors.RedisErrorCaster(err)
+	}
+
+	if code, ok := res.(int64); ok && code == tempUserExpired {
+		return nil, rerrors.NewRedisErrorTempUserExpired()
+	}
+
+	return res, nil
+}
+
+// GetWishlist returns the items of the temporary wishlist, the recently added go first
+func (c *RedisController) GetWishlist(user_id uint64) ([]models.TempWishlistItem, errors.PCCError) {
+	res, err := c.runWishlistScript(getTempWishlistScript, user_id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pairs, ok := res.([]interface{})
+
+	if !ok || len(pairs)%2 != 0 {
+		return nil, rerrors.NewRedisErrorWrongValue()
+	}
+
+	wishlist := make([]models.TempWishlistItem, 0, len(pairs)/2)
+
+	for i := 0; i < len(pairs); i += 2 {
+		field, _ := pairs[i].(string)
+		value, _ := pairs[i+1].(string)
+
+		product_id, perr := strconv.ParseUint(field, 10, 64)
+
+		if perr != nil {
+			return nil, rerrors.NewRedisErrorWrongValue()
+		}
+
+		added_at, perr := strconv.ParseInt(value, 10, 64)
+
+		if perr != nil {
+			return nil, rerrors.NewRedisErrorWrongValue()
+		}
+
+		wishlist = append(wishlist, *models.NewTempWishlistItem(product_id, time.Unix(added_at, 0)))
+	}
+
+	sort.Slice(wishlist, func(i, j int) bool {
+		return wishlist[i].AddedAt.After(wishlist[j].AddedAt)
+	})
+
+	return wishlist, nil
+}
+
+func (c *RedisController) AddToWishlist(user_id uint64, product_id uint64) (uint64, errors.PCCError) {
+	if _, err := c.runWishlistScript(addToTempWishlistScript, user_id, product_id, time.Now().Unix()); err != nil {
+		return IntErrorCode, err
+	}
+
+	return product_id, nil
+}
+
+func (c *RedisController) RemoveFromWishlist(user_id uint64, product_id uint64) errors.PCCError {
+	res, err := c.runWishlistScript(removeFromTempWishlistScript, user_id, product_id)
+
+	if err != nil {
+		return err
+	}
+
+	if removed, _ := res.(int64); removed == 0 {
+		return rerrors.NewRedisErrorNotFound()
+	}
+
+	return nil
+}
+
+// MoveWishlistItemToCart removes the product from the temporary wishlist and adds one item
+// of it to the temporary cart
+func (c *RedisController) MoveWishlistItemToCart(user_id uint64, product_id uint64) errors.PCCError {
+	keys := []string{tempUserKey(user_id), tempCartKey(user_id), tempWishlistKey(user_id)}
+	res, err := moveTempWishlistItemScript.Run(context.Background(), c.client, keys, product_id).Result()
+
+	if err != nil {
+		return rerrors.RedisErrorCaster(err)
+	}
+
+	switch code, _ := res.(int64); code {
+	case tempUserExpired:
+		return rerrors.NewRedisErrorTempUserExpired()
+	case 0:
+		return rerrors.NewRedisErrorNotFound()
+	}
+
+	return nil
+}
+
+// DeleteWishlist removes the temporary wishlist after it was merged into the wishlist of the registered user
+func (c *RedisController) DeleteWishlist(user_id uint64) errors.PCCError {
+	if err := c.client.Del(context.Background(), tempWishlistKey(user_id)).Err(); err != nil {
+		return rerrors.RedisErrorCaster(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/PC-Core/pc-core-backend/internal/errors"
+)
+
+// TestMoveWishlistItemToCart checks that the product is moved only once and
+// only if it is in the wishlist
+func TestMoveWishlistItemToCart(t *testing.T) {
+	c, userID := newTestController(t)
+
+	if err := c.MoveWishlistItemToCart(userID, testProductID); err == nil || err.GetErrorCode() != errors.EC_REDIS_NIL {
+		t.Fatalf("the product which is not in the wishlist is moved: %v", err)
+	}
+
+	if _, err := c.AddToWishlist(userID, testProductID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.MoveWishlistItemToCart(userID, testProductID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.MoveWishlistItemToCart(userID, testProductID); err == nil {
+		t.Fatal("the product is moved twice")
+	}
+
+	cart, err := c.GetCart(userID)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cart) != 1 || cart[0].ProductID != testProductID || cart[0].Quantity != 1 {
+		t.Fatalf("unexpected cart %v", cart)
+	}
+
+	wishlist, err := c.GetWishlist(userID)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(wishlist) != 0 {
+		t.Fatalf("the product is left in the wishlist: %v", wishlist)
+	}
+}
@@ -0,0 +1,50 @@
+package reservations
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/PC-Core/pc-core-backend/internal/database"
+)
+
+// DefaultSweepInterval is used if the checkout config doesn't set the interval
+const DefaultSweepInterval = 30 * time.Second
+
+// Sweeper releases the reservations of the checkouts which were not completed in time
+type Sweeper struct {
+	db       database.DbController
+	interval time.Duration
+}
+
+func NewSweeper(db database.DbController, interval time.Duration) *Sweeper {
+	if interval <= 0 {
+		interval = DefaultSweepInterval
+	}
+
+	return &Sweeper{
+		db, interval,
+	}
+}
+
+// Run releases the expired reservations until the context is cancelled
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		released, err := s.db.ReleaseExpiredReservations()
+
+		if err != nil {
+			log.Printf("reservations: %s", err.Error())
+		} else if released != 0 {
+			log.Printf("reservations: released %d expired reservations", released)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
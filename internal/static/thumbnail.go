@@ -0,0 +1,88 @@
+package static
+
+import (
+	"bytes"
+	goerrors "errors"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+)
+
+const (
+	// ThumbnailSize is the longest side of the thumbnail in pixels
+	ThumbnailSize = 320
+	// MaxImagePixels protects from the small files decoding into huge images
+	MaxImagePixels = 40_000_000
+)
+
+var ErrImageTooLarge = goerrors.New("the image has too many pixels")
+
+// MakeThumbnail scales the PNG, JPEG or GIF image down to fit the square of the size.
+// The transparent parts are drawn over white, the thumbnail is encoded as JPEG
+func MakeThumbnail(data []byte, size int) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Width*cfg.Height > MaxImagePixels {
+		return nil, ErrImageTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	dw, dh := width, height
+
+	if width > size || height > size {
+		if width > height {
+			dw, dh = size, max(1, height*size/width)
+		} else {
+			dw, dh = max(1, width*size/height), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	// Every pixel of the thumbnail is the average of the source pixels it covers
+	for y := 0; y < dh; y++ {
+		sy0 := bounds.Min.Y + y*height/dh
+		sy1 := max(sy0+1, bounds.Min.Y+(y+1)*height/dh)
+
+		for x := 0; x < dw; x++ {
+			sx0 := bounds.Min.X + x*width/dw
+			sx1 := max(sx0+1, bounds.Min.X+(x+1)*width/dw)
+
+			var r, g, b, n uint64
+
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					// The colors are premultiplied by alpha, the rest is white
+					r += uint64(pr + 0xffff - pa)
+					g += uint64(pg + 0xffff - pa)
+					b += uint64(pb + 0xffff - pa)
+					n++
+				}
+			}
+
+			dst.Set(x, y, color.RGBA64{uint16(r / n), uint16(g / n), uint16(b / n), 0xffff})
+		}
+	}
+
+	var out bytes.Buffer
+
+	if err := jpeg.Encode(&out, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+type CheckoutConfig struct {
+	// ReservationTTL is the time the started checkout holds the stock
+	ReservationTTL time.Duration `yaml:"reservationTtl"`
+	// SweepInterval is the interval between the releases of the expired reservations
+	SweepInterval time.Duration `yaml:"sweepInterval"`
+}
@@ -0,0 +1,49 @@
+package config
+
+import "time"
+
+type CommentsConfig struct {
+	// PremoderationPeriod is the age of the account its comments wait for the approval
+	// of the moderators before. Zero disables the pre-moderation
+	PremoderationPeriod time.Duration `yaml:"premoderationPeriod"`
+	// ReviewEditWindow is the period the rated reviews can be edited during. Zero doesn't limit the edits
+	ReviewEditWindow time.Duration       `yaml:"reviewEditWindow"`
+	Filter           ContentFilterConfig `yaml:"filter"`
+}
+
+// ContentFilterConfig configures the checks of the comment texts. Every check has its own
+// verdict: allow, mask, hold or reject. An empty verdict disables the check
+type ContentFilterConfig struct {
+	Enabled    bool                   `yaml:"enabled"`
+	Profanity  ProfanityFilterConfig  `yaml:"profanity"`
+	Links      LinksFilterConfig      `yaml:"links"`
+	Duplicates DuplicatesFilterConfig `yaml:"duplicates"`
+	Flood      FloodFilterConfig      `yaml:"flood"`
+}
+
+type ProfanityFilterConfig struct {
+	// Wordlists are the paths to the wordlist files relative to the working directory
+	Wordlists []string `yaml:"wordlists"`
+	Verdict   string   `yaml:"verdict"`
+}
+
+type LinksFilterConfig struct {
+	MaxLinks  int `yaml:"maxLinks"`
+	MaxPhones int `yaml:"maxPhones"`
+	// AllowedDomains are not counted, e.g. the domain of the shop
+	AllowedDomains []string `yaml:"allowedDomains"`
+	Verdict        string   `yaml:"verdict"`
+}
+
+type DuplicatesFilterConfig struct {
+	Window time.Duration `yaml:"window"`
+	// MinLength is the length of the shortest text that is checked
+	MinLength int    `yaml:"minLength"`
+	Verdict   string `yaml:"verdict"`
+}
+
+type FloodFilterConfig struct {
+	Window      time.Duration `yaml:"window"`
+	MaxComments int           `yaml:"maxComments"`
+	Verdict     string        `yaml:"verdict"`
+}
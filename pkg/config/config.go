@@ -7,12 +7,19 @@ import (
 )
 
 type Config struct {
-	Addr      string   `yaml:"addr"`
-	Port      int      `yaml:"port"`
-	DbDriver  string   `yaml:"dbdriver"`
-	AllowCors []string `yaml:"allowcors"`
-	RedisConn `yaml:"redisConn"`
-	MinIOConn `yaml:"minioConn"`
+	Addr                string   `yaml:"addr"`
+	Port                int      `yaml:"port"`
+	DbDriver            string   `yaml:"dbdriver"`
+	AllowCors           []string `yaml:"allowcors"`
+	RedisConn           `yaml:"redisConn"`
+	MinIOConn           `yaml:"minioConn"`
+	MfaConfig           `yaml:"mfa"`
+	JWTConfig           `yaml:"jwt"`
+	MailConfig          `yaml:"mail"`
+	NotificationsConfig `yaml:"notifications"`
+	CheckoutConfig      `yaml:"checkout"`
+	PriceChangesConfig  `yaml:"priceChanges"`
+	CommentsConfig      `yaml:"comments"`
 }
 
 func ParseConfig(path string) (*Config, error) {
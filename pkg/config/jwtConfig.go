@@ -0,0 +1,20 @@
+package config
+
+type JWTKeyConfig struct {
+	// Kid is put into the `kid` header of the tokens signed with the key
+	Kid string `yaml:"kid"`
+	// Alg is the JWT algorithm name, e.g. EdDSA or RS256
+	Alg string `yaml:"alg"`
+	// PrivateKey is the path to the PEM private key. The keys without the
+	// private part are used only for the verification
+	PrivateKey string `yaml:"privateKey"`
+	// PublicKey is the path to the PEM public key. If omitted, it is derived
+	// from the private key
+	PublicKey string `yaml:"publicKey"`
+}
+
+type JWTConfig struct {
+	// SigningKid is the kid of the key used to sign the new tokens
+	SigningKid string         `yaml:"signingKid"`
+	Keys       []JWTKeyConfig `yaml:"keys"`
+}
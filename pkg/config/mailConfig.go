@@ -0,0 +1,11 @@
+package config
+
+type MailConfig struct {
+	// Host of the SMTP server. If it is empty, the emails are written to the log
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	From     string `yaml:"from"`
+	Username string `yaml:"username"`
+	// VerifyEmailURL is the frontend page receiving the email verification token
+	VerifyEmailURL string `yaml:"verifyEmailUrl"`
+}
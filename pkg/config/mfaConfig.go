@@ -0,0 +1,9 @@
+package config
+
+type MfaConfig struct {
+	// Issuer is shown in the authenticator application
+	Issuer string `yaml:"issuer"`
+	// RequireForAdmin denies the routes requiring a permission for the sessions created
+	// without the second factor check, whatever role grants the permission
+	RequireForAdmin bool `yaml:"requireForAdmin"`
+}
@@ -0,0 +1,16 @@
+package config
+
+import "time"
+
+type NotificationsConfig struct {
+	// PollInterval is the interval between the checks of the notifications outbox
+	PollInterval time.Duration `yaml:"pollInterval"`
+	// BatchSize is the amount of the notifications claimed at once
+	BatchSize int `yaml:"batchSize"`
+	// MaxAttempts is the amount of the delivery attempts before the notification is marked as failed
+	MaxAttempts int `yaml:"maxAttempts"`
+	// UnsubscribeURL is the frontend page receiving the unsubscribe token
+	UnsubscribeURL string `yaml:"unsubscribeUrl"`
+	// WebhookTimeout limits the request to the webhook of the user
+	WebhookTimeout time.Duration `yaml:"webhookTimeout"`
+}
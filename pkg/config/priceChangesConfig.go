@@ -0,0 +1,8 @@
+package config
+
+import "time"
+
+type PriceChangesConfig struct {
+	// ApplyInterval is the interval between the checks of the scheduled price changes
+	ApplyInterval time.Duration `yaml:"applyInterval"`
+}
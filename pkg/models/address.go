@@ -0,0 +1,19 @@
+package models
+
+type Address struct {
+	ID         int64  `json:"id"`
+	Label      string `json:"label"`
+	Recipient  string `json:"recipient"`
+	Phone      string `json:"phone"`
+	Country    string `json:"country"`
+	City       string `json:"city"`
+	Street     string `json:"street"`
+	PostalCode string `json:"postal_code"`
+	IsDefault  bool   `json:"is_default"`
+}
+
+func NewAddress(id int64, label string, recipient string, phone string, country string, city string, street string, postalCode string, isDefault bool) *Address {
+	return &Address{
+		id, label, recipient, phone, country, city, street, postalCode, isDefault,
+	}
+}
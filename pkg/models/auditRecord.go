@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+type AuditAction string
+
+const (
+	AuditUserRoleChanged AuditAction = "user.role_changed"
+	AuditUserDisabled    AuditAction = "user.disabled"
+	AuditUserEnabled     AuditAction = "user.enabled"
+	AuditUserImpersonate AuditAction = "user.impersonate"
+
+	AuditCommentHidden        AuditAction = "comment.hidden"
+	AuditCommentRestored      AuditAction = "comment.restored"
+	AuditUserCommentsBanned   AuditAction = "user.comments_banned"
+	AuditUserCommentsUnbanned AuditAction = "user.comments_unbanned"
+
+	AuditOrderCancelled AuditAction = "order.cancelled"
+)
+
+// AuditRecord is the entry of the log of the actions performed by the staff
+type AuditRecord struct {
+	ID           int64          `json:"id"`
+	ActorID      int            `json:"actor_id"`
+	Action       AuditAction    `json:"action"`
+	TargetUserID *int           `json:"target_user_id"`
+	Details      map[string]any `json:"details"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+func NewAuditRecord(id int64, actorID int, action AuditAction, targetUserID *int, details map[string]any, createdAt time.Time) *AuditRecord {
+	return &AuditRecord{
+		id, actorID, action, targetUserID, details, createdAt,
+	}
+}
@@ -3,10 +3,26 @@ package models
 type Cart struct {
 	UserID uint64     `json:"user_id"`
 	Items  []CartItem `json:"items"`
+	// Subtotal is the sum of the line totals of the available items
+	Subtotal Money `json:"subtotal"`
+	// Discount is the sum of the discounts of the applied promotions
+	Discount Money `json:"discount"`
+	// Total is the subtotal without the discount
+	Total      Money              `json:"total"`
+	Promotions []AppliedPromotion `json:"promotions"`
+	ItemsCount uint               `json:"items_count"`
+	// Currency is the currency of all the amounts of the cart. It is the currency of the first
+	// available item
+	Currency Currency `json:"currency"`
+	// HasIssues is true if any item is unavailable, lacks the stock, changed the price
+	// or is priced in another currency
+	HasIssues bool `json:"has_issues"`
 }
 
 func NewCart(user_id uint64, items []CartItem) *Cart {
+	zero := NewMoney(0, DefaultCurrency)
+
 	return &Cart{
-		user_id, items,
+		user_id, items, zero, zero, zero, []AppliedPromotion{}, 0, DefaultCurrency, false,
 	}
 }
@@ -6,10 +6,27 @@ type CartItem struct {
 	Product  Product   `json:"product"`
 	Quantity uint      `json:"quantity"`
 	AddedAt  time.Time `json:"added_at"`
+	// PriceAtAdd is the price of the product when it was added to the cart.
+	// It is nil for the temporary carts
+	PriceAtAdd *Money `json:"price_at_add"`
+	// LineTotal is the price multiplied by the quantity
+	LineTotal Money `json:"line_total"`
+	// Discount is the sum of the adjustments
+	Discount Money `json:"discount"`
+	// Adjustments are the discounts of the promotions applied to the line
+	Adjustments       []PriceAdjustment `json:"adjustments"`
+	Unavailable       bool              `json:"unavailable"`
+	InsufficientStock bool              `json:"insufficient_stock"`
+	PriceChanged      bool              `json:"price_changed"`
+	// CurrencyMismatch is true if the product is priced in a currency other than the currency
+	// of the cart. The item is not included in the subtotal and can't be checked out
+	CurrencyMismatch bool `json:"currency_mismatch"`
 }
 
 func NewCartItem(product Product, quantity uint, AddedAt time.Time) *CartItem {
+	zero := NewMoney(0, product.Price.Currency)
+
 	return &CartItem{
-		product, quantity, AddedAt,
+		product, quantity, AddedAt, nil, zero, zero, []PriceAdjustment{}, false, false, false, false,
 	}
 }
@@ -0,0 +1,19 @@
+package models
+
+// CartMergeConflict describes the item of the temporary cart
+// which could not be merged into the cart of the user in full
+type CartMergeConflict struct {
+	ProductID uint64 `json:"product_id"`
+	// Requested is the quantity in the temporary cart
+	Requested uint `json:"requested"`
+	// Merged is the quantity actually added to the cart
+	Merged uint `json:"merged"`
+	// Available is the stock of the product which is not reserved. It is 0 for the missing products
+	Available uint64 `json:"available"`
+}
+
+func NewCartMergeConflict(productID uint64, requested uint, merged uint, available uint64) *CartMergeConflict {
+	return &CartMergeConflict{
+		productID, requested, merged, available,
+	}
+}
@@ -19,9 +19,11 @@ type Comment struct {
 	Medias        Medias           `json:"medias"`
 	Reactions     CommentReactions `json:"reactions"`
 	Deleted       bool             `json:"deleted"`
+	// VerifiedPurchase is set for the reviews of the users who have bought the product
+	VerifiedPurchase bool `json:"verified_purchase"`
 }
 
-func NewComment(id int64, user *User, text string, children []Comment, chilren_count uint64, rating *int16, created_at *time.Time, updated_at *time.Time, medias []Media, reactions CommentReactions, deleted bool) *Comment {
+func NewComment(id int64, user *User, text string, children []Comment, chilren_count uint64, rating *int16, created_at *time.Time, updated_at *time.Time, medias []Media, reactions CommentReactions, deleted bool, verifiedPurchase bool) *Comment {
 	return &Comment{
 		id,
 		user,
@@ -34,5 +36,6 @@ func NewComment(id int64, user *User, text string, children []Comment, chilren_c
 		medias,
 		reactions,
 		deleted,
+		verifiedPurchase,
 	}
 }
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "equal"
+	DiffInsert DiffOp = "insert"
+	DiffDelete DiffOp = "delete"
+)
+
+// DiffChunk is the part of the text which is kept, added or removed by the edit
+type DiffChunk struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// CommentRevision is the version of the comment. Diff is the change from the previous version
+type CommentRevision struct {
+	Text      string      `json:"text"`
+	Rating    *int16      `json:"rating"`
+	WrittenAt time.Time   `json:"written_at"`
+	Diff      []DiffChunk `json:"diff,omitempty"`
+}
+
+func NewCommentRevision(text string, rating *int16, writtenAt time.Time, diff []DiffChunk) *CommentRevision {
+	return &CommentRevision{
+		text, rating, writtenAt, diff,
+	}
+}
+
+// CommentHistory tells whether the comment was edited. The revisions are shown to the moderators only,
+// the oldest goes first and the last one is the current text
+type CommentHistory struct {
+	CommentID    int64             `json:"comment_id"`
+	Edited       bool              `json:"edited"`
+	Edits        uint64            `json:"edits"`
+	LastEditedAt *time.Time        `json:"last_edited_at"`
+	Revisions    []CommentRevision `json:"revisions,omitempty"`
+}
+
+func NewCommentHistory(commentID int64, edits uint64, lastEditedAt *time.Time, revisions []CommentRevision) *CommentHistory {
+	return &CommentHistory{
+		commentID, edits > 0, edits, lastEditedAt, revisions,
+	}
+}
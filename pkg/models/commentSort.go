@@ -0,0 +1,14 @@
+package models
+
+type CommentSort string
+
+const (
+	CommentSortNewest CommentSort = "newest"
+	CommentSortOldest CommentSort = "oldest"
+	// CommentSortHelpful ranks the comments by the Wilson score of their likes and dislikes
+	CommentSortHelpful    CommentSort = "helpful"
+	CommentSortRatingHigh CommentSort = "rating_high"
+	CommentSortRatingLow  CommentSort = "rating_low"
+	// CommentSortWithMedia shows the comments with the medias first
+	CommentSortWithMedia CommentSort = "with_media"
+)
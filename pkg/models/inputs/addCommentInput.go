@@ -1,8 +1,19 @@
 package inputs
 
+// AddCommentInput creates the review when the rating is set on the root comment.
+// The user has at most one review of the product. The text is at most 5000 characters
 type AddCommentInput struct {
-	Text   string `json:"text"`
-	Rating *int16 `json:"rating"`
-	//Medias    models.Medias `json:"medias"`
-	Answer *int64 `json:"answer"`
+	Text   string `json:"text" binding:"max=5000"`
+	Rating *int16 `json:"rating" binding:"omitempty,min=0,max=100"`
+	// Medias are the IDs of the images uploaded by the user, at most 5
+	Medias []int64 `json:"medias" binding:"omitempty,max=5,unique"`
+	Answer *int64  `json:"answer"`
+}
+
+// EditCommentInput changes the text and the rating. The rating is kept if it is not set.
+// The medias are kept if they are not set and removed if the list is empty
+type EditCommentInput struct {
+	Text   string  `json:"text" binding:"required,max=5000"`
+	Rating *int16  `json:"rating" binding:"omitempty,min=0,max=100"`
+	Medias []int64 `json:"medias" binding:"omitempty,max=5,unique"`
 }
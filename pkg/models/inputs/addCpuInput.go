@@ -4,7 +4,7 @@ import "github.com/PC-Core/pc-core-backend/pkg/models"
 
 type AddCpuInput struct {
 	Name         string              `json:"name"`
-	Price        float64             `json:"price"`
+	Price        models.Money        `json:"price"`
 	Stock        uint64              `json:"stock"`
 	CpuName      string              `json:"cpu_name"`
 	PCores       uint64              `json:"pcores"`
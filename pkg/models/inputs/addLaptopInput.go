@@ -7,7 +7,7 @@ type AddLaptopInput struct {
 	CpuID  uint64              `json:"cpu"`
 	Ram    int16               `json:"ram"`
 	GpuID  uint64              `json:"gpu"`
-	Price  float64             `json:"price"`
+	Price  models.Money        `json:"price"`
 	Stock  uint64              `json:"stock"`
 	Medias []models.InputMedia `json:"medias"`
 }
@@ -4,7 +4,7 @@ import "github.com/PC-Core/pc-core-backend/pkg/models"
 
 type AddMouseInput struct {
 	ID          uint64              `json:"id"`
-	Price       float64             `json:"price"`
+	Price       models.Money        `json:"price"`
 	Name        string              `json:"name"`
 	Stock       uint64              `json:"stock"`
 	TypeMouses  string              `json:"type_mouses"`
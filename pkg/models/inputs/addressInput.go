@@ -0,0 +1,12 @@
+package inputs
+
+type AddressInput struct {
+	Label      string `json:"label"`
+	Recipient  string `json:"recipient" binding:"required"`
+	Phone      string `json:"phone" binding:"required"`
+	Country    string `json:"country" binding:"required"`
+	City       string `json:"city" binding:"required"`
+	Street     string `json:"street" binding:"required"`
+	PostalCode string `json:"postal_code" binding:"required"`
+	IsDefault  bool   `json:"is_default"`
+}
@@ -0,0 +1,16 @@
+package inputs
+
+import "github.com/PC-Core/pc-core-backend/pkg/models"
+
+type GetOrdersInput struct {
+	Page  uint64 `json:"page" form:"page" binding:"required"`
+	Count uint64 `json:"count" form:"count" binding:"required"`
+}
+
+// GetAdminOrdersInput filters the orders of all the users
+type GetAdminOrdersInput struct {
+	Page   uint64              `json:"page" form:"page" binding:"required"`
+	Count  uint64              `json:"count" form:"count" binding:"required"`
+	UserID *int                `json:"user_id" form:"user_id"`
+	Status *models.OrderStatus `json:"status" form:"status" binding:"omitempty,oneof=pending completed cancelled expired"`
+}
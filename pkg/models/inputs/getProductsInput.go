@@ -1,6 +1,12 @@
 package inputs
 
+import "github.com/PC-Core/pc-core-backend/pkg/models"
+
 type GetProductsInput struct {
 	Page  uint64 `json:"page" form:"page"`
 	Count uint64 `json:"count" form:"count"`
+	// Sort is id by default
+	Sort models.ProductSort `json:"sort" form:"sort" binding:"omitempty,oneof=id rating_high rating_low most_rated"`
+	// MinRating keeps only the rated products with the average rating from 0 to 100 not below it
+	MinRating *float64 `json:"min_rating" form:"min_rating" binding:"omitempty,min=0,max=100"`
 }
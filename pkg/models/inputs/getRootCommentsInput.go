@@ -1,6 +1,13 @@
 package inputs
 
+import "github.com/PC-Core/pc-core-backend/pkg/models"
+
 type GetRootCommentsInput struct {
 	Limit  int `json:"limit" form:"limit" binding:"required"`
 	Offset int `json:"offset" form:"offset"`
+	// Sort is newest by default
+	Sort models.CommentSort `json:"sort" form:"sort" binding:"omitempty,oneof=newest oldest helpful rating_high rating_low with_media"`
+	// MinStars and MaxStars keep only the reviews rated in the range of the stars
+	MinStars *int `json:"min_stars" form:"min_stars" binding:"omitempty,min=1,max=5"`
+	MaxStars *int `json:"max_stars" form:"max_stars" binding:"omitempty,min=1,max=5"`
 }
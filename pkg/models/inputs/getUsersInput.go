@@ -0,0 +1,12 @@
+package inputs
+
+import "github.com/PC-Core/pc-core-backend/pkg/models"
+
+type GetUsersInput struct {
+	// Role filters the users by the exact role
+	Role models.UserRole `json:"role" form:"role"`
+	// Email filters the users which email contains the string
+	Email string `json:"email" form:"email"`
+	Page  uint64 `json:"page" form:"page" binding:"required"`
+	Count uint64 `json:"count" form:"count" binding:"required"`
+}
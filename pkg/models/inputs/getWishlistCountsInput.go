@@ -0,0 +1,6 @@
+package inputs
+
+type GetWishlistCountsInput struct {
+	Page  uint64 `json:"page" form:"page" binding:"required"`
+	Count uint64 `json:"count" form:"count" binding:"required"`
+}
@@ -0,0 +1,46 @@
+package inputs
+
+import "github.com/PC-Core/pc-core-backend/pkg/models"
+
+type WarehouseInput struct {
+	Name    string               `json:"name" binding:"required"`
+	Kind    models.WarehouseKind `json:"kind" binding:"required,oneof=warehouse pickup_point"`
+	Address string               `json:"address"`
+	Active  bool                 `json:"active"`
+}
+
+type ReceiptItemInput struct {
+	ProductID uint64 `json:"product_id" binding:"required"`
+	Quantity  uint64 `json:"quantity" binding:"required,min=1"`
+}
+
+// GoodsReceiptInput records the goods received from the supplier into the warehouse
+type GoodsReceiptInput struct {
+	WarehouseID int                `json:"warehouse_id" binding:"required"`
+	Supplier    string             `json:"supplier" binding:"required"`
+	Note        *string            `json:"note"`
+	Items       []ReceiptItemInput `json:"items" binding:"required,min=1,dive"`
+}
+
+type StockTransferInput struct {
+	FromWarehouseID int     `json:"from_warehouse_id" binding:"required"`
+	ToWarehouseID   int     `json:"to_warehouse_id" binding:"required,nefield=FromWarehouseID"`
+	ProductID       uint64  `json:"product_id" binding:"required"`
+	Quantity        uint64  `json:"quantity" binding:"required,min=1"`
+	Note            *string `json:"note"`
+}
+
+type WriteOffInput struct {
+	WarehouseID int    `json:"warehouse_id" binding:"required"`
+	ProductID   uint64 `json:"product_id" binding:"required"`
+	Quantity    uint64 `json:"quantity" binding:"required,min=1"`
+	// Note is the reason of the write-off
+	Note string `json:"note" binding:"required"`
+}
+
+type GetStockMovementsInput struct {
+	ProductID   *uint64 `json:"product_id" form:"product_id"`
+	WarehouseID *int    `json:"warehouse_id" form:"warehouse_id"`
+	Page        uint64  `json:"page" form:"page" binding:"required"`
+	Count       uint64  `json:"count" form:"count" binding:"required"`
+}
@@ -0,0 +1,8 @@
+package inputs
+
+type LoginMfaInput struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	MfaCodeInput
+	Remember *bool `json:"remember"`
+	TempCartInput
+}
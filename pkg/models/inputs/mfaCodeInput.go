@@ -0,0 +1,8 @@
+package inputs
+
+// MfaCodeInput contains the second factor. Either the TOTP code
+// or the one-time recovery code should be provided
+type MfaCodeInput struct {
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
@@ -0,0 +1,18 @@
+package inputs
+
+import "github.com/PC-Core/pc-core-backend/pkg/models"
+
+type ReportCommentInput struct {
+	Reason models.ReportReason `json:"reason" binding:"required,oneof=spam abuse off_topic illegal other"`
+	Note   *string             `json:"note"`
+}
+
+// ModerationActionInput is the reason of the action saved in the audit log
+type ModerationActionInput struct {
+	Reason *string `json:"reason"`
+}
+
+type GetModerationQueueInput struct {
+	Page  uint64 `json:"page" form:"page" binding:"required"`
+	Count uint64 `json:"count" form:"count" binding:"required"`
+}
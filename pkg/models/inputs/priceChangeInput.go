@@ -0,0 +1,28 @@
+package inputs
+
+import (
+	"time"
+
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+)
+
+type ScheduledPriceChangeInput struct {
+	ProductID uint64 `json:"product_id" binding:"required"`
+	// Price must be in the currency of the product
+	Price   *models.Money `json:"price" binding:"required"`
+	ApplyAt time.Time     `json:"apply_at" binding:"required"`
+	Note    *string       `json:"note"`
+}
+
+type GetScheduledPriceChangesInput struct {
+	ProductID *uint64                            `json:"product_id" form:"product_id"`
+	Status    *models.ScheduledPriceChangeStatus `json:"status" form:"status" binding:"omitempty,oneof=pending applied cancelled"`
+	Page      uint64                             `json:"page" form:"page" binding:"required"`
+	Count     uint64                             `json:"count" form:"count" binding:"required"`
+}
+
+// GetPriceHistoryInput limits the history to the period. The zero bounds are not applied
+type GetPriceHistoryInput struct {
+	From time.Time `json:"from" form:"from"`
+	To   time.Time `json:"to" form:"to"`
+}
@@ -0,0 +1,39 @@
+package inputs
+
+import (
+	"time"
+
+	"github.com/PC-Core/pc-core-backend/pkg/models"
+)
+
+type PromotionInput struct {
+	// Code is case-insensitive and is saved in the upper case
+	Code    string               `json:"code" binding:"required"`
+	Title   string               `json:"title" binding:"required"`
+	Kind    models.PromotionKind `json:"kind" binding:"required,oneof=percent fixed"`
+	Percent uint                 `json:"percent" binding:"max=100"`
+	// Amount is required for the fixed promotions. Its currency is also used for MinCart
+	Amount       *models.Money         `json:"amount"`
+	Scope        models.PromotionScope `json:"scope" binding:"required,oneof=cart products categories chars_tables"`
+	ProductIDs   []uint64              `json:"product_ids"`
+	CategoryIDs  []uint64              `json:"category_ids"`
+	CharsTables  []string              `json:"chars_tables" binding:"dive,oneof=LaptopChars CpuChars GpuChars KeyboardChars MouseChars"`
+	MinCart      *models.Money         `json:"min_cart"`
+	UsageLimit   *uint                 `json:"usage_limit" binding:"omitempty,min=1"`
+	PerUserLimit *uint                 `json:"per_user_limit" binding:"omitempty,min=1"`
+	// StartsAt is the current time if not set
+	StartsAt  *time.Time `json:"starts_at"`
+	EndsAt    *time.Time `json:"ends_at"`
+	Stackable bool       `json:"stackable"`
+	Priority  int        `json:"priority"`
+	Active    bool       `json:"active"`
+}
+
+type GetPromotionsInput struct {
+	Page  uint64 `json:"page" form:"page" binding:"required"`
+	Count uint64 `json:"count" form:"count" binding:"required"`
+}
+
+type ApplyPromoInput struct {
+	Code string `json:"code" binding:"required"`
+}
@@ -0,0 +1,30 @@
+package inputs
+
+import "github.com/PC-Core/pc-core-backend/pkg/models"
+
+type AddQuestionInput struct {
+	Text string `json:"text" binding:"required,max=2000"`
+	// NotifyBy is the channel the answers are notified to, in_app if it is not set
+	NotifyBy models.NotificationChannel `json:"notify_by" binding:"omitempty,oneof=email in_app"`
+}
+
+type AddQuestionAnswerInput struct {
+	Text string `json:"text" binding:"required,max=4000"`
+}
+
+// AcceptAnswerInput marks the answer as accepted, the unset answer removes the mark
+type AcceptAnswerInput struct {
+	AnswerID *int64 `json:"answer_id"`
+}
+
+type GetQuestionsInput struct {
+	// Unanswered lists only the questions without the answers
+	Unanswered bool   `json:"unanswered" form:"unanswered"`
+	Page       uint64 `json:"page" form:"page" binding:"required"`
+	Count      uint64 `json:"count" form:"count" binding:"required,max=100"`
+}
+
+type GetQuestionAnswersInput struct {
+	Page  uint64 `json:"page" form:"page" binding:"required"`
+	Count uint64 `json:"count" form:"count" binding:"required,max=100"`
+}
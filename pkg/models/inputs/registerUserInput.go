@@ -5,4 +5,5 @@ type RegisterUserInput struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
 	Remember *bool  `json:"remember"`
+	TempCartInput
 }
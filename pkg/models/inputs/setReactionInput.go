@@ -3,5 +3,18 @@ package inputs
 import "github.com/PC-Core/pc-core-backend/pkg/models"
 
 type SetReactionInput struct {
-	Type models.ReactionType `json:"type"`
+	Type models.ReactionType `json:"type" binding:"required"`
+}
+
+type GetReactionsInput struct {
+	// Type filters the reactions, all the types are listed if it is not set
+	Type  *models.ReactionType `json:"type" form:"type"`
+	Page  uint64               `json:"page" form:"page" binding:"required"`
+	Count uint64               `json:"count" form:"count" binding:"required,max=100"`
+}
+
+type SetReactionTypeInput struct {
+	Emoji    string `json:"emoji" binding:"required,max=16"`
+	Position int    `json:"position"`
+	Active   bool   `json:"active"`
 }
@@ -0,0 +1,7 @@
+package inputs
+
+import "github.com/PC-Core/pc-core-backend/pkg/models"
+
+type SetUserRoleInput struct {
+	Role models.UserRole `json:"role" binding:"required"`
+}
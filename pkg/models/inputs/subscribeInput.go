@@ -0,0 +1,21 @@
+package inputs
+
+import "github.com/PC-Core/pc-core-backend/pkg/models"
+
+type SubscribeInput struct {
+	Kind models.SubscriptionKind `json:"kind" binding:"required,oneof=back_in_stock price_drop"`
+	// PriceThreshold is required for the price drop subscriptions and must be in the currency of the product
+	PriceThreshold *models.Money              `json:"price_threshold"`
+	Channel        models.NotificationChannel `json:"channel" binding:"required,oneof=email in_app webhook"`
+	// WebhookURL is required for the webhook channel and must use HTTPS
+	WebhookURL *string `json:"webhook_url" binding:"omitempty,url"`
+}
+
+type UnsubscribeInput struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type GetNotificationsInput struct {
+	Page  uint64 `json:"page" form:"page" binding:"required"`
+	Count uint64 `json:"count" form:"count" binding:"required"`
+}
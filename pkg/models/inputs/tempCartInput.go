@@ -0,0 +1,7 @@
+package inputs
+
+// TempCartInput is embedded into the login and the registration inputs.
+// The cart of the temporary user is merged into the account if the token is provided
+type TempCartInput struct {
+	TempToken *string `form:"temp_token" json:"temp_token"`
+}
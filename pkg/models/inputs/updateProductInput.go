@@ -0,0 +1,10 @@
+package inputs
+
+import "github.com/PC-Core/pc-core-backend/pkg/models"
+
+// UpdateProductInput changes only the provided fields. The stock is changed
+// by the stock movements of the inventory
+type UpdateProductInput struct {
+	// Price must be in the currency of the product
+	Price *models.Money `json:"price"`
+}
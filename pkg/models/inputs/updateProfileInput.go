@@ -0,0 +1,10 @@
+package inputs
+
+// UpdateProfileInput contains the fields to change. The missing fields are
+// kept. The password can only be changed with the current password provided
+type UpdateProfileInput struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=30"`
+	Email       *string `json:"email" binding:"omitempty,email"`
+	Password    *string `json:"password" binding:"omitempty,min=1"`
+	OldPassword *string `json:"old_password"`
+}
@@ -0,0 +1,5 @@
+package inputs
+
+type VerifyEmailInput struct {
+	Token string `json:"token" binding:"required"`
+}
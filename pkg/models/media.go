@@ -12,17 +12,19 @@ type Media struct {
 	Url       string    `json:"url"`
 	Type      MediaType `json:"type"`
 	ProductID uint64    `json:"product_id"`
+	// ThumbnailUrl is set on the images attached to the comments
+	ThumbnailUrl *string `json:"thumbnail_url,omitempty"`
 }
 
-func NewMedia(id uint64, url string, t MediaType, prod_id uint64) *Media {
+func NewMedia(id uint64, url string, t MediaType, prod_id uint64, thumbnail *string) *Media {
 	return &Media{
-		id, url, t, prod_id,
+		id, url, t, prod_id, thumbnail,
 	}
 }
 
 func NewMediaFromInput(id uint64, prod_id uint64, input *InputMedia) *Media {
 	return &Media{
-		id, input.Url, input.Type, prod_id,
+		id, input.Url, input.Type, prod_id, nil,
 	}
 }
 
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+type ModerationStatus string
+
+const (
+	ModerationVisible ModerationStatus = "visible"
+	// ModerationPending comments of the new accounts wait for the approval
+	ModerationPending ModerationStatus = "pending"
+	ModerationHidden  ModerationStatus = "hidden"
+)
+
+type ReportReason string
+
+const (
+	ReportSpam     ReportReason = "spam"
+	ReportAbuse    ReportReason = "abuse"
+	ReportOffTopic ReportReason = "off_topic"
+	ReportIllegal  ReportReason = "illegal"
+	ReportOther    ReportReason = "other"
+)
+
+// ModerationQueueItem is the pending comment or the comment with the open reports
+type ModerationQueueItem struct {
+	CommentID int64            `json:"comment_id"`
+	ProductID int64            `json:"product_id"`
+	Author    *User            `json:"author"`
+	Text      string           `json:"text"`
+	Rating    *int16           `json:"rating"`
+	Status    ModerationStatus `json:"status"`
+	CreatedAt time.Time        `json:"created_at"`
+	// Reports is the amount of the open reports
+	Reports        uint64         `json:"reports"`
+	Reasons        []ReportReason `json:"reasons"`
+	LastReportedAt *time.Time     `json:"last_reported_at"`
+	// FilterFlags are the reasons of the content filter to hold or mask the comment
+	FilterFlags []string `json:"filter_flags"`
+}
+
+func NewModerationQueueItem(commentID int64, productID int64, author *User, text string, rating *int16, status ModerationStatus, createdAt time.Time, reports uint64, reasons []ReportReason, lastReportedAt *time.Time, filterFlags []string) *ModerationQueueItem {
+	return &ModerationQueueItem{
+		commentID, productID, author, text, rating, status, createdAt, reports, reasons, lastReportedAt, filterFlags,
+	}
+}
@@ -0,0 +1,319 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Currency is the ISO 4217 code of the currency
+type Currency string
+
+const (
+	CurrencyRUB Currency = "RUB"
+	CurrencyUSD Currency = "USD"
+	CurrencyEUR Currency = "EUR"
+
+	// DefaultCurrency is used when the currency is not specified
+	DefaultCurrency = CurrencyRUB
+)
+
+// MoneyScale is the amount of the minor units digits. All the supported currencies
+// have the hundredth minor unit
+const MoneyScale = 2
+
+const moneyMinorUnits = 100
+
+var (
+	// ErrInvalidMoney is returned when the string is not a decimal with at most
+	// MoneyScale significant digits after the point or the currency is unknown
+	ErrInvalidMoney = errors.New("invalid money value")
+	// ErrMoneyOverflow is returned when the result does not fit into the minor units
+	ErrMoneyOverflow = errors.New("money overflow")
+	// ErrCurrencyMismatch is returned when the values of different currencies are combined
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+func (c Currency) IsValid() bool {
+	switch c {
+	case CurrencyRUB, CurrencyUSD, CurrencyEUR:
+		return true
+	default:
+		return false
+	}
+}
+
+// Money is the exact decimal amount stored in the minor units (kopecks, cents)
+//
+// Money is stored in the numeric columns as the decimal string and is marshalled
+// to JSON as the decimal string "4299.90". The currency is neither stored in the
+// column nor marshalled, so the owner of the value sets it after scanning and
+// shows it in its own currency field
+type Money struct {
+	Minor    int64
+	Currency Currency
+}
+
+func NewMoney(minor int64, currency Currency) Money {
+	return Money{minor, currency}
+}
+
+// ParseMoney parses the decimal string like "-4299.9". The trailing zeros after
+// MoneyScale digits are allowed, so "10.500" is parsed while "10.505" is not
+func ParseMoney(value string, currency Currency) (Money, error) {
+	if !currency.IsValid() {
+		return Money{}, ErrInvalidMoney
+	}
+
+	s := strings.TrimSpace(value)
+	negative := false
+
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+
+	if whole == "" && frac == "" {
+		return Money{}, ErrInvalidMoney
+	}
+
+	if len(frac) > MoneyScale {
+		if strings.Trim(frac[MoneyScale:], "0") != "" {
+			return Money{}, ErrInvalidMoney
+		}
+
+		frac = frac[:MoneyScale]
+	}
+
+	frac += strings.Repeat("0", MoneyScale-len(frac))
+
+	if whole == "" {
+		whole = "0"
+	}
+
+	if !isDigits(whole) || !isDigits(frac) {
+		return Money{}, ErrInvalidMoney
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+
+	if err != nil {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	cents, _ := strconv.ParseInt(frac, 10, 64)
+
+	if units > (math.MaxInt64-cents)/moneyMinorUnits {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	minor := units*moneyMinorUnits + cents
+
+	if negative {
+		minor = -minor
+	}
+
+	return Money{minor, currency}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns the decimal representation with exactly MoneyScale digits after the point
+func (m Money) String() string {
+	minor := m.Minor
+	sign := ""
+
+	if minor < 0 {
+		sign = "-"
+	}
+
+	units := minor / moneyMinorUnits
+	cents := minor % moneyMinorUnits
+
+	if units < 0 {
+		units = -units
+	}
+
+	if cents < 0 {
+		cents = -cents
+	}
+
+	return fmt.Sprintf("%s%d.%0*d", sign, units, MoneyScale, cents)
+}
+
+func (m Money) IsZero() bool {
+	return m.Minor == 0
+}
+
+// In returns the same amount in the given currency. It is used after scanning
+// the amount from the database
+func (m Money) In(currency Currency) Money {
+	return Money{m.Minor, currency}
+}
+
+func (m Money) Equal(other Money) bool {
+	return m.Minor == other.Minor && m.Currency == other.Currency
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	if (other.Minor > 0 && m.Minor > math.MaxInt64-other.Minor) ||
+		(other.Minor < 0 && m.Minor < math.MinInt64-other.Minor) {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return Money{m.Minor + other.Minor, m.Currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if other.Minor == math.MinInt64 {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return m.Add(Money{-other.Minor, other.Currency})
+}
+
+// Mul multiplies the amount by the integer factor like the quantity of the items
+func (m Money) Mul(factor int64) (Money, error) {
+	if m.Minor == 0 || factor == 0 {
+		return Money{0, m.Currency}, nil
+	}
+
+	result := m.Minor * factor
+
+	if result/factor != m.Minor || (m.Minor == -1 && factor == math.MinInt64) {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return Money{result, m.Currency}, nil
+}
+
+// Scan implements sql.Scanner for the numeric columns. The currency is left
+// unset and should be filled with In
+func (m *Money) Scan(src any) error {
+	var value string
+
+	switch v := src.(type) {
+	case nil:
+		*m = Money{}
+		return nil
+	case []byte:
+		value = string(v)
+	case string:
+		value = v
+	case int64:
+		value = strconv.FormatInt(v, 10)
+	case float64:
+		value = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("%w: unsupported type %T", ErrInvalidMoney, src)
+	}
+
+	parsed, err := ParseMoney(value, DefaultCurrency)
+
+	if err != nil {
+		return err
+	}
+
+	m.Minor = parsed.Minor
+
+	return nil
+}
+
+// Value implements driver.Valuer. The amount is sent as the decimal string,
+// so the numeric column gets the exact value
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+type moneyJSON struct {
+	Amount   json.RawMessage `json:"amount"`
+	Currency Currency        `json:"currency"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON accepts the object {"amount": "4299.90", "currency": "RUB"} or the
+// bare amount in the default currency. The amount may be the string or the JSON number,
+// the number is parsed from its literal text, so it is never rounded through float64
+func (m *Money) UnmarshalJSON(data []byte) error {
+	raw := json.RawMessage(data)
+	currency := DefaultCurrency
+
+	if len(data) != 0 && data[0] == '{' {
+		var obj moneyJSON
+
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+
+		raw = obj.Amount
+
+		if obj.Currency != "" {
+			currency = obj.Currency
+		}
+	}
+
+	amount, err := rawAmount(raw)
+
+	if err != nil {
+		return err
+	}
+
+	parsed, err := ParseMoney(amount, currency)
+
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+
+	return nil
+}
+
+func rawAmount(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 {
+		return "", ErrInvalidMoney
+	}
+
+	if raw[0] == '"' {
+		var s string
+
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", err
+		}
+
+		return s, nil
+	}
+
+	var n json.Number
+
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return "", ErrInvalidMoney
+	}
+
+	if strings.ContainsAny(n.String(), "eE") {
+		return "", ErrInvalidMoney
+	}
+
+	return n.String(), nil
+}
@@ -0,0 +1,146 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	cases := []struct {
+		value string
+		minor int64
+		err   error
+	}{
+		{"4299.90", 429990, nil},
+		{"4299.9", 429990, nil},
+		{"-0.01", -1, nil},
+		{"+12", 1200, nil},
+		{".5", 50, nil},
+		{"10.500", 1050, nil},
+		{"10.505", 0, ErrInvalidMoney},
+		{"1e3", 0, ErrInvalidMoney},
+		{"", 0, ErrInvalidMoney},
+		{"92233720368547758.08", 0, ErrMoneyOverflow},
+	}
+
+	for _, c := range cases {
+		money, err := ParseMoney(c.value, CurrencyRUB)
+
+		if !errors.Is(err, c.err) {
+			t.Errorf("ParseMoney(%q): error %v, expected %v", c.value, err, c.err)
+			continue
+		}
+
+		if err == nil && money.Minor != c.minor {
+			t.Errorf("ParseMoney(%q) = %d, expected %d", c.value, money.Minor, c.minor)
+		}
+	}
+
+	if _, err := ParseMoney("1.00", "XXX"); !errors.Is(err, ErrInvalidMoney) {
+		t.Errorf("unknown currency is accepted: %v", err)
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	cases := map[int64]string{
+		0:       "0.00",
+		5:       "0.05",
+		-5:      "-0.05",
+		429990:  "4299.90",
+		-429990: "-4299.90",
+	}
+
+	for minor, expected := range cases {
+		if s := NewMoney(minor, CurrencyRUB).String(); s != expected {
+			t.Errorf("String(%d) = %q, expected %q", minor, s, expected)
+		}
+	}
+}
+
+// TestMoneySum checks the sum which is not exact in float64
+func TestMoneySum(t *testing.T) {
+	sum := NewMoney(0, CurrencyRUB)
+
+	for i := 0; i < 10; i++ {
+		var err error
+
+		if sum, err = sum.Add(NewMoney(10, CurrencyRUB)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if sum.String() != "1.00" {
+		t.Fatalf("10 * 0.10 = %s", sum)
+	}
+
+	if _, err := sum.Add(NewMoney(1, CurrencyUSD)); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("currencies are mixed: %v", err)
+	}
+
+	if _, err := NewMoney(math.MaxInt64, CurrencyRUB).Add(NewMoney(1, CurrencyRUB)); !errors.Is(err, ErrMoneyOverflow) {
+		t.Fatalf("overflow is not detected: %v", err)
+	}
+
+	if _, err := NewMoney(math.MaxInt64/2+1, CurrencyRUB).Mul(2); !errors.Is(err, ErrMoneyOverflow) {
+		t.Fatalf("overflow is not detected: %v", err)
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	data, err := json.Marshal(NewMoney(429990, CurrencyUSD))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `"4299.90"` {
+		t.Fatalf("marshalled as %s", data)
+	}
+
+	product := NewProduct(1, "Laptop", NewMoney(429990, CurrencyUSD), 0, 1, 0, Medias{}, "", 0, nil)
+
+	if data, err = json.Marshal(product); err != nil {
+		t.Fatal(err)
+	}
+
+	var fields map[string]any
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+
+	if fields["price"] != "4299.90" || fields["currency"] != "USD" {
+		t.Fatalf("product price is marshalled as %v %v", fields["price"], fields["currency"])
+	}
+
+	cases := map[string]Money{
+		`"4299.90"`:                             NewMoney(429990, DefaultCurrency),
+		`4299.9`:                                NewMoney(429990, DefaultCurrency),
+		`{"amount": "0.10", "currency": "EUR"}`: NewMoney(10, CurrencyEUR),
+		`{"amount": 19.99, "currency": "USD"}`:  NewMoney(1999, CurrencyUSD),
+		`{"amount": "19.99"}`:                   NewMoney(1999, DefaultCurrency),
+	}
+
+	for input, expected := range cases {
+		var money Money
+
+		if err := json.Unmarshal([]byte(input), &money); err != nil {
+			t.Errorf("Unmarshal(%s): %v", input, err)
+			continue
+		}
+
+		if !money.Equal(expected) {
+			t.Errorf("Unmarshal(%s) = %s %s, expected %s %s", input, money, money.Currency, expected, expected.Currency)
+		}
+	}
+
+	for _, input := range []string{`1e2`, `"abc"`, `{"amount": "1.001"}`, `null`} {
+		var money Money
+
+		if err := json.Unmarshal([]byte(input), &money); err == nil {
+			t.Errorf("Unmarshal(%s) is accepted as %s", input, money)
+		}
+	}
+}
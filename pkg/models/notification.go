@@ -0,0 +1,77 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type NotificationStatus string
+
+const (
+	NotificationPending NotificationStatus = "pending"
+	NotificationSending NotificationStatus = "sending"
+	NotificationSent    NotificationStatus = "sent"
+	NotificationFailed  NotificationStatus = "failed"
+)
+
+// NotificationPayload is the state of the product at the moment of the change.
+// It is built by the database trigger
+type NotificationPayload struct {
+	ProductName string `json:"product_name"`
+	Price       Money  `json:"price"`
+	OldPrice    Money  `json:"old_price"`
+	// Currency is the currency of the prices. The trigger puts it into the prices
+	// themselves, so it is filled after scanning
+	Currency Currency `json:"currency"`
+	Stock    uint64   `json:"stock"`
+	// QuestionID and AnswerID are set for the answers on the product questions
+	QuestionID *int64 `json:"question_id,omitempty"`
+	AnswerID   *int64 `json:"answer_id,omitempty"`
+}
+
+func (p *NotificationPayload) Scan(src any) error {
+	var err error
+
+	switch v := src.(type) {
+	case []byte:
+		err = json.Unmarshal(v, p)
+	case string:
+		err = json.Unmarshal([]byte(v), p)
+	default:
+		return fmt.Errorf("can't scan %T into NotificationPayload", src)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if p.Currency == "" {
+		p.Currency = p.Price.Currency
+	}
+
+	p.Price = p.Price.In(p.Currency)
+	p.OldPrice = p.OldPrice.In(p.Currency)
+
+	return nil
+}
+
+func (p NotificationPayload) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+type Notification struct {
+	ID             int64               `json:"id"`
+	SubscriptionID *int64              `json:"subscription_id"`
+	ProductID      uint64              `json:"product_id"`
+	Kind           SubscriptionKind    `json:"kind"`
+	Channel        NotificationChannel `json:"channel"`
+	Payload        NotificationPayload `json:"payload"`
+	CreatedAt      time.Time           `json:"created_at"`
+	ReadAt         *time.Time          `json:"read_at"`
+	// The delivery data is loaded only for the claimed notifications
+	UserEmail        string  `json:"-"`
+	WebhookURL       *string `json:"-"`
+	UnsubscribeToken *string `json:"-"`
+}
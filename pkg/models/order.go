@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+type OrderStatus string
+
+const (
+	// OrderPending is the checkout in progress holding the reservations of the stock
+	OrderPending   OrderStatus = "pending"
+	OrderCompleted OrderStatus = "completed"
+	OrderCancelled OrderStatus = "cancelled"
+	// OrderExpired is the pending order whose reservations were released by the sweeper
+	OrderExpired OrderStatus = "expired"
+)
+
+type OrderItem struct {
+	ProductID uint64 `json:"product_id"`
+	Name      string `json:"name"`
+	Quantity  uint   `json:"quantity"`
+	// Price is the price of one item at the start of the checkout
+	Price Money `json:"price"`
+	// Discount is the discount of the whole line
+	Discount Money `json:"discount"`
+}
+
+func NewOrderItem(productID uint64, name string, quantity uint, price Money, discount Money) *OrderItem {
+	return &OrderItem{
+		productID, name, quantity, price, discount,
+	}
+}
+
+type Order struct {
+	ID          int64       `json:"id"`
+	UserID      int         `json:"user_id"`
+	Status      OrderStatus `json:"status"`
+	Items       []OrderItem `json:"items"`
+	Subtotal    Money       `json:"subtotal"`
+	Discount    Money       `json:"discount"`
+	Total       Money       `json:"total"`
+	Currency    Currency    `json:"currency"`
+	ExpiresAt   time.Time   `json:"expires_at"`
+	CreatedAt   time.Time   `json:"created_at"`
+	CompletedAt *time.Time  `json:"completed_at"`
+}
+
+func NewOrder(id int64, userID int, status OrderStatus, items []OrderItem, subtotal Money, discount Money, total Money, expiresAt time.Time, createdAt time.Time, completedAt *time.Time) *Order {
+	return &Order{
+		id, userID, status, items, subtotal, discount, total, total.Currency, expiresAt, createdAt, completedAt,
+	}
+}
+
+// StockShortage describes the product which lacks the available stock for the order
+type StockShortage struct {
+	ProductID uint64 `json:"product_id"`
+	Requested uint   `json:"requested"`
+	Available uint64 `json:"available"`
+}
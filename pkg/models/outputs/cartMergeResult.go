@@ -0,0 +1,16 @@
+package outputs
+
+import "github.com/PC-Core/pc-core-backend/pkg/models"
+
+// CartMergeResult is returned on the login and the registration
+// if the cart of the temporary user was merged
+type CartMergeResult struct {
+	MergedItems int                        `json:"merged_items"`
+	Conflicts   []models.CartMergeConflict `json:"conflicts"`
+}
+
+func NewCartMergeResult(mergedItems int, conflicts []models.CartMergeConflict) *CartMergeResult {
+	return &CartMergeResult{
+		mergedItems, conflicts,
+	}
+}
@@ -0,0 +1,17 @@
+package outputs
+
+import "github.com/PC-Core/pc-core-backend/pkg/models"
+
+type GetModerationQueueResult struct {
+	Items  []models.ModerationQueueItem `json:"items"`
+	Amount uint64                       `json:"amount"`
+	Page   uint64                       `json:"page"`
+}
+
+func NewGetModerationQueueResult(items []models.ModerationQueueItem, amount uint64, page uint64) *GetModerationQueueResult {
+	return &GetModerationQueueResult{
+		items,
+		amount,
+		page,
+	}
+}
@@ -0,0 +1,17 @@
+package outputs
+
+import "github.com/PC-Core/pc-core-backend/pkg/models"
+
+type GetNotificationsResult struct {
+	Notifications []models.Notification `json:"notifications"`
+	Amount        uint64                `json:"amount"`
+	Page          uint64                `json:"page"`
+}
+
+func NewGetNotificationsResult(notifications []models.Notification, amount uint64, page uint64) *GetNotificationsResult {
+	return &GetNotificationsResult{
+		notifications,
+		amount,
+		page,
+	}
+}
@@ -0,0 +1,17 @@
+package outputs
+
+import "github.com/PC-Core/pc-core-backend/pkg/models"
+
+type GetOrdersResult struct {
+	Orders []models.Order `json:"orders"`
+	Amount uint64         `json:"amount"`
+	Page   uint64         `json:"page"`
+}
+
+func NewGetOrdersResult(orders []models.Order, amount uint64, page uint64) *GetOrdersResult {
+	return &GetOrdersResult{
+		orders,
+		amount,
+		page,
+	}
+}
@@ -0,0 +1,17 @@
+package outputs
+
+import "github.com/PC-Core/pc-core-backend/pkg/models"
+
+type GetPromotionsResult struct {
+	Promotions []models.Promotion `json:"promotions"`
+	Amount     uint64             `json:"amount"`
+	Page       uint64             `json:"page"`
+}
+
+func NewGetPromotionsResult(promotions []models.Promotion, amount uint64, page uint64) *GetPromotionsResult {
+	return &GetPromotionsResult{
+		promotions,
+		amount,
+		page,
+	}
+}
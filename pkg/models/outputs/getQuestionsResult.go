@@ -0,0 +1,33 @@
+package outputs
+
+import "github.com/PC-Core/pc-core-backend/pkg/models"
+
+type GetQuestionsResult struct {
+	Items  []models.Question `json:"items"`
+	Amount uint64            `json:"amount"`
+	Page   uint64            `json:"page"`
+}
+
+func NewGetQuestionsResult(items []models.Question, amount uint64, page uint64) *GetQuestionsResult {
+	return &GetQuestionsResult{
+		items,
+		amount,
+		page,
+	}
+}
+
+type GetQuestionAnswersResult struct {
+	Question *models.Question        `json:"question"`
+	Items    []models.QuestionAnswer `json:"items"`
+	Amount   uint64                  `json:"amount"`
+	Page     uint64                  `json:"page"`
+}
+
+func NewGetQuestionAnswersResult(question *models.Question, items []models.QuestionAnswer, amount uint64, page uint64) *GetQuestionAnswersResult {
+	return &GetQuestionAnswersResult{
+		question,
+		items,
+		amount,
+		page,
+	}
+}
@@ -0,0 +1,17 @@
+package outputs
+
+import "github.com/PC-Core/pc-core-backend/pkg/models"
+
+type GetReactionsResult struct {
+	Items  []models.Reaction `json:"items"`
+	Amount uint64            `json:"amount"`
+	Page   uint64            `json:"page"`
+}
+
+func NewGetReactionsResult(items []models.Reaction, amount uint64, page uint64) *GetReactionsResult {
+	return &GetReactionsResult{
+		items,
+		amount,
+		page,
+	}
+}
@@ -0,0 +1,17 @@
+package outputs
+
+import "github.com/PC-Core/pc-core-backend/pkg/models"
+
+type GetStockMovementsResult struct {
+	Movements []models.StockMovement `json:"movements"`
+	Amount    uint64                 `json:"amount"`
+	Page      uint64                 `json:"page"`
+}
+
+func NewGetStockMovementsResult(movements []models.StockMovement, amount uint64, page uint64) *GetStockMovementsResult {
+	return &GetStockMovementsResult{
+		movements,
+		amount,
+		page,
+	}
+}
@@ -0,0 +1,17 @@
+package outputs
+
+import "github.com/PC-Core/pc-core-backend/pkg/models"
+
+type GetUsersResult struct {
+	Users  []models.User `json:"users"`
+	Amount uint64        `json:"amount"`
+	Page   uint64        `json:"page"`
+}
+
+func NewGetUsersResult(users []models.User, amount uint64, page uint64) *GetUsersResult {
+	return &GetUsersResult{
+		users,
+		amount,
+		page,
+	}
+}
@@ -0,0 +1,17 @@
+package outputs
+
+import "github.com/PC-Core/pc-core-backend/pkg/models"
+
+// ImpersonationResult contains the access token to view as the user.
+// The refresh token is never issued for the impersonation
+type ImpersonationResult struct {
+	User        *models.PublicUser `json:"user"`
+	AccessToken string             `json:"access_token"`
+	ExpiresIn   int                `json:"expires_in"`
+}
+
+func NewImpersonationResult(user *models.PublicUser, accessToken string, expiresIn int) *ImpersonationResult {
+	return &ImpersonationResult{
+		user, accessToken, expiresIn,
+	}
+}
@@ -8,10 +8,17 @@ type TokensMap map[string]string
 type LoginResult struct {
 	User   *models.PublicUser `json:"user"`
 	Tokens TokensMap          `json:"tokens"`
+	// CartMerge is set if the cart of the temporary user was merged
+	CartMerge *CartMergeResult `json:"cart_merge,omitempty"`
+	// WishlistMerge is set if the wishlist of the temporary user was merged
+	WishlistMerge *WishlistMergeResult `json:"wishlist_merge,omitempty"`
+	// MergeFailed is set if the cart or the wishlist of the temporary user failed to merge.
+	// The temporary user is kept, so the merge is retried on the next login with its token
+	MergeFailed bool `json:"merge_failed,omitempty"`
 }
 
 func NewLoginResult(user *models.PublicUser, tokens TokensMap) *LoginResult {
 	return &LoginResult{
-		user, tokens,
+		user, tokens, nil, nil, false,
 	}
 }
@@ -0,0 +1,14 @@
+package outputs
+
+// MfaChallengeResult is returned from the login instead of the LoginResult
+// when the user has the MFA enabled
+type MfaChallengeResult struct {
+	MfaRequired    bool   `json:"mfa_required"`
+	ChallengeToken string `json:"challenge_token"`
+}
+
+func NewMfaChallengeResult(challenge string) *MfaChallengeResult {
+	return &MfaChallengeResult{
+		true, challenge,
+	}
+}
@@ -0,0 +1,14 @@
+package outputs
+
+// MfaEnrollment contains the data for the authenticator application.
+// ProvisioningURI is expected to be rendered as a QR code on the frontend
+type MfaEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+func NewMfaEnrollment(secret string, uri string) *MfaEnrollment {
+	return &MfaEnrollment{
+		secret, uri,
+	}
+}
@@ -0,0 +1,40 @@
+package outputs
+
+import "github.com/PC-Core/pc-core-backend/pkg/models"
+
+type PriceHistoryResult struct {
+	ProductID uint64       `json:"product_id"`
+	Price     models.Money `json:"price"`
+	// Currency is the currency of the price and all the points
+	Currency models.Currency `json:"currency"`
+	// LowestPrice30d is the lowest price of the 30 days before the latest price change shown
+	// with the discounts. It is nil if the product was never repriced
+	LowestPrice30d *models.Money       `json:"lowest_price_30d"`
+	Points         []models.PricePoint `json:"points"`
+}
+
+func NewPriceHistoryResult(productID uint64, price models.Money, lowestPrice30d *models.Money, entries []models.PriceHistoryEntry) *PriceHistoryResult {
+	points := make([]models.PricePoint, 0, len(entries))
+
+	for _, entry := range entries {
+		points = append(points, models.PricePoint{Price: entry.Price, ChangedAt: entry.ChangedAt})
+	}
+
+	return &PriceHistoryResult{
+		productID, price, price.Currency, lowestPrice30d, points,
+	}
+}
+
+type GetScheduledPriceChangesResult struct {
+	Changes []models.ScheduledPriceChange `json:"changes"`
+	Amount  uint64                        `json:"amount"`
+	Page    uint64                        `json:"page"`
+}
+
+func NewGetScheduledPriceChangesResult(changes []models.ScheduledPriceChange, amount uint64, page uint64) *GetScheduledPriceChangesResult {
+	return &GetScheduledPriceChangesResult{
+		changes,
+		amount,
+		page,
+	}
+}
@@ -5,10 +5,15 @@ import "github.com/PC-Core/pc-core-backend/pkg/models"
 type ProductWithChars = struct {
 	*models.Product `json:"product"`
 	Chars           *RestCharsObject `json:"chars"`
+	// Availability is the stock of the product at the active locations
+	Availability []models.LocationStock `json:"availability"`
+	// LowestPrice30d is the lowest price of the 30 days before the latest price change shown
+	// with the discounts. It is nil if the product was never repriced
+	LowestPrice30d *models.Money `json:"lowest_price_30d"`
 }
 
-func NewProductWithChars(product *models.Product, chars *RestCharsObject) *ProductWithChars {
+func NewProductWithChars(product *models.Product, chars *RestCharsObject, availability []models.LocationStock, lowestPrice30d *models.Money) *ProductWithChars {
 	return &ProductWithChars{
-		product, chars,
+		product, chars, availability, lowestPrice30d,
 	}
 }
@@ -0,0 +1,12 @@
+package outputs
+
+// RecoveryCodes are shown to the user only once, the database keeps the hashes
+type RecoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+func NewRecoveryCodes(codes []string) *RecoveryCodes {
+	return &RecoveryCodes{
+		codes,
+	}
+}
@@ -0,0 +1,41 @@
+package outputs
+
+import "github.com/PC-Core/pc-core-backend/pkg/models"
+
+// WishlistShareResult contains the token of the public wishlist link.
+// The wishlist is available at /wishlist/shared/{token}
+type WishlistShareResult struct {
+	Token string `json:"token"`
+}
+
+func NewWishlistShareResult(token string) *WishlistShareResult {
+	return &WishlistShareResult{
+		token,
+	}
+}
+
+// WishlistMergeResult is returned on the login and the registration
+// if the wishlist of the temporary user was merged
+type WishlistMergeResult struct {
+	MergedItems int `json:"merged_items"`
+}
+
+func NewWishlistMergeResult(mergedItems int) *WishlistMergeResult {
+	return &WishlistMergeResult{
+		mergedItems,
+	}
+}
+
+type GetWishlistCountsResult struct {
+	Products []models.WishlistProductCount `json:"products"`
+	Amount   uint64                        `json:"amount"`
+	Page     uint64                        `json:"page"`
+}
+
+func NewGetWishlistCountsResult(products []models.WishlistProductCount, amount uint64, page uint64) *GetWishlistCountsResult {
+	return &GetWishlistCountsResult{
+		products,
+		amount,
+		page,
+	}
+}
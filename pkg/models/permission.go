@@ -0,0 +1,26 @@
+package models
+
+type Permission string
+
+const (
+	PermissionCatalogWrite     Permission = "catalog:write"
+	PermissionOrdersManage     Permission = "orders:manage"
+	PermissionCommentsModerate Permission = "comments:moderate"
+	PermissionUsersManage      Permission = "users:manage"
+	PermissionRolesAssign      Permission = "roles:assign"
+	PermissionPromotionsManage Permission = "promotions:manage"
+	PermissionInventoryManage  Permission = "inventory:manage"
+)
+
+// Permissions is the set of permissions granted to the user by his role
+type Permissions []Permission
+
+func (p Permissions) Has(required Permission) bool {
+	for _, perm := range p {
+		if perm == required {
+			return true
+		}
+	}
+
+	return false
+}
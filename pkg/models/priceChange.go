@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// PriceHistoryEntry is the price the product had since ChangedAt
+type PriceHistoryEntry struct {
+	ID        int64    `json:"id"`
+	ProductID uint64   `json:"product_id"`
+	Price     Money    `json:"price"`
+	Currency  Currency `json:"currency"`
+	// ChangedBy is not set for the prices changed by the migrations and the seeds
+	ChangedBy *int `json:"changed_by"`
+	// ScheduledChangeID is set when the price was changed by the scheduled change
+	ScheduledChangeID *int64    `json:"scheduled_change_id"`
+	ChangedAt         time.Time `json:"changed_at"`
+}
+
+func NewPriceHistoryEntry(id int64, productID uint64, price Money, changedBy *int, scheduledChangeID *int64, changedAt time.Time) *PriceHistoryEntry {
+	return &PriceHistoryEntry{
+		id, productID, price, price.Currency, changedBy, scheduledChangeID, changedAt,
+	}
+}
+
+// PricePoint is the public part of the price history entry
+type PricePoint struct {
+	Price     Money     `json:"price"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+type ScheduledPriceChangeStatus string
+
+const (
+	ScheduledPriceChangePending   ScheduledPriceChangeStatus = "pending"
+	ScheduledPriceChangeApplied   ScheduledPriceChangeStatus = "applied"
+	ScheduledPriceChangeCancelled ScheduledPriceChangeStatus = "cancelled"
+)
+
+// ScheduledPriceChange is the price change applied by the scheduler at ApplyAt.
+// A sale is scheduled as two changes: the discounted price and the regular one
+type ScheduledPriceChange struct {
+	ID        int64                      `json:"id"`
+	ProductID uint64                     `json:"product_id"`
+	Price     Money                      `json:"price"`
+	Currency  Currency                   `json:"currency"`
+	ApplyAt   time.Time                  `json:"apply_at"`
+	Status    ScheduledPriceChangeStatus `json:"status"`
+	Note      *string                    `json:"note"`
+	CreatedBy *int                       `json:"created_by"`
+	CreatedAt time.Time                  `json:"created_at"`
+	AppliedAt *time.Time                 `json:"applied_at"`
+}
+
+func NewScheduledPriceChange(id int64, productID uint64, price Money, applyAt time.Time, status ScheduledPriceChangeStatus, note *string, createdBy *int, createdAt time.Time, appliedAt *time.Time) *ScheduledPriceChange {
+	return &ScheduledPriceChange{
+		id, productID, price, price.Currency, applyAt, status, note, createdBy, createdAt, appliedAt,
+	}
+}
@@ -1,18 +1,29 @@
 package models
 
 type Product struct {
-	ID            uint64  `json:"id"`
-	Name          string  `json:"name"`
-	Price         float64 `json:"price"`
-	Selled        uint64  `json:"selled"`
-	Stock         uint64  `json:"stock"`
-	Medias        Medias  `json:"medias"`
-	CharTableName string  `json:"-"`
-	CharId        uint64  `json:"-"`
+	ID            uint64         `json:"id"`
+	Name          string         `json:"name"`
+	Price         Money          `json:"price"`
+	Currency      Currency       `json:"currency"`
+	Selled        uint64         `json:"selled"`
+	Stock         uint64         `json:"stock"`
+	Available     uint64         `json:"available"`
+	Medias        Medias         `json:"medias"`
+	CharTableName string         `json:"-"`
+	CharId        uint64         `json:"-"`
+	Rating        *ProductRating `json:"rating,omitempty"`
 }
 
-func NewProduct(id uint64, name string, price float64, selled uint64, stock uint64, medias Medias, charTableName string, charId uint64) *Product {
+// NewProduct creates the product. Its available stock is the stock without the items
+// reserved by the checkouts in progress. The rating is not set when the product is loaded with its chars
+func NewProduct(id uint64, name string, price Money, selled uint64, stock uint64, reserved uint64, medias Medias, charTableName string, charId uint64, rating *ProductRating) *Product {
+	var available uint64
+
+	if stock > reserved {
+		available = stock - reserved
+	}
+
 	return &Product{
-		id, name, price, selled, stock, medias, charTableName, charId,
+		id, name, price, price.Currency, selled, stock, available, medias, charTableName, charId, rating,
 	}
 }
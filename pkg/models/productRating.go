@@ -0,0 +1,26 @@
+package models
+
+// RatingStars is the amount of the stars the ratings are grouped by
+const RatingStars = 5
+
+// ProductRating is the aggregate of the ratings of the root comments of the product
+type ProductRating struct {
+	// Average is from 0 to 100 like the ratings of the comments
+	Average float64 `json:"average"`
+	Count   uint64  `json:"count"`
+	// Histogram[i] is the amount of the ratings with i+1 stars
+	Histogram []uint64 `json:"histogram"`
+	// VerifiedAverage and VerifiedCount include only the verified purchases
+	VerifiedAverage float64 `json:"verified_average"`
+	VerifiedCount   uint64  `json:"verified_count"`
+}
+
+// NewProductRating creates the rating. The missing histogram buckets are filled with zeros
+func NewProductRating(average float64, count uint64, histogram []uint64, verifiedAverage float64, verifiedCount uint64) *ProductRating {
+	buckets := make([]uint64, RatingStars)
+	copy(buckets, histogram)
+
+	return &ProductRating{
+		average, count, buckets, verifiedAverage, verifiedCount,
+	}
+}
@@ -0,0 +1,12 @@
+package models
+
+type ProductSort string
+
+const (
+	ProductSortID ProductSort = "id"
+	// ProductSortRatingHigh and ProductSortRatingLow put the products without the ratings last
+	ProductSortRatingHigh ProductSort = "rating_high"
+	ProductSortRatingLow  ProductSort = "rating_low"
+	// ProductSortMostRated ranks the products by the amount of the ratings
+	ProductSortMostRated ProductSort = "most_rated"
+)
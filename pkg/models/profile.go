@@ -1,11 +1,18 @@
 package models
 
 type Profile struct {
-	User *PublicUser `json:"user"`
+	User      *PublicUser `json:"user"`
+	AvatarURL *string     `json:"avatar_url"`
+	// PendingEmail is the new email waiting for the verification
+	PendingEmail *string   `json:"pending_email"`
+	Addresses    []Address `json:"addresses"`
+	// MfaEnabled is true when the user has the MFA enabled. The session may
+	// still be created without it, see PublicUser.Mfa
+	MfaEnabled bool `json:"mfa_enabled"`
 }
 
-func NewProfile(user *PublicUser) *Profile {
+func NewProfile(user *PublicUser, avatarURL *string, pendingEmail *string, addresses []Address) *Profile {
 	return &Profile{
-		user,
+		user, avatarURL, pendingEmail, addresses, false,
 	}
 }
@@ -0,0 +1,104 @@
+package models
+
+import "time"
+
+type PromotionKind string
+
+const (
+	// PromotionPercent takes the percent off the matching lines
+	PromotionPercent PromotionKind = "percent"
+	// PromotionFixed takes the fixed amount off the matching lines together
+	PromotionFixed PromotionKind = "fixed"
+)
+
+// PromotionScope defines which cart lines the promotion is applied to
+type PromotionScope string
+
+const (
+	PromotionScopeCart        PromotionScope = "cart"
+	PromotionScopeProducts    PromotionScope = "products"
+	PromotionScopeCategories  PromotionScope = "categories"
+	PromotionScopeCharsTables PromotionScope = "chars_tables"
+)
+
+type Promotion struct {
+	ID    int64         `json:"id"`
+	Code  string        `json:"code"`
+	Title string        `json:"title"`
+	Kind  PromotionKind `json:"kind"`
+	// Percent is set for the percent promotions
+	Percent uint `json:"percent,omitempty"`
+	// Amount is set for the fixed promotions
+	Amount *Money `json:"amount,omitempty"`
+	// Currency is the currency of Amount and MinCart
+	Currency    Currency       `json:"currency"`
+	Scope       PromotionScope `json:"scope"`
+	ProductIDs  []uint64       `json:"product_ids"`
+	CategoryIDs []uint64       `json:"category_ids"`
+	CharsTables []string       `json:"chars_tables"`
+	// CategoryCharsTables are the chars tables of the products in CategoryIDs
+	CategoryCharsTables []string `json:"-"`
+	// MinCart is the minimal cart subtotal required to apply the promotion
+	MinCart *Money `json:"min_cart"`
+	// UsageLimit is the total amount of the orders which can use the promotion
+	UsageLimit *uint `json:"usage_limit"`
+	// PerUserLimit is the amount of the orders of one user which can use the promotion
+	PerUserLimit *uint      `json:"per_user_limit"`
+	StartsAt     time.Time  `json:"starts_at"`
+	EndsAt       *time.Time `json:"ends_at"`
+	// Stackable promotions can be combined with each other. Not stackable promotion
+	// is always applied alone
+	Stackable bool `json:"stackable"`
+	// Priority defines the order of application, the lower goes first
+	Priority int  `json:"priority"`
+	Active   bool `json:"active"`
+	// Used is the amount of the orders which used the promotion
+	Used uint `json:"used"`
+	// UsedByUser is the amount of the orders of the current user which used the promotion
+	UsedByUser uint `json:"-"`
+}
+
+// PriceAdjustment is the discount taken off the cart line by the promotion
+type PriceAdjustment struct {
+	PromotionID int64  `json:"promotion_id"`
+	Code        string `json:"code"`
+	Amount      Money  `json:"amount"`
+}
+
+func NewPriceAdjustment(promotionID int64, code string, amount Money) *PriceAdjustment {
+	return &PriceAdjustment{
+		promotionID, code, amount,
+	}
+}
+
+// PromotionRejection is the reason the promotion was not applied to the cart
+type PromotionRejection string
+
+const (
+	PromotionInactive        PromotionRejection = "inactive"
+	PromotionNotStarted      PromotionRejection = "not_started"
+	PromotionExpired         PromotionRejection = "expired"
+	PromotionUsageLimit      PromotionRejection = "usage_limit"
+	PromotionUserLimit       PromotionRejection = "user_limit"
+	PromotionMinCart         PromotionRejection = "min_cart"
+	PromotionCurrency        PromotionRejection = "currency"
+	PromotionNotStackable    PromotionRejection = "not_stackable"
+	PromotionNoMatchingItems PromotionRejection = "no_matching_items"
+)
+
+// AppliedPromotion describes the result of the promotion in the cart. Rejected
+// promotions stay in the cart and are applied again once the reason is gone
+type AppliedPromotion struct {
+	ID       int64              `json:"id"`
+	Code     string             `json:"code"`
+	Title    string             `json:"title"`
+	Discount Money              `json:"discount"`
+	Applied  bool               `json:"applied"`
+	Reason   PromotionRejection `json:"reason,omitempty"`
+}
+
+func NewAppliedPromotion(promotion *Promotion, discount Money, reason PromotionRejection) *AppliedPromotion {
+	return &AppliedPromotion{
+		promotion.ID, promotion.Code, promotion.Title, discount, reason == "", reason,
+	}
+}
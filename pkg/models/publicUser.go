@@ -5,14 +5,24 @@ type PublicUser struct {
 	Name  string   `json:"name"`
 	Email string   `json:"email"`
 	Role  UserRole `json:"role"`
+	// Permissions are granted by the role
+	Permissions Permissions `json:"permissions"`
+	// Mfa is true when the session was created after the second factor check.
+	// It is never set just because the user has the MFA enabled
+	Mfa bool `json:"mfa"`
+	// ImpersonatedBy is the ID of the admin viewing as the user
+	ImpersonatedBy *int `json:"impersonated_by,omitempty"`
 }
 
 func NewPublicUser(id int, name string, email string, role UserRole) *PublicUser {
 	return &PublicUser{
-		id, name, email, role,
+		id, name, email, role, Permissions{}, false, nil,
 	}
 }
 
 func NewPublicUserFromUser(user *User) *PublicUser {
-	return NewPublicUser(user.ID, user.Name, user.Email, user.Role)
+	pu := NewPublicUser(user.ID, user.Name, user.Email, user.Role)
+	pu.Permissions = user.Permissions
+
+	return pu
 }
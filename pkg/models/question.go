@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// QuestionAnswer is the answer on the product question. The answers of the staff and
+// of the users who have bought the product are highlighted
+type QuestionAnswer struct {
+	ID         int64   `json:"id"`
+	QuestionID int64   `json:"question_id"`
+	UserID     int     `json:"user_id"`
+	UserName   string  `json:"user_name"`
+	AvatarURL  *string `json:"avatar_url"`
+	Text       string  `json:"text"`
+	// Staff is set if the author had any permission when the answer was written
+	Staff            bool       `json:"staff"`
+	VerifiedPurchase bool       `json:"verified_purchase"`
+	Accepted         bool       `json:"accepted"`
+	Upvotes          uint64     `json:"upvotes"`
+	YourUpvote       bool       `json:"your_upvote"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        *time.Time `json:"updated_at"`
+}
+
+func NewQuestionAnswer(id int64, questionID int64, userID int, userName string, avatarURL *string, text string, staff bool, verifiedPurchase bool, accepted bool, upvotes uint64, yourUpvote bool, createdAt time.Time, updatedAt *time.Time) *QuestionAnswer {
+	return &QuestionAnswer{
+		id, questionID, userID, userName, avatarURL, text, staff, verifiedPurchase, accepted, upvotes, yourUpvote, createdAt, updatedAt,
+	}
+}
+
+// Question is the question about the product. It is not the review, so it has no rating
+type Question struct {
+	ID           int64   `json:"id"`
+	ProductID    int64   `json:"product_id"`
+	UserID       int     `json:"user_id"`
+	UserName     string  `json:"user_name"`
+	AvatarURL    *string `json:"avatar_url"`
+	Text         string  `json:"text"`
+	AnswersCount uint64  `json:"answers_count"`
+	// AcceptedAnswer is the answer marked by the asker or the moderators
+	AcceptedAnswer *QuestionAnswer `json:"accepted_answer"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      *time.Time      `json:"updated_at"`
+}
+
+func NewQuestion(id int64, productID int64, userID int, userName string, avatarURL *string, text string, answersCount uint64, acceptedAnswer *QuestionAnswer, createdAt time.Time, updatedAt *time.Time) *Question {
+	return &Question{
+		id, productID, userID, userName, avatarURL, text, answersCount, acceptedAnswer, createdAt, updatedAt,
+	}
+}
@@ -1,8 +1,42 @@
 package models
 
+import "time"
+
+// ReactionType is the name of the reaction from the ReactionTypes table
 type ReactionType string
 
 const (
 	REACTION_LIKE    ReactionType = "like"
 	REACTION_DISLIKE ReactionType = "dislike"
 )
+
+// ReactionTypeInfo is the reaction the users can choose. The inactive reactions
+// stay on the comments, but can't be added
+type ReactionTypeInfo struct {
+	Name     ReactionType `json:"name"`
+	Emoji    string       `json:"emoji"`
+	Position int          `json:"position"`
+	Active   bool         `json:"active"`
+}
+
+func NewReactionTypeInfo(name ReactionType, emoji string, position int, active bool) *ReactionTypeInfo {
+	return &ReactionTypeInfo{
+		name, emoji, position, active,
+	}
+}
+
+// Reaction is the reaction of the user in the reactions list of the comment.
+// Only the public fields of the user are shown
+type Reaction struct {
+	UserID    int          `json:"user_id"`
+	UserName  string       `json:"user_name"`
+	AvatarURL *string      `json:"avatar_url"`
+	Type      ReactionType `json:"type"`
+	AddedAt   time.Time    `json:"added_at"`
+}
+
+func NewReaction(userID int, userName string, avatarURL *string, ty ReactionType, addedAt time.Time) *Reaction {
+	return &Reaction{
+		userID, userName, avatarURL, ty, addedAt,
+	}
+}
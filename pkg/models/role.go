@@ -0,0 +1,12 @@
+package models
+
+type Role struct {
+	Name        UserRole    `json:"name"`
+	Permissions Permissions `json:"permissions"`
+}
+
+func NewRole(name UserRole, permissions Permissions) *Role {
+	return &Role{
+		name, permissions,
+	}
+}
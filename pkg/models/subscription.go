@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+type SubscriptionKind string
+
+const (
+	// SubscriptionBackInStock notifies when the stock of the product goes from zero to positive
+	SubscriptionBackInStock SubscriptionKind = "back_in_stock"
+	// SubscriptionPriceDrop notifies when the price drops to the threshold or lower
+	SubscriptionPriceDrop SubscriptionKind = "price_drop"
+	// SubscriptionQuestionAnswered can't be subscribed to, the asker of the product question
+	// is notified about the answers
+	SubscriptionQuestionAnswered SubscriptionKind = "question_answered"
+)
+
+type NotificationChannel string
+
+const (
+	NotificationEmail   NotificationChannel = "email"
+	NotificationInApp   NotificationChannel = "in_app"
+	NotificationWebhook NotificationChannel = "webhook"
+)
+
+type ProductSubscription struct {
+	ID        int64            `json:"id"`
+	ProductID uint64           `json:"product_id"`
+	Kind      SubscriptionKind `json:"kind"`
+	// PriceThreshold is set for the price drop subscriptions
+	PriceThreshold *Money `json:"price_threshold"`
+	// Currency is the currency of the product. It is set for the price drop subscriptions
+	Currency       Currency            `json:"currency,omitempty"`
+	Channel        NotificationChannel `json:"channel"`
+	WebhookURL     *string             `json:"webhook_url"`
+	LastNotifiedAt *time.Time          `json:"last_notified_at"`
+	CreatedAt      time.Time           `json:"created_at"`
+}
+
+func NewProductSubscription(id int64, productID uint64, kind SubscriptionKind, priceThreshold *Money, channel NotificationChannel, webhookURL *string, lastNotifiedAt *time.Time, createdAt time.Time) *ProductSubscription {
+	var currency Currency
+
+	if priceThreshold != nil {
+		currency = priceThreshold.Currency
+	}
+
+	return &ProductSubscription{
+		id, productID, kind, priceThreshold, currency, channel, webhookURL, lastNotifiedAt, createdAt,
+	}
+}
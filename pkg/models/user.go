@@ -6,19 +6,29 @@ const (
 	Temporary UserRole = "Temporary"
 	Default   UserRole = "Default"
 
-	Admin UserRole = "Admin"
+	Admin          UserRole = "Admin"
+	ContentManager UserRole = "ContentManager"
+	Support        UserRole = "Support"
+	Moderator      UserRole = "Moderator"
 )
 
 type User struct {
-	ID         int      `json:"id"`
-	Name       string   `json:"name"`
-	Email      string   `json:"email"`
-	Role       UserRole `json:"user_role"`
-	PasswdHash string   `json:"-"`
+	ID          int         `json:"id"`
+	Name        string      `json:"name"`
+	Email       string      `json:"email"`
+	Role        UserRole    `json:"user_role"`
+	Permissions Permissions `json:"permissions"`
+	PasswdHash  string      `json:"-"`
+	MfaEnabled  bool        `json:"mfa_enabled"`
+	TotpSecret  *string     `json:"-"`
+	Disabled    bool        `json:"disabled"`
+	AvatarURL   *string     `json:"avatar_url"`
+	// CommentsBanned users can't add and edit the comments
+	CommentsBanned bool `json:"comments_banned"`
 }
 
 func NewUser(id int, name string, email string, role UserRole, passwdHash string) *User {
 	return &User{
-		id, name, email, role, passwdHash,
+		id, name, email, role, Permissions{}, passwdHash, false, nil, false, nil, false,
 	}
 }
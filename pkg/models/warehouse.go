@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+type WarehouseKind string
+
+const (
+	WarehouseKindWarehouse   WarehouseKind = "warehouse"
+	WarehouseKindPickupPoint WarehouseKind = "pickup_point"
+)
+
+type Warehouse struct {
+	ID      int           `json:"id"`
+	Name    string        `json:"name"`
+	Kind    WarehouseKind `json:"kind"`
+	Address string        `json:"address"`
+	// IsDefault warehouse receives the initial stock of the new products
+	IsDefault bool `json:"is_default"`
+	Active    bool `json:"active"`
+}
+
+func NewWarehouse(id int, name string, kind WarehouseKind, address string, isDefault bool, active bool) *Warehouse {
+	return &Warehouse{
+		id, name, kind, address, isDefault, active,
+	}
+}
+
+// LocationStock is the stock of the product at the location
+type LocationStock struct {
+	WarehouseID int           `json:"warehouse_id"`
+	Name        string        `json:"name"`
+	Kind        WarehouseKind `json:"kind"`
+	Address     string        `json:"address"`
+	Quantity    uint64        `json:"quantity"`
+}
+
+type StockMovementKind string
+
+const (
+	// StockReceipt is the goods received from the supplier
+	StockReceipt  StockMovementKind = "receipt"
+	StockTransfer StockMovementKind = "transfer"
+	StockWriteOff StockMovementKind = "write_off"
+	// StockSale is the goods taken by the completed order
+	StockSale StockMovementKind = "sale"
+)
+
+// StockMovement is the entry of the append-only ledger the stock is derived from
+type StockMovement struct {
+	ID              int64             `json:"id"`
+	Kind            StockMovementKind `json:"kind"`
+	ProductID       uint64            `json:"product_id"`
+	FromWarehouseID *int              `json:"from_warehouse_id"`
+	ToWarehouseID   *int              `json:"to_warehouse_id"`
+	Quantity        uint64            `json:"quantity"`
+	Supplier        *string           `json:"supplier"`
+	OrderID         *int64            `json:"order_id"`
+	Note            *string           `json:"note"`
+	CreatedBy       *int              `json:"created_by"`
+	CreatedAt       time.Time         `json:"created_at"`
+}
+
+func NewStockMovement(id int64, kind StockMovementKind, productID uint64, fromWarehouseID *int, toWarehouseID *int, quantity uint64, supplier *string, orderID *int64, note *string, createdBy *int, createdAt time.Time) *StockMovement {
+	return &StockMovement{
+		id, kind, productID, fromWarehouseID, toWarehouseID, quantity, supplier, orderID, note, createdBy, createdAt,
+	}
+}
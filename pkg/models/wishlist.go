@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+type WishlistItem struct {
+	Product Product   `json:"product"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+func NewWishlistItem(product Product, addedAt time.Time) *WishlistItem {
+	return &WishlistItem{
+		product, addedAt,
+	}
+}
+
+type Wishlist struct {
+	Items []WishlistItem `json:"items"`
+}
+
+func NewWishlist(items []WishlistItem) *Wishlist {
+	return &Wishlist{
+		items,
+	}
+}
+
+// TempWishlistItem is the wishlist item of the temporary user stored in redis
+type TempWishlistItem struct {
+	ProductID uint64
+	AddedAt   time.Time
+}
+
+func NewTempWishlistItem(productID uint64, addedAt time.Time) *TempWishlistItem {
+	return &TempWishlistItem{
+		productID, addedAt,
+	}
+}
+
+// WishlistProductCount is the amount of the registered users who saved the product
+type WishlistProductCount struct {
+	ProductID uint64 `json:"product_id"`
+	Name      string `json:"name"`
+	Count     uint64 `json:"count"`
+}
@@ -0,0 +1,4 @@
+DROP TABLE RecoveryCodes;
+
+ALTER TABLE Users DROP COLUMN mfa_enabled;
+ALTER TABLE Users DROP COLUMN totp_secret;
@@ -0,0 +1,11 @@
+ALTER TABLE Users ADD COLUMN totp_secret text DEFAULT NULL;
+ALTER TABLE Users ADD COLUMN mfa_enabled boolean NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS RecoveryCodes(
+    id integer GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES Users(id) ON DELETE CASCADE,
+    code_hash text NOT NULL,
+    used_at timestamptz DEFAULT NULL
+);
+
+CREATE INDEX recovery_codes_user_id ON RecoveryCodes(user_id);
@@ -0,0 +1,11 @@
+CREATE TYPE UserRole AS ENUM ('Default', 'Admin');
+
+ALTER TABLE Users DROP CONSTRAINT users_role_fkey;
+UPDATE Users SET Role = 'Default' WHERE Role NOT IN ('Default', 'Admin');
+ALTER TABLE Users ALTER COLUMN Role DROP DEFAULT;
+ALTER TABLE Users ALTER COLUMN Role TYPE UserRole USING Role::UserRole;
+ALTER TABLE Users ALTER COLUMN Role SET DEFAULT 'Default';
+
+DROP TABLE RolePermissions;
+DROP TABLE Roles;
+DROP TABLE Permissions;
@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS Permissions(
+    name text PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS Roles(
+    name text PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS RolePermissions(
+    role text NOT NULL REFERENCES Roles(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    permission text NOT NULL REFERENCES Permissions(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO Permissions (name) VALUES
+    ('catalog:write'),
+    ('orders:manage'),
+    ('comments:moderate'),
+    ('users:manage');
+
+INSERT INTO Roles (name) VALUES
+    ('Default'),
+    ('Admin'),
+    ('ContentManager'),
+    ('Support');
+
+INSERT INTO RolePermissions (role, permission) VALUES
+    ('Admin', 'catalog:write'),
+    ('Admin', 'orders:manage'),
+    ('Admin', 'comments:moderate'),
+    ('Admin', 'users:manage'),
+    ('ContentManager', 'catalog:write'),
+    ('Support', 'orders:manage'),
+    ('Support', 'comments:moderate');
+
+ALTER TABLE Users ALTER COLUMN Role DROP DEFAULT;
+ALTER TABLE Users ALTER COLUMN Role TYPE text USING Role::text;
+ALTER TABLE Users ALTER COLUMN Role SET DEFAULT 'Default';
+ALTER TABLE Users ADD CONSTRAINT users_role_fkey FOREIGN KEY (Role) REFERENCES Roles(name) ON UPDATE CASCADE;
+
+DROP TYPE UserRole;
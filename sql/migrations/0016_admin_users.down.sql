@@ -0,0 +1,6 @@
+DROP TABLE AuditLog;
+
+DELETE FROM RolePermissions WHERE role = 'Support' AND permission = 'users:manage';
+DELETE FROM Permissions WHERE name = 'roles:assign';
+
+ALTER TABLE Users DROP COLUMN disabled;
@@ -0,0 +1,19 @@
+ALTER TABLE Users ADD COLUMN disabled boolean NOT NULL DEFAULT false;
+
+INSERT INTO Permissions (name) VALUES ('roles:assign');
+
+INSERT INTO RolePermissions (role, permission) VALUES
+    ('Admin', 'roles:assign'),
+    ('Support', 'users:manage');
+
+CREATE TABLE IF NOT EXISTS AuditLog(
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    actor_id integer NOT NULL REFERENCES Users(id),
+    action text NOT NULL,
+    target_user_id integer DEFAULT NULL REFERENCES Users(id) ON DELETE SET NULL,
+    details jsonb DEFAULT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX audit_log_target_user_id ON AuditLog(target_user_id);
+CREATE INDEX audit_log_actor_id ON AuditLog(actor_id);
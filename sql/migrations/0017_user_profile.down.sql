@@ -0,0 +1,4 @@
+DROP TABLE UserAddresses;
+DROP TABLE EmailVerifications;
+
+ALTER TABLE Users DROP COLUMN avatar_url;
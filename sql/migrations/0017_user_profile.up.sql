@@ -0,0 +1,28 @@
+ALTER TABLE Users ADD COLUMN avatar_url text DEFAULT NULL;
+
+CREATE TABLE IF NOT EXISTS EmailVerifications(
+    id integer GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES Users(id) ON DELETE CASCADE,
+    email text NOT NULL CHECK (email ~* '^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$'),
+    token_hash text UNIQUE NOT NULL,
+    expires_at timestamptz NOT NULL
+);
+
+CREATE INDEX email_verifications_user_id ON EmailVerifications(user_id);
+
+CREATE TABLE IF NOT EXISTS UserAddresses(
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES Users(id) ON DELETE CASCADE,
+    label text NOT NULL DEFAULT '',
+    recipient text NOT NULL,
+    phone text NOT NULL,
+    country text NOT NULL,
+    city text NOT NULL,
+    street text NOT NULL,
+    postal_code text NOT NULL,
+    is_default boolean NOT NULL DEFAULT false
+);
+
+CREATE INDEX user_addresses_user_id ON UserAddresses(user_id);
+-- Only one default address per user
+CREATE UNIQUE INDEX user_addresses_default ON UserAddresses(user_id) WHERE is_default;
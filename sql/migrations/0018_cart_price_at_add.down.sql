@@ -0,0 +1,4 @@
+DROP TRIGGER set_cart_price_at_add ON Cart;
+DROP FUNCTION set_cart_price_at_add();
+
+ALTER TABLE Cart DROP COLUMN price_at_add;
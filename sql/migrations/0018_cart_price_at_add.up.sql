@@ -0,0 +1,18 @@
+ALTER TABLE Cart ADD COLUMN price_at_add numeric DEFAULT NULL;
+
+UPDATE Cart SET price_at_add = Products.price FROM Products WHERE Products.id = Cart.product_id;
+
+CREATE OR REPLACE FUNCTION set_cart_price_at_add()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.price_at_add IS NULL THEN
+        NEW.price_at_add := (SELECT price FROM Products WHERE id = NEW.product_id);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER set_cart_price_at_add
+BEFORE INSERT ON Cart
+FOR EACH ROW
+EXECUTE FUNCTION set_cart_price_at_add();
@@ -0,0 +1,5 @@
+ALTER TABLE Cart ALTER COLUMN price_at_add TYPE numeric;
+
+ALTER TABLE Products DROP COLUMN currency;
+ALTER TABLE Products DROP CONSTRAINT products_price_non_negative;
+ALTER TABLE Products ALTER COLUMN price TYPE numeric;
@@ -0,0 +1,5 @@
+ALTER TABLE Products ALTER COLUMN price TYPE numeric(14, 2);
+ALTER TABLE Products ADD CONSTRAINT products_price_non_negative CHECK (price >= 0);
+ALTER TABLE Products ADD COLUMN currency char(3) NOT NULL DEFAULT 'RUB' CHECK (currency IN ('RUB', 'USD', 'EUR'));
+
+ALTER TABLE Cart ALTER COLUMN price_at_add TYPE numeric(14, 2);
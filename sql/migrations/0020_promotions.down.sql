@@ -0,0 +1,6 @@
+DELETE FROM RolePermissions WHERE permission = 'promotions:manage';
+DELETE FROM Permissions WHERE name = 'promotions:manage';
+
+DROP TABLE PromotionRedemptions;
+DROP TABLE CartPromotions;
+DROP TABLE Promotions;
@@ -0,0 +1,49 @@
+CREATE TABLE IF NOT EXISTS Promotions(
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    code text NOT NULL CHECK (code = upper(code) AND code <> ''),
+    title text NOT NULL,
+    kind text NOT NULL CHECK (kind IN ('percent', 'fixed')),
+    percent integer NOT NULL DEFAULT 0 CHECK (percent BETWEEN 0 AND 100),
+    amount numeric(14, 2) DEFAULT NULL CHECK (amount > 0),
+    currency char(3) NOT NULL DEFAULT 'RUB' CHECK (currency IN ('RUB', 'USD', 'EUR')),
+    scope text NOT NULL CHECK (scope IN ('cart', 'products', 'categories', 'chars_tables')),
+    product_ids bigint[] NOT NULL DEFAULT '{}',
+    category_ids bigint[] NOT NULL DEFAULT '{}',
+    chars_tables text[] NOT NULL DEFAULT '{}',
+    min_cart numeric(14, 2) DEFAULT NULL CHECK (min_cart >= 0),
+    usage_limit integer DEFAULT NULL CHECK (usage_limit > 0),
+    per_user_limit integer DEFAULT NULL CHECK (per_user_limit > 0),
+    starts_at timestamptz NOT NULL DEFAULT now(),
+    ends_at timestamptz DEFAULT NULL CHECK (ends_at > starts_at),
+    stackable boolean NOT NULL DEFAULT false,
+    priority integer NOT NULL DEFAULT 0,
+    active boolean NOT NULL DEFAULT true,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CHECK ((kind = 'percent' AND percent > 0) OR (kind = 'fixed' AND amount IS NOT NULL))
+);
+
+CREATE UNIQUE INDEX promotions_code ON Promotions(code);
+
+-- The promotions applied to the cart of the user
+CREATE TABLE IF NOT EXISTS CartPromotions(
+    user_id integer NOT NULL REFERENCES Users(id) ON DELETE CASCADE,
+    promotion_id bigint NOT NULL REFERENCES Promotions(id) ON DELETE CASCADE,
+    applied_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, promotion_id)
+);
+
+-- The usages of the promotions in the placed orders
+CREATE TABLE IF NOT EXISTS PromotionRedemptions(
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    promotion_id bigint NOT NULL REFERENCES Promotions(id),
+    user_id integer NOT NULL REFERENCES Users(id) ON DELETE CASCADE,
+    redeemed_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX promotion_redemptions_promotion_user ON PromotionRedemptions(promotion_id, user_id);
+
+INSERT INTO Permissions (name) VALUES ('promotions:manage');
+
+INSERT INTO RolePermissions (role, permission) VALUES
+    ('Admin', 'promotions:manage'),
+    ('ContentManager', 'promotions:manage');
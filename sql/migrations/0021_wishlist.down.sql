@@ -0,0 +1,2 @@
+DROP TABLE WishlistShares;
+DROP TABLE Wishlist;
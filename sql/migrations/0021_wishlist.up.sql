@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS Wishlist(
+    user_id integer NOT NULL REFERENCES Users(id) ON DELETE CASCADE,
+    product_id integer NOT NULL REFERENCES Products(id) ON DELETE CASCADE,
+    added_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, product_id)
+);
+
+CREATE INDEX wishlist_product_id ON Wishlist(product_id);
+
+-- The public links to the wishlists. The link is revoked by deleting the row
+CREATE TABLE IF NOT EXISTS WishlistShares(
+    user_id integer PRIMARY KEY REFERENCES Users(id) ON DELETE CASCADE,
+    token text UNIQUE NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
@@ -0,0 +1,6 @@
+DROP TRIGGER enqueue_product_notifications ON Products;
+DROP FUNCTION enqueue_product_notifications();
+DROP FUNCTION notification_payload(Products, numeric);
+
+DROP TABLE Notifications;
+DROP TABLE ProductSubscriptions;
@@ -0,0 +1,85 @@
+CREATE TABLE IF NOT EXISTS ProductSubscriptions(
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES Users(id) ON DELETE CASCADE,
+    product_id integer NOT NULL REFERENCES Products(id) ON DELETE CASCADE,
+    kind text NOT NULL CHECK (kind IN ('back_in_stock', 'price_drop')),
+    -- price_threshold is in the currency of the product
+    price_threshold numeric(14, 2) DEFAULT NULL CHECK (price_threshold > 0),
+    channel text NOT NULL CHECK (channel IN ('email', 'in_app', 'webhook')),
+    webhook_url text DEFAULT NULL,
+    unsubscribe_token text UNIQUE NOT NULL,
+    last_notified_at timestamptz DEFAULT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (user_id, product_id, kind),
+    CHECK (kind <> 'price_drop' OR price_threshold IS NOT NULL),
+    CHECK (channel <> 'webhook' OR webhook_url IS NOT NULL)
+);
+
+CREATE INDEX product_subscriptions_product_kind ON ProductSubscriptions(product_id, kind);
+
+-- The outbox of the notifications delivered by the dispatcher
+CREATE TABLE IF NOT EXISTS Notifications(
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    subscription_id bigint DEFAULT NULL REFERENCES ProductSubscriptions(id) ON DELETE SET NULL,
+    user_id integer NOT NULL REFERENCES Users(id) ON DELETE CASCADE,
+    product_id integer NOT NULL REFERENCES Products(id) ON DELETE CASCADE,
+    kind text NOT NULL,
+    channel text NOT NULL,
+    payload jsonb NOT NULL,
+    -- dedup_key makes the same event notify the subscription only once
+    dedup_key text UNIQUE NOT NULL,
+    status text NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sending', 'sent', 'failed')),
+    attempts integer NOT NULL DEFAULT 0,
+    last_error text DEFAULT NULL,
+    claimed_at timestamptz DEFAULT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    sent_at timestamptz DEFAULT NULL,
+    read_at timestamptz DEFAULT NULL
+);
+
+CREATE INDEX notifications_pending ON Notifications(id) WHERE status IN ('pending', 'sending');
+CREATE INDEX notifications_user_channel ON Notifications(user_id, channel);
+
+CREATE OR REPLACE FUNCTION notification_payload(product Products, old_price numeric)
+RETURNS jsonb AS $$
+BEGIN
+    RETURN jsonb_build_object(
+        'product_name', product.name,
+        'price', jsonb_build_object('amount', product.price::text, 'currency', product.currency),
+        'old_price', jsonb_build_object('amount', old_price::text, 'currency', product.currency),
+        'stock', product.stock
+    );
+END;
+$$ LANGUAGE plpgsql;
+
+-- The back in stock subscriptions are notified at most once a day, the price drop
+-- subscriptions are notified once for every new price below the threshold
+CREATE OR REPLACE FUNCTION enqueue_product_notifications()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.stock = 0 AND NEW.stock > 0 THEN
+        INSERT INTO Notifications (subscription_id, user_id, product_id, kind, channel, payload, dedup_key)
+        SELECT s.id, s.user_id, NEW.id, s.kind, s.channel, notification_payload(NEW, OLD.price),
+            'back_in_stock:' || s.id || ':' || to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD')
+        FROM ProductSubscriptions s
+        WHERE s.product_id = NEW.id AND s.kind = 'back_in_stock'
+        ON CONFLICT (dedup_key) DO NOTHING;
+    END IF;
+
+    IF NEW.currency = OLD.currency AND NEW.price < OLD.price THEN
+        INSERT INTO Notifications (subscription_id, user_id, product_id, kind, channel, payload, dedup_key)
+        SELECT s.id, s.user_id, NEW.id, s.kind, s.channel, notification_payload(NEW, OLD.price),
+            'price_drop:' || s.id || ':' || NEW.price::text
+        FROM ProductSubscriptions s
+        WHERE s.product_id = NEW.id AND s.kind = 'price_drop' AND NEW.price <= s.price_threshold
+        ON CONFLICT (dedup_key) DO NOTHING;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER enqueue_product_notifications
+AFTER UPDATE OF stock, price ON Products
+FOR EACH ROW
+EXECUTE FUNCTION enqueue_product_notifications();
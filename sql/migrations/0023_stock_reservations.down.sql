@@ -0,0 +1,10 @@
+DROP TRIGGER sync_reserved_stock ON StockReservations;
+DROP FUNCTION sync_reserved_stock();
+
+DROP TABLE StockReservations;
+ALTER TABLE PromotionRedemptions DROP COLUMN order_id;
+DROP TABLE OrderPromotions;
+DROP TABLE OrderItems;
+DROP TABLE Orders;
+
+ALTER TABLE Products DROP COLUMN reserved;
@@ -0,0 +1,66 @@
+-- reserved is the sum of the quantities of the active reservations, so the available stock is stock - reserved
+ALTER TABLE Products ADD COLUMN reserved bigint NOT NULL DEFAULT 0 CHECK (reserved >= 0);
+
+CREATE TABLE IF NOT EXISTS Orders(
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES Users(id) ON DELETE CASCADE,
+    status text NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'completed', 'cancelled', 'expired')),
+    subtotal numeric(14, 2) NOT NULL,
+    discount numeric(14, 2) NOT NULL,
+    total numeric(14, 2) NOT NULL,
+    currency char(3) NOT NULL,
+    -- expires_at is the time the reservations of the pending order are released
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    completed_at timestamptz DEFAULT NULL
+);
+
+-- The user has at most one checkout in progress
+CREATE UNIQUE INDEX orders_user_pending ON Orders(user_id) WHERE status = 'pending';
+CREATE INDEX orders_pending_expires_at ON Orders(expires_at) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS OrderItems(
+    order_id bigint NOT NULL REFERENCES Orders(id) ON DELETE CASCADE,
+    product_id integer NOT NULL REFERENCES Products(id),
+    name text NOT NULL,
+    quantity integer NOT NULL CHECK (quantity > 0),
+    price numeric(14, 2) NOT NULL,
+    discount numeric(14, 2) NOT NULL DEFAULT 0,
+    PRIMARY KEY (order_id, product_id)
+);
+
+-- The promotions applied to the order. They are redeemed when the order is completed
+CREATE TABLE IF NOT EXISTS OrderPromotions(
+    order_id bigint NOT NULL REFERENCES Orders(id) ON DELETE CASCADE,
+    promotion_id bigint NOT NULL REFERENCES Promotions(id),
+    discount numeric(14, 2) NOT NULL,
+    PRIMARY KEY (order_id, promotion_id)
+);
+
+ALTER TABLE PromotionRedemptions ADD COLUMN order_id bigint DEFAULT NULL REFERENCES Orders(id) ON DELETE CASCADE;
+
+-- The reservations live while their order is pending
+CREATE TABLE IF NOT EXISTS StockReservations(
+    order_id bigint NOT NULL REFERENCES Orders(id) ON DELETE CASCADE,
+    product_id integer NOT NULL REFERENCES Products(id) ON DELETE CASCADE,
+    quantity integer NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (order_id, product_id)
+);
+
+CREATE OR REPLACE FUNCTION sync_reserved_stock()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE Products SET reserved = reserved + NEW.quantity WHERE id = NEW.product_id;
+        RETURN NEW;
+    END IF;
+
+    UPDATE Products SET reserved = reserved - OLD.quantity WHERE id = OLD.product_id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER sync_reserved_stock
+AFTER INSERT OR DELETE ON StockReservations
+FOR EACH ROW
+EXECUTE FUNCTION sync_reserved_stock();
@@ -0,0 +1,14 @@
+DELETE FROM RolePermissions WHERE permission = 'inventory:manage';
+DELETE FROM Permissions WHERE name = 'inventory:manage';
+
+DROP TRIGGER receive_initial_stock ON Products;
+DROP FUNCTION receive_initial_stock();
+
+DROP TRIGGER apply_stock_movement ON StockMovements;
+DROP FUNCTION apply_stock_movement();
+DROP TRIGGER stock_movements_append_only ON StockMovements;
+DROP FUNCTION forbid_stock_movement_change();
+
+DROP TABLE StockMovements;
+DROP TABLE WarehouseStock;
+DROP TABLE Warehouses;
@@ -0,0 +1,119 @@
+CREATE TABLE IF NOT EXISTS Warehouses(
+    id integer GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    name text NOT NULL,
+    kind text NOT NULL CHECK (kind IN ('warehouse', 'pickup_point')),
+    address text NOT NULL DEFAULT '',
+    -- The initial stock of the new products is received into the default warehouse
+    is_default boolean NOT NULL DEFAULT false,
+    active boolean NOT NULL DEFAULT true,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX warehouses_default ON Warehouses(is_default) WHERE is_default;
+
+INSERT INTO Warehouses (name, kind, is_default) VALUES ('Main warehouse', 'warehouse', true);
+
+CREATE TABLE IF NOT EXISTS WarehouseStock(
+    warehouse_id integer NOT NULL REFERENCES Warehouses(id),
+    product_id integer NOT NULL REFERENCES Products(id),
+    quantity bigint NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    PRIMARY KEY (warehouse_id, product_id)
+);
+
+-- The append-only ledger of the stock. order_id and created_by have no foreign keys,
+-- so the ledger stays intact when the users and their orders are deleted
+CREATE TABLE IF NOT EXISTS StockMovements(
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    kind text NOT NULL CHECK (kind IN ('receipt', 'transfer', 'write_off', 'sale')),
+    product_id integer NOT NULL REFERENCES Products(id),
+    from_warehouse_id integer DEFAULT NULL REFERENCES Warehouses(id),
+    to_warehouse_id integer DEFAULT NULL REFERENCES Warehouses(id),
+    quantity bigint NOT NULL CHECK (quantity > 0),
+    supplier text DEFAULT NULL,
+    order_id bigint DEFAULT NULL,
+    note text DEFAULT NULL,
+    created_by integer DEFAULT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CHECK (
+        (kind = 'receipt' AND from_warehouse_id IS NULL AND to_warehouse_id IS NOT NULL) OR
+        (kind = 'transfer' AND from_warehouse_id IS NOT NULL AND to_warehouse_id IS NOT NULL AND from_warehouse_id <> to_warehouse_id) OR
+        (kind IN ('write_off', 'sale') AND from_warehouse_id IS NOT NULL AND to_warehouse_id IS NULL)
+    )
+);
+
+CREATE INDEX stock_movements_product ON StockMovements(product_id, id);
+
+CREATE OR REPLACE FUNCTION forbid_stock_movement_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'Stock movements are append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER stock_movements_append_only
+BEFORE UPDATE OR DELETE ON StockMovements
+FOR EACH ROW
+EXECUTE FUNCTION forbid_stock_movement_change();
+
+-- apply_stock_movement moves the quantity between the warehouses and derives the stock
+-- of the product from the sum of its warehouse stock
+CREATE OR REPLACE FUNCTION apply_stock_movement()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.from_warehouse_id IS NOT NULL THEN
+        UPDATE WarehouseStock SET quantity = quantity - NEW.quantity
+        WHERE warehouse_id = NEW.from_warehouse_id AND product_id = NEW.product_id AND quantity >= NEW.quantity;
+
+        IF NOT FOUND THEN
+            RAISE EXCEPTION 'Warehouse stock is insufficient';
+        END IF;
+    END IF;
+
+    IF NEW.to_warehouse_id IS NOT NULL THEN
+        INSERT INTO WarehouseStock (warehouse_id, product_id, quantity)
+        VALUES (NEW.to_warehouse_id, NEW.product_id, NEW.quantity)
+        ON CONFLICT (warehouse_id, product_id) DO UPDATE SET quantity = WarehouseStock.quantity + EXCLUDED.quantity;
+    END IF;
+
+    IF NEW.kind <> 'transfer' THEN
+        UPDATE Products
+        SET stock = (SELECT COALESCE(SUM(quantity), 0) FROM WarehouseStock WHERE product_id = NEW.product_id)
+        WHERE id = NEW.product_id;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER apply_stock_movement
+AFTER INSERT ON StockMovements
+FOR EACH ROW
+EXECUTE FUNCTION apply_stock_movement();
+
+-- The current stock becomes the opening balance of the default warehouse
+INSERT INTO StockMovements (kind, product_id, to_warehouse_id, quantity, note)
+SELECT 'receipt', id, (SELECT id FROM Warehouses WHERE is_default), stock, 'Opening balance'
+FROM Products
+WHERE stock > 0;
+
+CREATE OR REPLACE FUNCTION receive_initial_stock()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO StockMovements (kind, product_id, to_warehouse_id, quantity, note)
+    VALUES ('receipt', NEW.id, (SELECT id FROM Warehouses WHERE is_default), NEW.stock, 'Initial stock');
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER receive_initial_stock
+AFTER INSERT ON Products
+FOR EACH ROW
+WHEN (NEW.stock > 0)
+EXECUTE FUNCTION receive_initial_stock();
+
+INSERT INTO Permissions (name) VALUES ('inventory:manage');
+
+INSERT INTO RolePermissions (role, permission) VALUES
+    ('Admin', 'inventory:manage'),
+    ('ContentManager', 'inventory:manage');
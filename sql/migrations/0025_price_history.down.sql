@@ -0,0 +1,11 @@
+DROP FUNCTION lowest_price(integer, char(3), timestamptz);
+
+DROP TRIGGER record_price_change ON Products;
+DROP TRIGGER record_initial_price ON Products;
+DROP FUNCTION record_price_change();
+
+DROP TRIGGER price_history_append_only ON PriceHistory;
+DROP FUNCTION forbid_price_history_change();
+
+DROP TABLE ScheduledPriceChanges;
+DROP TABLE PriceHistory;
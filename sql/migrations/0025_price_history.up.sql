@@ -0,0 +1,86 @@
+-- The append-only history of the product prices. changed_by has no foreign key,
+-- so the history stays intact when the users are deleted
+CREATE TABLE IF NOT EXISTS PriceHistory(
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    product_id integer NOT NULL REFERENCES Products(id) ON DELETE CASCADE,
+    price numeric(14, 2) NOT NULL,
+    currency char(3) NOT NULL,
+    changed_by integer DEFAULT NULL,
+    scheduled_change_id bigint DEFAULT NULL,
+    changed_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX price_history_product ON PriceHistory(product_id, changed_at);
+
+CREATE TABLE IF NOT EXISTS ScheduledPriceChanges(
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    product_id integer NOT NULL REFERENCES Products(id) ON DELETE CASCADE,
+    -- price is in the currency of the product
+    price numeric(14, 2) NOT NULL CHECK (price >= 0),
+    apply_at timestamptz NOT NULL,
+    status text NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'applied', 'cancelled')),
+    note text DEFAULT NULL,
+    created_by integer DEFAULT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    applied_at timestamptz DEFAULT NULL
+);
+
+CREATE INDEX scheduled_price_changes_due ON ScheduledPriceChanges(apply_at) WHERE status = 'pending';
+
+-- record_price_change writes the new price to the history. The author of the change
+-- is set by the application with set_config('pccore.price_changed_by', ..., true)
+CREATE OR REPLACE FUNCTION record_price_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO PriceHistory (product_id, price, currency, changed_by, scheduled_change_id)
+    VALUES (
+        NEW.id, NEW.price, NEW.currency,
+        NULLIF(current_setting('pccore.price_changed_by', true), '')::integer,
+        NULLIF(current_setting('pccore.price_scheduled_change', true), '')::bigint
+    );
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER record_initial_price
+AFTER INSERT ON Products
+FOR EACH ROW
+EXECUTE FUNCTION record_price_change();
+
+CREATE TRIGGER record_price_change
+AFTER UPDATE OF price, currency ON Products
+FOR EACH ROW
+WHEN (NEW.price IS DISTINCT FROM OLD.price OR NEW.currency IS DISTINCT FROM OLD.currency)
+EXECUTE FUNCTION record_price_change();
+
+CREATE OR REPLACE FUNCTION forbid_price_history_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'Price history is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER price_history_append_only
+BEFORE UPDATE ON PriceHistory
+FOR EACH ROW
+EXECUTE FUNCTION forbid_price_history_change();
+
+-- lowest_price returns the lowest price of the product in the currency since the time.
+-- The price which was in effect at that time is also counted
+CREATE OR REPLACE FUNCTION lowest_price(product integer, cur char(3), since timestamptz)
+RETURNS numeric AS $$
+    SELECT MIN(price) FROM PriceHistory
+    WHERE product_id = product AND currency = cur AND (
+        changed_at >= since OR id = (
+            SELECT id FROM PriceHistory
+            WHERE product_id = product AND changed_at < since
+            ORDER BY changed_at DESC, id DESC
+            LIMIT 1
+        )
+    );
+$$ LANGUAGE sql STABLE;
+
+-- The current prices are the opening entries of the history
+INSERT INTO PriceHistory (product_id, price, currency)
+SELECT id, price, currency FROM Products;
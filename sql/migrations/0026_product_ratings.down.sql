@@ -0,0 +1,6 @@
+DROP TRIGGER sync_product_rating ON Comments;
+DROP FUNCTION sync_product_rating();
+DROP FUNCTION refresh_product_rating(bigint);
+
+DROP TABLE ProductRatings;
+DROP FUNCTION rating_stars(smallint);
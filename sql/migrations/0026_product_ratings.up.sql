@@ -0,0 +1,69 @@
+-- rating_stars maps the rating from 0 to 100 to the stars from 1 to 5
+CREATE OR REPLACE FUNCTION rating_stars(rating smallint)
+RETURNS integer AS $$
+    SELECT GREATEST(1, CEIL(rating / 20.0))::integer;
+$$ LANGUAGE sql IMMUTABLE;
+
+-- The rating aggregates of the products. Only the root comments with the rating are counted
+CREATE TABLE IF NOT EXISTS ProductRatings(
+    product_id integer PRIMARY KEY REFERENCES Products(id) ON DELETE CASCADE,
+    rating_count integer NOT NULL DEFAULT 0,
+    average numeric(5, 2) NOT NULL DEFAULT 0,
+    -- histogram[i] is the amount of the ratings with i stars
+    histogram integer[] NOT NULL DEFAULT '{0, 0, 0, 0, 0}'
+);
+
+CREATE INDEX product_ratings_average ON ProductRatings(average);
+
+CREATE OR REPLACE FUNCTION refresh_product_rating(product bigint)
+RETURNS void AS $$
+    INSERT INTO ProductRatings (product_id, rating_count, average, histogram)
+    SELECT
+        p.id,
+        COUNT(c.rating),
+        COALESCE(ROUND(AVG(c.rating), 2), 0),
+        ARRAY[
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 1),
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 2),
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 3),
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 4),
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 5)
+        ]::integer[]
+    FROM Products p
+    LEFT JOIN Comments c ON c.product_id = p.id
+        AND c.answer_on IS NULL
+        AND c.rating IS NOT NULL
+        AND NOT c.is_deleted
+    WHERE p.id = product
+    GROUP BY p.id
+    ON CONFLICT (product_id) DO UPDATE SET
+        rating_count = EXCLUDED.rating_count,
+        average = EXCLUDED.average,
+        histogram = EXCLUDED.histogram;
+$$ LANGUAGE sql;
+
+CREATE OR REPLACE FUNCTION sync_product_rating()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        PERFORM refresh_product_rating(OLD.product_id);
+    END IF;
+
+    IF TG_OP <> 'DELETE' AND (TG_OP = 'INSERT' OR NEW.product_id <> OLD.product_id) THEN
+        PERFORM refresh_product_rating(NEW.product_id);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER sync_product_rating
+AFTER INSERT OR DELETE OR UPDATE OF rating, answer_on, is_deleted, product_id ON Comments
+FOR EACH ROW
+EXECUTE FUNCTION sync_product_rating();
+
+INSERT INTO ProductRatings (product_id)
+SELECT id FROM Products;
+
+SELECT refresh_product_rating(product_id)
+FROM (SELECT DISTINCT product_id FROM Comments WHERE answer_on IS NULL AND rating IS NOT NULL) rated;
@@ -0,0 +1,55 @@
+DROP TRIGGER sync_product_rating ON Comments;
+
+CREATE TRIGGER sync_product_rating
+AFTER INSERT OR DELETE OR UPDATE OF rating, answer_on, is_deleted, product_id ON Comments
+FOR EACH ROW
+EXECUTE FUNCTION sync_product_rating();
+
+CREATE OR REPLACE FUNCTION refresh_product_rating(product bigint)
+RETURNS void AS $$
+    INSERT INTO ProductRatings (product_id, rating_count, average, histogram)
+    SELECT
+        p.id,
+        COUNT(c.rating),
+        COALESCE(ROUND(AVG(c.rating), 2), 0),
+        ARRAY[
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 1),
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 2),
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 3),
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 4),
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 5)
+        ]::integer[]
+    FROM Products p
+    LEFT JOIN Comments c ON c.product_id = p.id
+        AND c.answer_on IS NULL
+        AND c.rating IS NOT NULL
+        AND NOT c.is_deleted
+    WHERE p.id = product
+    GROUP BY p.id
+    ON CONFLICT (product_id) DO UPDATE SET
+        rating_count = EXCLUDED.rating_count,
+        average = EXCLUDED.average,
+        histogram = EXCLUDED.histogram;
+$$ LANGUAGE sql;
+
+ALTER TABLE ProductRatings DROP COLUMN verified_average;
+ALTER TABLE ProductRatings DROP COLUMN verified_count;
+
+DROP TRIGGER verify_reviews_of_order ON Orders;
+DROP FUNCTION verify_reviews_of_order();
+DROP TRIGGER verify_review_purchase ON Comments;
+DROP FUNCTION verify_review_purchase();
+DROP FUNCTION has_purchased(bigint, bigint);
+
+ALTER TABLE Comments DROP COLUMN verified_purchase;
+
+DROP INDEX comments_one_review;
+ALTER TABLE Comments DROP CONSTRAINT comments_rating_on_root;
+
+-- The ratings are restored after the triggers and the aggregate are reverted, so the
+-- restored ratings are counted again
+UPDATE Comments c SET rating = b.rating
+FROM CommentRatingsBackup b
+WHERE b.comment_id = c.id;
+
+DROP TABLE CommentRatingsBackup;
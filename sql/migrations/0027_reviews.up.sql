@@ -0,0 +1,122 @@
+-- The ratings removed below are kept, so the down migration restores them
+CREATE TABLE IF NOT EXISTS CommentRatingsBackup(
+    comment_id int8 PRIMARY KEY REFERENCES Comments(id) ON DELETE CASCADE,
+    rating int2 NOT NULL
+);
+
+-- The review is the root comment with the rating. The answers are the discussion only
+INSERT INTO CommentRatingsBackup (comment_id, rating)
+SELECT id, rating FROM Comments WHERE answer_on IS NOT NULL AND rating IS NOT NULL;
+
+-- The latest review of the user stays the review, the older ones become the discussion
+INSERT INTO CommentRatingsBackup (comment_id, rating)
+SELECT c.id, c.rating FROM Comments c
+WHERE c.answer_on IS NULL AND c.rating IS NOT NULL AND NOT c.is_deleted AND EXISTS (
+    SELECT 1 FROM Comments newer
+    WHERE newer.user_id = c.user_id
+        AND newer.product_id = c.product_id
+        AND newer.answer_on IS NULL
+        AND newer.rating IS NOT NULL
+        AND NOT newer.is_deleted
+        AND (newer.created_at, newer.id) > (c.created_at, c.id)
+);
+
+UPDATE Comments SET rating = NULL WHERE id IN (SELECT comment_id FROM CommentRatingsBackup);
+
+ALTER TABLE Comments ADD CONSTRAINT comments_rating_on_root CHECK (rating IS NULL OR answer_on IS NULL);
+
+CREATE UNIQUE INDEX comments_one_review ON Comments(user_id, product_id)
+WHERE answer_on IS NULL AND rating IS NOT NULL AND NOT is_deleted;
+
+-- verified_purchase is set when the author has the completed order with the product
+ALTER TABLE Comments ADD COLUMN verified_purchase boolean NOT NULL DEFAULT false;
+
+CREATE OR REPLACE FUNCTION has_purchased(buyer bigint, product bigint)
+RETURNS boolean AS $$
+    SELECT EXISTS (
+        SELECT 1 FROM OrderItems oi
+        JOIN Orders o ON o.id = oi.order_id
+        WHERE o.user_id = buyer AND o.status = 'completed' AND oi.product_id = product
+    );
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION verify_review_purchase()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.verified_purchase := NEW.answer_on IS NULL AND NEW.rating IS NOT NULL AND has_purchased(NEW.user_id, NEW.product_id);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER verify_review_purchase
+BEFORE INSERT OR UPDATE OF rating, answer_on ON Comments
+FOR EACH ROW
+EXECUTE FUNCTION verify_review_purchase();
+
+CREATE OR REPLACE FUNCTION verify_reviews_of_order()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE Comments SET verified_purchase = true
+    WHERE user_id = NEW.user_id
+        AND answer_on IS NULL
+        AND rating IS NOT NULL
+        AND NOT verified_purchase
+        AND product_id IN (SELECT product_id FROM OrderItems WHERE order_id = NEW.id);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER verify_reviews_of_order
+AFTER UPDATE OF status ON Orders
+FOR EACH ROW
+WHEN (NEW.status = 'completed' AND OLD.status <> 'completed')
+EXECUTE FUNCTION verify_reviews_of_order();
+
+UPDATE Comments SET verified_purchase = true
+WHERE answer_on IS NULL AND rating IS NOT NULL AND has_purchased(user_id, product_id);
+
+-- The verified ratings are aggregated separately, so the unverified ones can be excluded
+ALTER TABLE ProductRatings ADD COLUMN verified_count integer NOT NULL DEFAULT 0;
+ALTER TABLE ProductRatings ADD COLUMN verified_average numeric(5, 2) NOT NULL DEFAULT 0;
+
+CREATE OR REPLACE FUNCTION refresh_product_rating(product bigint)
+RETURNS void AS $$
+    INSERT INTO ProductRatings (product_id, rating_count, average, histogram, verified_count, verified_average)
+    SELECT
+        p.id,
+        COUNT(c.rating),
+        COALESCE(ROUND(AVG(c.rating), 2), 0),
+        ARRAY[
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 1),
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 2),
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 3),
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 4),
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 5)
+        ]::integer[],
+        COUNT(c.rating) FILTER (WHERE c.verified_purchase),
+        COALESCE(ROUND(AVG(c.rating) FILTER (WHERE c.verified_purchase), 2), 0)
+    FROM Products p
+    LEFT JOIN Comments c ON c.product_id = p.id
+        AND c.answer_on IS NULL
+        AND c.rating IS NOT NULL
+        AND NOT c.is_deleted
+    WHERE p.id = product
+    GROUP BY p.id
+    ON CONFLICT (product_id) DO UPDATE SET
+        rating_count = EXCLUDED.rating_count,
+        average = EXCLUDED.average,
+        histogram = EXCLUDED.histogram,
+        verified_count = EXCLUDED.verified_count,
+        verified_average = EXCLUDED.verified_average;
+$$ LANGUAGE sql;
+
+DROP TRIGGER sync_product_rating ON Comments;
+
+CREATE TRIGGER sync_product_rating
+AFTER INSERT OR DELETE OR UPDATE OF rating, answer_on, is_deleted, product_id, verified_purchase ON Comments
+FOR EACH ROW
+EXECUTE FUNCTION sync_product_rating();
+
+SELECT refresh_product_rating(product_id)
+FROM (SELECT DISTINCT product_id FROM Comments WHERE verified_purchase) verified;
@@ -0,0 +1,48 @@
+DELETE FROM RolePermissions WHERE role = 'Moderator';
+UPDATE Users SET role = 'Default' WHERE role = 'Moderator';
+DELETE FROM Roles WHERE name = 'Moderator';
+
+DROP TRIGGER sync_product_rating ON Comments;
+
+CREATE TRIGGER sync_product_rating
+AFTER INSERT OR DELETE OR UPDATE OF rating, answer_on, is_deleted, product_id, verified_purchase ON Comments
+FOR EACH ROW
+EXECUTE FUNCTION sync_product_rating();
+
+CREATE OR REPLACE FUNCTION refresh_product_rating(product bigint)
+RETURNS void AS $$
+    INSERT INTO ProductRatings (product_id, rating_count, average, histogram, verified_count, verified_average)
+    SELECT
+        p.id,
+        COUNT(c.rating),
+        COALESCE(ROUND(AVG(c.rating), 2), 0),
+        ARRAY[
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 1),
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 2),
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 3),
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 4),
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 5)
+        ]::integer[],
+        COUNT(c.rating) FILTER (WHERE c.verified_purchase),
+        COALESCE(ROUND(AVG(c.rating) FILTER (WHERE c.verified_purchase), 2), 0)
+    FROM Products p
+    LEFT JOIN Comments c ON c.product_id = p.id
+        AND c.answer_on IS NULL
+        AND c.rating IS NOT NULL
+        AND NOT c.is_deleted
+    WHERE p.id = product
+    GROUP BY p.id
+    ON CONFLICT (product_id) DO UPDATE SET
+        rating_count = EXCLUDED.rating_count,
+        average = EXCLUDED.average,
+        histogram = EXCLUDED.histogram,
+        verified_count = EXCLUDED.verified_count,
+        verified_average = EXCLUDED.verified_average;
+$$ LANGUAGE sql;
+
+DROP TABLE CommentReports;
+
+ALTER TABLE Comments DROP COLUMN moderation_status;
+
+ALTER TABLE Users DROP COLUMN comments_banned;
+ALTER TABLE Users DROP COLUMN created_at;
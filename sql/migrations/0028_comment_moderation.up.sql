@@ -0,0 +1,69 @@
+-- The accounts created before the migration have no creation time, so they are never pre-moderated
+ALTER TABLE Users ADD COLUMN created_at timestamptz DEFAULT NULL;
+ALTER TABLE Users ALTER COLUMN created_at SET DEFAULT now();
+ALTER TABLE Users ADD COLUMN comments_banned boolean NOT NULL DEFAULT false;
+
+-- pending comments wait for the pre-moderation, hidden ones are hidden by the staff.
+-- Both are not shown and not counted in the ratings
+ALTER TABLE Comments ADD COLUMN moderation_status text NOT NULL DEFAULT 'visible'
+    CHECK (moderation_status IN ('visible', 'pending', 'hidden'));
+
+CREATE INDEX comments_pending ON Comments(created_at) WHERE moderation_status = 'pending';
+
+CREATE TABLE IF NOT EXISTS CommentReports(
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    comment_id bigint NOT NULL REFERENCES Comments(id) ON DELETE CASCADE,
+    reporter_id integer NOT NULL REFERENCES Users(id) ON DELETE CASCADE,
+    reason text NOT NULL CHECK (reason IN ('spam', 'abuse', 'off_topic', 'illegal', 'other')),
+    note text DEFAULT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    -- The reports are resolved when the comment is hidden or restored
+    resolved_at timestamptz DEFAULT NULL,
+    resolved_by integer DEFAULT NULL REFERENCES Users(id) ON DELETE SET NULL,
+    UNIQUE (comment_id, reporter_id)
+);
+
+CREATE INDEX comment_reports_open ON CommentReports(comment_id) WHERE resolved_at IS NULL;
+
+CREATE OR REPLACE FUNCTION refresh_product_rating(product bigint)
+RETURNS void AS $$
+    INSERT INTO ProductRatings (product_id, rating_count, average, histogram, verified_count, verified_average)
+    SELECT
+        p.id,
+        COUNT(c.rating),
+        COALESCE(ROUND(AVG(c.rating), 2), 0),
+        ARRAY[
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 1),
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 2),
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 3),
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 4),
+            COUNT(*) FILTER (WHERE rating_stars(c.rating) = 5)
+        ]::integer[],
+        COUNT(c.rating) FILTER (WHERE c.verified_purchase),
+        COALESCE(ROUND(AVG(c.rating) FILTER (WHERE c.verified_purchase), 2), 0)
+    FROM Products p
+    LEFT JOIN Comments c ON c.product_id = p.id
+        AND c.answer_on IS NULL
+        AND c.rating IS NOT NULL
+        AND NOT c.is_deleted
+        AND c.moderation_status = 'visible'
+    WHERE p.id = product
+    GROUP BY p.id
+    ON CONFLICT (product_id) DO UPDATE SET
+        rating_count = EXCLUDED.rating_count,
+        average = EXCLUDED.average,
+        histogram = EXCLUDED.histogram,
+        verified_count = EXCLUDED.verified_count,
+        verified_average = EXCLUDED.verified_average;
+$$ LANGUAGE sql;
+
+DROP TRIGGER sync_product_rating ON Comments;
+
+CREATE TRIGGER sync_product_rating
+AFTER INSERT OR DELETE OR UPDATE OF rating, answer_on, is_deleted, product_id, verified_purchase, moderation_status ON Comments
+FOR EACH ROW
+EXECUTE FUNCTION sync_product_rating();
+
+INSERT INTO Roles (name) VALUES ('Moderator');
+
+INSERT INTO RolePermissions (role, permission) VALUES ('Moderator', 'comments:moderate');
@@ -0,0 +1,3 @@
+DROP INDEX comments_author_recent;
+
+ALTER TABLE Comments DROP COLUMN filter_flags;
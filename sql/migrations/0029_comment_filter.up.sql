@@ -0,0 +1,5 @@
+-- The reasons of the content filter to mask or hold the comment, shown to the moderators
+ALTER TABLE Comments ADD COLUMN filter_flags text[] NOT NULL DEFAULT '{}';
+
+-- The recent comments of the author are loaded for the duplicates and flood checks
+CREATE INDEX comments_author_recent ON Comments(user_id, created_at);
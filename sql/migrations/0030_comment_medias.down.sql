@@ -0,0 +1,3 @@
+UPDATE Comments SET media_ids = '{}' WHERE media_ids <> '{}';
+
+DROP TABLE CommentMedias;
@@ -0,0 +1,16 @@
+-- The images uploaded by the users for their comments. The media is attached to the comment
+-- when the comment is saved, the unattached medias can be attached to the next comment
+CREATE TABLE IF NOT EXISTS CommentMedias(
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    owner_id integer NOT NULL REFERENCES Users(id) ON DELETE CASCADE,
+    comment_id bigint DEFAULT NULL REFERENCES Comments(id) ON DELETE CASCADE,
+    url text NOT NULL,
+    thumbnail_url text NOT NULL,
+    type MediaType NOT NULL DEFAULT 'Image',
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX comment_medias_comment ON CommentMedias(comment_id);
+
+-- media_ids pointed to the product medias before, the comments could not attach them
+UPDATE Comments SET media_ids = '{}' WHERE media_ids <> '{}';
@@ -0,0 +1,4 @@
+DROP TRIGGER record_comment_revision ON Comments;
+DROP FUNCTION record_comment_revision();
+
+DROP TABLE CommentRevisions;
@@ -0,0 +1,28 @@
+-- The previous versions of the comments. written_at is the time the version was written,
+-- replaced_at is the time it was replaced by the edit or the deletion
+CREATE TABLE IF NOT EXISTS CommentRevisions(
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    comment_id bigint NOT NULL REFERENCES Comments(id) ON DELETE CASCADE,
+    comment_text text NOT NULL,
+    rating smallint DEFAULT NULL,
+    written_at timestamptz NOT NULL,
+    replaced_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX comment_revisions_comment ON CommentRevisions(comment_id);
+
+CREATE OR REPLACE FUNCTION record_comment_revision()
+RETURNS trigger AS $$
+BEGIN
+    INSERT INTO CommentRevisions (comment_id, comment_text, rating, written_at)
+    VALUES (OLD.id, OLD.comment_text, OLD.rating, COALESCE(OLD.updated_at, OLD.created_at));
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER record_comment_revision
+AFTER UPDATE OF comment_text, rating ON Comments
+FOR EACH ROW
+WHEN (OLD.comment_text IS DISTINCT FROM NEW.comment_text OR OLD.rating IS DISTINCT FROM NEW.rating)
+EXECUTE FUNCTION record_comment_revision();
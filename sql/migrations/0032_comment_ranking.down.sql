@@ -0,0 +1,3 @@
+DROP INDEX comments_product_roots;
+
+DROP FUNCTION wilson_lower_bound(bigint, bigint);
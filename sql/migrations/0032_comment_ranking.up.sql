@@ -0,0 +1,14 @@
+-- wilson_lower_bound is the lower bound of the 95% confidence interval of the share of the likes.
+-- The comment with 10 likes of 10 reactions ranks above the one with 1 like of 1 reaction
+CREATE OR REPLACE FUNCTION wilson_lower_bound(likes bigint, dislikes bigint)
+RETURNS double precision AS $$
+    SELECT CASE
+        WHEN likes + dislikes = 0 THEN 0
+        ELSE (
+            likes::double precision / (likes + dislikes) + 1.9208 / (likes + dislikes)
+            - 1.96 * sqrt(likes::double precision * dislikes / (likes + dislikes) + 0.9604) / (likes + dislikes)
+        ) / (1 + 3.8416 / (likes + dislikes))
+    END;
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE INDEX comments_product_roots ON Comments(product_id, created_at) WHERE answer_on IS NULL;
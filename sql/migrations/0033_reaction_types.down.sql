@@ -0,0 +1,23 @@
+DROP TRIGGER sync_reaction_counts ON CommentReactions;
+
+DROP FUNCTION sync_reaction_counts();
+DROP FUNCTION add_reaction_count(bigint, text, int);
+
+ALTER TABLE Comments DROP COLUMN reaction_counts;
+
+DROP INDEX comment_reactions_comment;
+
+-- The enum has only the likes and the dislikes, so the rollback loses the reaction types.
+-- The built-in reactions are mapped by their sentiment, the ones added by the moderators
+-- can't be mapped and are deleted
+UPDATE CommentReactions SET ty = 'like' WHERE ty IN ('heart', 'laugh', 'wow');
+UPDATE CommentReactions SET ty = 'dislike' WHERE ty = 'sad';
+DELETE FROM CommentReactions WHERE ty NOT IN ('like', 'dislike');
+
+ALTER TABLE CommentReactions DROP CONSTRAINT comment_reactions_type;
+
+CREATE TYPE ReactionType AS ENUM ('like', 'dislike');
+
+ALTER TABLE CommentReactions ALTER COLUMN ty TYPE ReactionType USING ty::ReactionType;
+
+DROP TABLE ReactionTypes;
@@ -0,0 +1,67 @@
+-- The reactions are configured in the table instead of the enum, so the new ones
+-- can be added and the old ones hidden without the migration
+CREATE TABLE IF NOT EXISTS ReactionTypes(
+    name text PRIMARY KEY CHECK (name ~ '^[a-z_]{1,32}$'),
+    emoji text NOT NULL,
+    position int NOT NULL DEFAULT 0,
+    active boolean NOT NULL DEFAULT true
+);
+
+INSERT INTO ReactionTypes (name, emoji, position) VALUES
+    ('like', '👍', 1),
+    ('dislike', '👎', 2),
+    ('heart', '❤️', 3),
+    ('laugh', '😂', 4),
+    ('wow', '😮', 5),
+    ('sad', '😢', 6);
+
+ALTER TABLE CommentReactions ALTER COLUMN ty TYPE text USING ty::text;
+ALTER TABLE CommentReactions ADD CONSTRAINT comment_reactions_type
+    FOREIGN KEY (ty) REFERENCES ReactionTypes(name) ON UPDATE CASCADE;
+
+DROP TYPE ReactionType;
+
+CREATE INDEX comment_reactions_comment ON CommentReactions(comment_id, added_at);
+
+-- reaction_counts is the amount of the reactions of every type, so the comments are
+-- loaded without counting the reactions
+ALTER TABLE Comments ADD COLUMN reaction_counts jsonb NOT NULL DEFAULT '{}';
+
+UPDATE Comments c SET reaction_counts = r.counts
+FROM (
+    SELECT comment_id, jsonb_object_agg(ty, amount) AS counts
+    FROM (SELECT comment_id, ty, COUNT(*) AS amount FROM CommentReactions GROUP BY comment_id, ty) t
+    GROUP BY comment_id
+) r
+WHERE c.id = r.comment_id;
+
+-- add_reaction_count changes the amount of the reactions of the type by the delta.
+-- The update locks the comment row, so the concurrent reactions don't lose the counts
+CREATE OR REPLACE FUNCTION add_reaction_count(comment bigint, ty text, delta int)
+RETURNS void AS $$
+    UPDATE Comments SET reaction_counts = CASE
+        WHEN COALESCE((reaction_counts->>ty)::bigint, 0) + delta <= 0 THEN reaction_counts - ty
+        ELSE jsonb_set(reaction_counts, ARRAY[ty], to_jsonb(COALESCE((reaction_counts->>ty)::bigint, 0) + delta))
+    END
+    WHERE id = comment;
+$$ LANGUAGE sql;
+
+CREATE OR REPLACE FUNCTION sync_reaction_counts()
+RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM add_reaction_count(OLD.comment_id, OLD.ty, -1);
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM add_reaction_count(NEW.comment_id, NEW.ty, 1);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER sync_reaction_counts
+AFTER INSERT OR DELETE OR UPDATE OF ty, comment_id ON CommentReactions
+FOR EACH ROW
+EXECUTE FUNCTION sync_reaction_counts();
@@ -0,0 +1,18 @@
+DELETE FROM Notifications WHERE kind = 'question_answered';
+
+DROP TRIGGER enqueue_answer_notification ON QuestionAnswers;
+DROP TRIGGER sync_answer_upvotes ON AnswerUpvotes;
+DROP TRIGGER sync_question_answers ON QuestionAnswers;
+DROP TRIGGER mark_answer_author ON QuestionAnswers;
+
+DROP FUNCTION enqueue_answer_notification();
+DROP FUNCTION sync_answer_upvotes();
+DROP FUNCTION sync_question_answers();
+DROP FUNCTION mark_answer_author();
+
+DROP TABLE AnswerUpvotes;
+
+ALTER TABLE ProductQuestions DROP CONSTRAINT product_questions_accepted_answer;
+
+DROP TABLE QuestionAnswers;
+DROP TABLE ProductQuestions;
@@ -0,0 +1,134 @@
+-- The questions about the product are kept apart from the reviews, so they don't
+-- affect the rating and are listed separately
+CREATE TABLE IF NOT EXISTS ProductQuestions(
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES Users(id) ON DELETE CASCADE,
+    product_id integer NOT NULL REFERENCES Products(id) ON DELETE CASCADE,
+    question_text text NOT NULL,
+    -- notify_channel is where the asker is notified about the answers
+    notify_channel text NOT NULL DEFAULT 'in_app' CHECK (notify_channel IN ('email', 'in_app')),
+    accepted_answer_id bigint DEFAULT NULL,
+    answers_count integer NOT NULL DEFAULT 0,
+    is_deleted boolean NOT NULL DEFAULT false,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz DEFAULT NULL
+);
+
+CREATE INDEX product_questions_product ON ProductQuestions(product_id, created_at) WHERE NOT is_deleted;
+
+-- staff and verified_purchase are set by the trigger when the answer is written
+CREATE TABLE IF NOT EXISTS QuestionAnswers(
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    question_id bigint NOT NULL REFERENCES ProductQuestions(id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES Users(id) ON DELETE CASCADE,
+    answer_text text NOT NULL,
+    staff boolean NOT NULL DEFAULT false,
+    verified_purchase boolean NOT NULL DEFAULT false,
+    upvotes integer NOT NULL DEFAULT 0,
+    is_deleted boolean NOT NULL DEFAULT false,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz DEFAULT NULL
+);
+
+CREATE INDEX question_answers_question ON QuestionAnswers(question_id) WHERE NOT is_deleted;
+
+ALTER TABLE ProductQuestions ADD CONSTRAINT product_questions_accepted_answer
+    FOREIGN KEY (accepted_answer_id) REFERENCES QuestionAnswers(id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS AnswerUpvotes(
+    answer_id bigint NOT NULL REFERENCES QuestionAnswers(id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES Users(id) ON DELETE CASCADE,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (answer_id, user_id)
+);
+
+-- The staff are the users whose role has any permission
+CREATE OR REPLACE FUNCTION mark_answer_author()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.staff := EXISTS (
+        SELECT 1 FROM Users u
+        JOIN RolePermissions rp ON rp.role = u.role
+        WHERE u.id = NEW.user_id
+    );
+
+    NEW.verified_purchase := has_purchased(
+        NEW.user_id, (SELECT product_id FROM ProductQuestions WHERE id = NEW.question_id)
+    );
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER mark_answer_author
+BEFORE INSERT ON QuestionAnswers
+FOR EACH ROW
+EXECUTE FUNCTION mark_answer_author();
+
+-- The count is changed by the delta instead of being recounted, so the concurrent
+-- answers don't lose it. The deleted answer stops being accepted
+CREATE OR REPLACE FUNCTION sync_question_answers()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' AND NOT NEW.is_deleted THEN
+        UPDATE ProductQuestions SET answers_count = answers_count + 1 WHERE id = NEW.question_id;
+    ELSIF TG_OP = 'UPDATE' AND NEW.is_deleted AND NOT OLD.is_deleted THEN
+        UPDATE ProductQuestions SET
+            answers_count = answers_count - 1,
+            accepted_answer_id = NULLIF(accepted_answer_id, NEW.id)
+        WHERE id = NEW.question_id;
+    ELSIF TG_OP = 'UPDATE' AND OLD.is_deleted AND NOT NEW.is_deleted THEN
+        UPDATE ProductQuestions SET answers_count = answers_count + 1 WHERE id = NEW.question_id;
+    ELSIF TG_OP = 'DELETE' AND NOT OLD.is_deleted THEN
+        UPDATE ProductQuestions SET answers_count = answers_count - 1 WHERE id = OLD.question_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER sync_question_answers
+AFTER INSERT OR DELETE OR UPDATE OF is_deleted ON QuestionAnswers
+FOR EACH ROW
+EXECUTE FUNCTION sync_question_answers();
+
+CREATE OR REPLACE FUNCTION sync_answer_upvotes()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE QuestionAnswers SET upvotes = upvotes + 1 WHERE id = NEW.answer_id;
+    ELSE
+        UPDATE QuestionAnswers SET upvotes = upvotes - 1 WHERE id = OLD.answer_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER sync_answer_upvotes
+AFTER INSERT OR DELETE ON AnswerUpvotes
+FOR EACH ROW
+EXECUTE FUNCTION sync_answer_upvotes();
+
+-- The asker is notified about every answer except their own. The payload is the state
+-- of the product with the question and the answer
+CREATE OR REPLACE FUNCTION enqueue_answer_notification()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO Notifications (user_id, product_id, kind, channel, payload, dedup_key)
+    SELECT q.user_id, q.product_id, 'question_answered', q.notify_channel,
+        notification_payload(p, p.price) || jsonb_build_object('question_id', q.id, 'answer_id', NEW.id),
+        'question_answered:' || NEW.id
+    FROM ProductQuestions q
+    JOIN Products p ON p.id = q.product_id
+    WHERE q.id = NEW.question_id AND q.user_id <> NEW.user_id AND NOT q.is_deleted
+    ON CONFLICT (dedup_key) DO NOTHING;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER enqueue_answer_notification
+AFTER INSERT ON QuestionAnswers
+FOR EACH ROW
+EXECUTE FUNCTION enqueue_answer_notification();
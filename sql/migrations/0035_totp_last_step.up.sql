@@ -0,0 +1,3 @@
+-- The time step of the last accepted TOTP code. The codes of this and the
+-- previous steps are rejected, so the code can't be used twice
+ALTER TABLE Users ADD COLUMN totp_last_step bigint DEFAULT NULL;
@@ -0,0 +1,14 @@
+CREATE OR REPLACE FUNCTION lowest_price(product integer, cur char(3), since timestamptz)
+RETURNS numeric AS $$
+    SELECT MIN(price) FROM PriceHistory
+    WHERE product_id = product AND currency = cur AND (
+        changed_at >= since OR id = (
+            SELECT id FROM PriceHistory
+            WHERE product_id = product AND changed_at < since
+            ORDER BY changed_at DESC, id DESC
+            LIMIT 1
+        )
+    );
+$$ LANGUAGE sql STABLE;
+
+DROP FUNCTION lowest_prior_price(integer, char(3), interval);
@@ -0,0 +1,24 @@
+-- lowest_prior_price returns the lowest price of the product in the currency during the period
+-- before the latest price change. The current price is not counted, so the product which was
+-- never repriced has no prior price. The price which was in effect at the start of the period
+-- is also counted
+CREATE OR REPLACE FUNCTION lowest_prior_price(product integer, cur char(3), period interval)
+RETURNS numeric AS $$
+    WITH current_entry AS (
+        SELECT id, changed_at FROM PriceHistory
+        WHERE product_id = product
+        ORDER BY changed_at DESC, id DESC
+        LIMIT 1
+    )
+    SELECT MIN(h.price) FROM PriceHistory h, current_entry c
+    WHERE h.product_id = product AND h.currency = cur AND h.id <> c.id AND (
+        (h.changed_at >= c.changed_at - period AND (h.changed_at, h.id) < (c.changed_at, c.id)) OR h.id = (
+            SELECT id FROM PriceHistory
+            WHERE product_id = product AND changed_at < c.changed_at - period
+            ORDER BY changed_at DESC, id DESC
+            LIMIT 1
+        )
+    );
+$$ LANGUAGE sql STABLE;
+
+DROP FUNCTION lowest_price(integer, char(3), timestamptz);